@@ -35,6 +35,13 @@ const PRIMITIVE_TYPES = [
   { value: 'string_array', label: 'string[]', is_custom: false },
   { value: 'boolean', label: 'bool', is_custom: false },
   { value: 'double', label: 'double', is_custom: false },
+  { value: 'double_array', label: 'double[]', is_custom: false },
+  { value: 'long', label: 'long', is_custom: false },
+  { value: 'char', label: 'char', is_custom: false },
+  { value: 'integer_matrix', label: 'int[][]', is_custom: false },
+  { value: 'string_matrix', label: 'string[][]', is_custom: false },
+  { value: 'list<list<char>>', label: 'char[][]', is_custom: false },
+  { value: 'map<string,int>', label: 'map<string, int>', is_custom: false },
 ];
 
 const VALIDATION_TYPES = [
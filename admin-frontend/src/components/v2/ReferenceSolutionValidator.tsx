@@ -45,6 +45,10 @@ const ALL_LANGUAGES = [
     { value: 'c++', label: 'C++' },
     { value: 'c', label: 'C' },
     { value: 'go', label: 'Go' },
+    { value: 'rust', label: 'Rust' },
+    { value: 'typescript', label: 'TypeScript' },
+    { value: 'kotlin', label: 'Kotlin' },
+    { value: 'csharp', label: 'C#' },
 ];
 
 export const ReferenceSolutionValidator: React.FC<ReferenceSolutionValidatorProps> = ({ problemId, supportedLanguages, onValidationSuccess }) => {
@@ -95,6 +95,8 @@ export const ProblemWizard: React.FC = () => {
                     expected_output: JSON.stringify(tc.expected_output)
                 })) || [{ input: '[]', expected_output: 'null', is_sample: true }],
                 validation_type: p.validation_type || 'EXACT',
+                checker_language: p.checker_language || '',
+                checker_code: p.checker_code || '',
                 selected_languages: p.boilerplates?.map((b: any) => b.language?.language_id || b.language_id) || [],
                 boilerplates: p.boilerplates || [],
                 tag_ids: p.tags?.map((t: any) => t.id) || [],
@@ -153,6 +155,8 @@ export const ProblemWizard: React.FC = () => {
                 parameters: formData.parameters,
                 test_cases: formattedTestCases,
                 validation_type: formData.validation_type,
+                checker_language: formData.checker_language,
+                checker_code: formData.checker_code,
                 status: 'draft' as 'draft',
                 visibility: 'public' as 'public',
                 is_active: true,
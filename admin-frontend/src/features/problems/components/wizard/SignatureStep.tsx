@@ -30,6 +30,13 @@ const PRIMITIVE_TYPES = [
     { value: 'string_array', label: 'string[]', is_custom: false },
     { value: 'boolean', label: 'bool', is_custom: false },
     { value: 'double', label: 'double', is_custom: false },
+    { value: 'double_array', label: 'double[]', is_custom: false },
+    { value: 'long', label: 'long', is_custom: false },
+    { value: 'char', label: 'char', is_custom: false },
+    { value: 'integer_matrix', label: 'int[][]', is_custom: false },
+    { value: 'string_matrix', label: 'string[][]', is_custom: false },
+    { value: 'list<list<char>>', label: 'char[][]', is_custom: false },
+    { value: 'map<string,int>', label: 'map<string, int>', is_custom: false },
 ];
 
 export const SignatureStep: React.FC<SignatureStepProps> = ({ data, onChange }) => {
@@ -186,6 +193,7 @@ export const SignatureStep: React.FC<SignatureStepProps> = ({ data, onChange })
                             <MenuItem value="EXACT">Exact Match</MenuItem>
                             <MenuItem value="UNORDERED">Unordered Array (Sets)</MenuItem>
                             <MenuItem value="SUBSET">Subset Match</MenuItem>
+                            <MenuItem value="CUSTOM">Custom Checker</MenuItem>
                         </TextField>
 
                         <Box sx={{ display: 'flex', gap: 2 }}>
@@ -203,6 +211,30 @@ export const SignatureStep: React.FC<SignatureStepProps> = ({ data, onChange })
                             />
                         </Box>
                     </div>
+
+                    {data.validation_type === 'CUSTOM' && (
+                        <Stack spacing={2} sx={{ mt: 3 }}>
+                            <Typography variant="body2" color="text.secondary">
+                                The checker reads {'{"input", "expected", "actual"}'} as JSON from stdin and exits 0 to accept or 1 to reject. Anything it prints is shown as the verdict message.
+                            </Typography>
+                            <TextField
+                                label="Checker Language"
+                                value={data.checker_language || ''}
+                                onChange={(e) => onChange({ checker_language: e.target.value })}
+                                helperText="Language slug, e.g. python"
+                                required
+                            />
+                            <TextField
+                                label="Checker Code"
+                                value={data.checker_code || ''}
+                                onChange={(e) => onChange({ checker_code: e.target.value })}
+                                multiline
+                                minRows={8}
+                                required
+                                InputProps={{ sx: { fontFamily: 'monospace' } }}
+                            />
+                        </Stack>
+                    )}
                 </Paper>
             </Box>
         </Stack>
@@ -37,6 +37,10 @@ export function useLanguageManager(
       cpp: "cpp",
       python: "python",
       java: "java",
+      rust: "rust",
+      typescript: "typescript",
+      kotlin: "kotlin",
+      csharp: "csharp",
     };
     return map[langName.toLowerCase()] || "plaintext";
   }, []);
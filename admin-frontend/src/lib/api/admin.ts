@@ -104,6 +104,11 @@ export const adminProblemApi = {
   v2RegenerateBoilerplates: (id: string | number) =>
     axiosInstance.post<SimpleResponse>(`/admin/problems/${id}/boilerplates`),
 
+  v2PreviewHarness: (id: string | number, language: string) =>
+    axiosInstance.get<Response<{ harness: string; version: string }>>(`/admin/problems/${id}/harness`, {
+      params: { language }
+    }),
+
   getCustomTypes: () => axiosInstance.get<Response<{ id: number; name: string; description: string }[]>>('/admin/custom-types'),
 
   getTags: () => axiosInstance.get<Response<Tag[]>>('/tags'),
@@ -44,10 +44,34 @@ export interface Category {
   updated_at: string
 }
 
+// Limits on the values a parameter takes in test cases
+export interface ParamConstraints {
+  min?: number
+  max?: number
+  min_length?: number
+  max_length?: number
+  unique?: boolean
+  sorted?: 'asc' | 'desc'
+}
+
 export interface Parameter {
   name: string
   type: string
   is_custom: boolean
+  constraints?: ParamConstraints
+}
+
+// A method of the class a design problem has the user implement
+export interface DesignMethod {
+  name: string
+  parameters: Parameter[]
+  return_type: string // "void" when it returns nothing
+}
+
+export interface Design {
+  class_name: string
+  constructor: Parameter[]
+  methods: DesignMethod[]
 }
 
 export interface Problem {
@@ -69,13 +93,25 @@ export interface Problem {
   acceptance_rate: number
   total_submissions: number
   total_accepted: number
+  judge_mode?: "function" | "design" | "stdio" | "interactive"
+  interactor_language?: string
+  interactor_code?: string
+  query_limit?: number
 
   // V2 Fields
   function_name?: string
   return_type?: string
   parameters?: Parameter[]
+  design?: Design
   test_cases?: TestCase[]
   validation_type?: string
+  checker_language?: string
+  checker_code?: string
+  generator_language?: string
+  generator_code?: string
+  validator_language?: string
+  validator_code?: string
+  subtasks?: Subtask[]
 
   tags?: Tag[]
   categories?: Category[]
@@ -91,10 +127,43 @@ export interface TestCase {
   is_hidden: boolean;
   is_sample: boolean;
   order: number;
+  source?: "manual" | "generated" | "stress";
+  generator_seed?: number;
+  generator_size?: number;
+  reference_language_id?: number;
+  subtask?: string;
+  // Set when the value is too large to keep inline; it is then served by
+  // GET /admin/test-cases/{id}/data/{field}
+  input_key?: string;
+  expected_output_key?: string;
   created_at: string;
   updated_at: string;
 }
 
+// A group of test cases worth points, scored only once its tests and the
+// subtasks it depends on all pass
+export interface Subtask {
+  name: string
+  points: number
+  depends_on?: string[]
+}
+
+export interface SubtaskResult {
+  name: string
+  points: number
+  score: number
+  passed: number
+  total: number
+  status: "passed" | "failed" | "skipped"
+}
+
+// What is wrong with the input of one test case
+export interface TestCaseViolation {
+  test_case: number
+  test_case_id?: number
+  errors: string[]
+}
+
 export interface TrendingProblem {
   id: number
   title: string
@@ -139,6 +208,148 @@ export interface PistonExecution {
   problem?: Problem
 }
 
+export type ContestScoring = 'icpc' | 'ioi'
+
+export interface ContestProblem {
+  id: number
+  contest_id: number
+  problem_id: number
+  label: string
+  points: number
+  order: number
+  problem?: Pick<Problem, 'id' | 'title' | 'slug' | 'difficulty'>
+}
+
+export interface Contest {
+  id: number
+  slug: string
+  title: string
+  description: string
+  start_time: string
+  end_time: string
+  scoring: ContestScoring
+  penalty_minutes: number
+  // The public scoreboard hides results for the last freeze_minutes, until unfrozen
+  freeze_minutes: number
+  unfrozen: boolean
+  visibility: 'public' | 'private'
+  created_by: number
+  created_at: string
+  updated_at: string
+  problems?: ContestProblem[]
+}
+
+export interface ContestRegistration {
+  id: number
+  contest_id: number
+  user_id: number
+  registered_at: string
+  user?: Pick<User, 'id' | 'username' | 'email'>
+}
+
+export interface ScoreboardCell {
+  problem_id: number
+  attempts: number
+  pending: number
+  solved: boolean
+  solved_at?: number
+  first_to_solve?: boolean
+  score: number
+}
+
+export interface ScoreboardRow {
+  rank: number
+  user_id: number
+  username: string
+  solved: number
+  penalty: number
+  score: number
+  cells: ScoreboardCell[]
+}
+
+export interface Scoreboard {
+  contest_id: number
+  scoring: ContestScoring
+  frozen: boolean
+  freeze_time?: string
+  problems: { problem_id: number; label: string; title: string; points: number }[]
+  rows: ScoreboardRow[]
+}
+
+// A rejudge judges the submissions matching its filter again, on the bulk lane
+export interface Rejudge {
+  id: number
+  submission_id?: number
+  problem_id?: number
+  language_id?: number
+  from?: string
+  to?: string
+  total: number
+  judged: number
+  changed: number
+  created_by: number
+  created_at: string
+  finished_at?: string
+}
+
+// A submission's verdict before and after a rejudge
+export interface RejudgeResult {
+  id: number
+  rejudge_id: number
+  submission_id: number
+  user_id: number
+  problem_id: number
+  old_status: string
+  old_score: number
+  old_passed_test_cases: number
+  new_status?: string // unset until judged again
+  new_score: number
+  new_passed_test_cases: number
+  judged_at?: string
+  created_at: string
+}
+
+export interface RejudgeDetail extends Rejudge {
+  results: RejudgeResult[]
+}
+
+export interface SubmissionTestResult {
+  test_id: number
+  input: string
+  expected_output: string
+  actual_output: string
+  status: string
+  time_ms: number
+  memory_kb: number
+  is_sample: boolean
+  error?: string
+  stdout?: string
+  subtask?: string
+}
+
+// One attempt at judging a submission, from a worker retry or a rejudge
+export interface SubmissionJudgement {
+  id: number
+  submission_id: number
+  rejudge_id?: number
+  worker_id: string
+  executor: string
+  harness_version: string
+  queued_at?: string
+  started_at: string
+  finished_at: string
+  status: string
+  error_message?: string
+  score: number
+  passed_test_cases: number
+  total_test_cases: number
+  runtime: number
+  memory: number
+  // Left out when listing a submission's judgements
+  test_case_results?: SubmissionTestResult[]
+  subtask_results?: SubtaskResult[]
+  created_at: string
+}
 
 export * from './request'
 export * from './response'
@@ -19,12 +19,24 @@ export interface CreateOrUpdateProblemRequest {
   constraints: string;
   status: "draft" | "published";
   is_active: boolean;
+  judge_mode?: "function" | "design" | "stdio" | "interactive";
+  interactor_language?: string;
+  interactor_code?: string;
+  query_limit?: number;
   // V2 Fields
   function_name?: string;
   return_type?: string;
   parameters?: any[];
+  design?: any;
   test_cases?: any[];
   validation_type?: string;
+  checker_language?: string;
+  checker_code?: string;
+  generator_language?: string;
+  generator_code?: string;
+  validator_language?: string;
+  validator_code?: string;
+  subtasks?: { name: string; points: number; depends_on?: string[] }[];
   selected_languages?: string[];
 
   tag_ids?: number[];
@@ -38,4 +50,27 @@ export interface CreateTestCaseRequest {
   is_hidden?: boolean;
   is_sample?: boolean;
   order?: number;
-}
\ No newline at end of file
+  subtask?: string;
+}
+
+export interface CreateContestRequest {
+  title: string;
+  slug?: string;
+  description?: string;
+  start_time: string;
+  end_time: string;
+  scoring?: 'icpc' | 'ioi';
+  penalty_minutes?: number;
+  freeze_minutes?: number;
+  visibility?: 'public' | 'private';
+  problems: { problem_id: number; label: string; points?: number }[];
+}
+
+// Every field set narrows the submissions rejudged; at least one is required
+export interface RejudgeRequest {
+  submission_id?: number;
+  problem_id?: number;
+  language_id?: number;
+  from?: string;
+  to?: string;
+}
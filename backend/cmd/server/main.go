@@ -88,6 +88,12 @@ func main() {
 		&domain.CustomType{},
 		&domain.TypeImplementation{},
 		&domain.PistonExecution{},
+		&domain.Contest{},
+		&domain.ContestProblem{},
+		&domain.ContestRegistration{},
+		&domain.Rejudge{},
+		&domain.RejudgeResult{},
+		&domain.SubmissionJudgement{},
 	); err != nil {
 		log.Fatal("Failed to run auto migrations", zap.Error(err))
 	}
@@ -8,11 +8,13 @@ import (
 	"syscall"
 
 	"github.com/joho/godotenv"
-	"github.com/prabalesh/loco/backend/internal/infrastructure/piston"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/blobstore"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/worker"
 	"github.com/prabalesh/loco/backend/internal/repository/postgres"
 	"github.com/prabalesh/loco/backend/internal/services/codegen"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
 	"github.com/prabalesh/loco/backend/internal/usecase"
 	"github.com/prabalesh/loco/backend/pkg/config"
 	"github.com/prabalesh/loco/backend/pkg/database"
@@ -66,16 +68,27 @@ func main() {
 	achievementRepo := postgres.NewAchievementRepository(db)
 	userRepo := postgres.NewUserRepository(db)
 	boilerplateRepo := postgres.NewBoilerplateRepository(db)
+	customTypeRepo := postgres.NewCustomTypeRepository(db.DB)
 	typeImplementationRepo := postgres.NewTypeImplementationRepository(db.DB)
 	referenceSolutionRepo := postgres.NewReferenceSolutionRepository(db)
+	rejudgeRepo := postgres.NewRejudgeRepository(db)
+	judgementRepo := postgres.NewSubmissionJudgementRepository(db)
 
 	pistonExecutionRepo := postgres.NewPistonExecutionRepository(db)
 
 	// 6. Initialize Services
-	pistonService := piston.NewPistonService(cfg, pistonExecutionRepo, loggers)
+	codeExecutor, err := executor.New(cfg, pistonExecutionRepo, loggers)
+	if err != nil {
+		loggers.Fatal("Failed to initialize code executor", zap.Error(err))
+	}
 	jobQueue := queue.NewJobQueue(redisClient, loggers)
-	codeGenService := codegen.NewCodeGenService(typeImplementationRepo)
+	codeGenService := codegen.NewCodeGenService(customTypeRepo, typeImplementationRepo)
 	boilerplateService := codegen.NewBoilerplateService(boilerplateRepo, languageRepo, testCaseRepo, codeGenService)
+	testDataStore, err := blobstore.New(cfg)
+	if err != nil {
+		loggers.Fatal("Failed to initialize test data store", zap.Error(err))
+	}
+	testDataService := testdata.NewTestDataService(testDataStore, cfg.TestData.InlineLimitBytes)
 
 	achievementUsecase := usecase.NewAchievementUsecase(
 		achievementRepo,
@@ -95,9 +108,12 @@ func main() {
 		languageRepo,
 		problemLanguageRepo,
 		referenceSolutionRepo,
-		pistonService,
+		codeExecutor,
 		boilerplateService,
+		testDataService,
 		userProblemStatsRepo,
+		rejudgeRepo,
+		judgementRepo,
 		loggers,
 		redisClient.Client,
 		cfg,
@@ -110,6 +126,14 @@ func main() {
 		loggers,
 	)
 
+	reaper := worker.NewReaper(
+		jobQueue,
+		submissionRepo,
+		redisClient.Client,
+		loggers,
+		cfg,
+	)
+
 	// 8. Start Worker
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -122,6 +146,10 @@ func main() {
 		achievementWorker.Start(ctx)
 	}()
 
+	go func() {
+		reaper.Start(ctx)
+	}()
+
 	// Graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -130,6 +158,7 @@ func main() {
 	loggers.Info("Shutting down worker...")
 	submissionWorker.Stop()
 	achievementWorker.Stop()
+	reaper.Stop()
 	cancel()
 	loggers.Info("Worker stopped")
 }
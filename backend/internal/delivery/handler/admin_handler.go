@@ -9,6 +9,7 @@ import (
 	"github.com/prabalesh/loco/backend/internal/delivery/middleware"
 	"github.com/prabalesh/loco/backend/internal/domain"
 	"github.com/prabalesh/loco/backend/internal/domain/dto"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
 	"github.com/prabalesh/loco/backend/internal/usecase"
 	"go.uber.org/zap"
 )
@@ -199,3 +200,78 @@ func (h *AdminHandler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
 		Data:  submissions,
 	})
 }
+
+// ListSubmissionJudgements - Get every attempt at judging a submission
+func (h *AdminHandler) ListSubmissionJudgements(w http.ResponseWriter, r *http.Request) {
+	submissionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid submission id")
+		return
+	}
+
+	judgements, err := h.adminUsecase.ListSubmissionJudgements(submissionID)
+	if err != nil {
+		if err.Error() == "submission not found" {
+			RespondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, judgements)
+}
+
+// GetJudgement - Get an attempt at judging a submission with its test results
+func (h *AdminHandler) GetJudgement(w http.ResponseWriter, r *http.Request) {
+	judgementID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid judgement id")
+		return
+	}
+
+	judgement, err := h.adminUsecase.GetJudgement(judgementID)
+	if err != nil {
+		RespondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, judgement)
+}
+
+// ListDeadSubmissions - Get submission jobs that exhausted their retries
+func (h *AdminHandler) ListDeadSubmissions(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	jobs, total, err := h.adminUsecase.ListDeadSubmissions(page, limit)
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	RespondPaginatedJSON(w, http.StatusOK, PaginatedResponse[[]queue.SubmissionJob]{
+		Total: int(total),
+		Page:  page,
+		Limit: limit,
+		Data:  jobs,
+	})
+}
+
+// ReplayDeadSubmission - Re-enqueue a dead-lettered submission
+func (h *AdminHandler) ReplayDeadSubmission(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := middleware.GetUserID(r.Context())
+
+	submissionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid submission id")
+		return
+	}
+
+	if err := h.adminUsecase.ReplayDeadSubmission(adminID, submissionID); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{"message": "submission re-enqueued"})
+}
@@ -37,6 +37,7 @@ type GenerateStubRequest struct {
 	FunctionName string                   `json:"function_name"`
 	ReturnType   domain.GenericType       `json:"return_type"`
 	Parameters   []domain.SchemaParameter `json:"parameters"`
+	Design       *domain.DesignSchema     `json:"design,omitempty"` // In place of the function, for design problems
 	LanguageSlug string                   `json:"language_slug"`
 }
 
@@ -56,6 +57,7 @@ func (h *CodeGenHandler) GenerateStub(w http.ResponseWriter, r *http.Request) {
 		FunctionName: req.FunctionName,
 		ReturnType:   req.ReturnType,
 		Parameters:   req.Parameters,
+		Design:       req.Design,
 	}
 
 	stubCode, err := h.codeGenService.GenerateStubCode(signature, req.LanguageSlug)
@@ -102,21 +104,20 @@ func (h *CodeGenHandler) GetProblemStub(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		if problem.FunctionName == nil || problem.ReturnType == nil || problem.Parameters == nil {
-			RespondError(w, http.StatusBadRequest, "Problem signature not defined")
+		// Whole-program problems start from an empty editor
+		if problem.IsWholeProgram() {
+			RespondJSON(w, http.StatusOK, GenerateStubResponse{StubCode: ""})
 			return
 		}
 
-		var params []domain.SchemaParameter
-		if err := json.Unmarshal([]byte(*problem.Parameters), &params); err != nil {
-			RespondError(w, http.StatusInternalServerError, "Failed to parse problem parameters")
+		signature, err := problem.Schema()
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "Problem signature not defined")
 			return
 		}
-
-		signature := domain.ProblemSchema{
-			FunctionName: *problem.FunctionName,
-			ReturnType:   domain.GenericType(*problem.ReturnType),
-			Parameters:   params,
+		if signature.Design != nil && !h.codeGenService.SupportsDesign(languageSlug) {
+			RespondError(w, http.StatusBadRequest, "Design problems are not supported in this language")
+			return
 		}
 
 		testCases, err := h.testCaseRepo.GetByProblemID(problemID)
@@ -157,3 +158,87 @@ func (h *CodeGenHandler) GetProblemBoilerplates(w http.ResponseWriter, r *http.R
 
 	RespondJSON(w, http.StatusOK, boilerplates)
 }
+
+type PreviewHarnessRequest struct {
+	FunctionName   string                   `json:"function_name"`
+	ReturnType     domain.GenericType       `json:"return_type"`
+	Parameters     []domain.SchemaParameter `json:"parameters"`
+	Design         *domain.DesignSchema     `json:"design,omitempty"` // In place of the function, for design problems
+	LanguageSlug   string                   `json:"language_slug"`
+	ValidationType string                   `json:"validation_type"`
+	UserCode       string                   `json:"user_code"` // Optional, the {USER_CODE} placeholder is kept when empty
+}
+
+type PreviewHarnessResponse struct {
+	Harness string `json:"harness"`
+	Version string `json:"version"` // Template the harness was rendered from
+}
+
+// POST /api/v2/codegen/harness - Preview the harness rendered for a signature
+func (h *CodeGenHandler) PreviewHarness(w http.ResponseWriter, r *http.Request) {
+	var req PreviewHarnessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	signature := domain.ProblemSchema{
+		FunctionName: req.FunctionName,
+		ReturnType:   req.ReturnType,
+		Parameters:   req.Parameters,
+		Design:       req.Design,
+	}
+	h.respondHarnessPreview(w, signature, req.LanguageSlug, req.ValidationType, req.UserCode)
+}
+
+// GET /api/v2/admin/problems/{id}/harness?language={language}
+func (h *CodeGenHandler) PreviewProblemHarness(w http.ResponseWriter, r *http.Request) {
+	problemID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid problem ID")
+		return
+	}
+
+	languageSlug := r.URL.Query().Get("language")
+	if languageSlug == "" {
+		languageSlug = "python" // default
+	}
+
+	problem, err := h.problemRepo.GetByID(problemID)
+	if err != nil {
+		RespondError(w, http.StatusNotFound, "Problem not found")
+		return
+	}
+
+	if problem.IsWholeProgram() {
+		RespondError(w, http.StatusBadRequest, "Whole-program problems have no harness")
+		return
+	}
+
+	signature, err := problem.Schema()
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Problem signature not defined")
+		return
+	}
+	h.respondHarnessPreview(w, signature, languageSlug, problem.ValidationType, "")
+}
+
+func (h *CodeGenHandler) respondHarnessPreview(w http.ResponseWriter, signature domain.ProblemSchema, languageSlug, validationType, userCode string) {
+	if validationType == "" {
+		validationType = "EXACT"
+	}
+	if userCode == "" {
+		userCode = "{USER_CODE}"
+	}
+
+	harness, err := h.codeGenService.GenerateTestHarness(signature, userCode, languageSlug, nil, validationType)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, PreviewHarnessResponse{
+		Harness: harness,
+		Version: h.codeGenService.HarnessVersion(languageSlug),
+	})
+}
@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/prabalesh/loco/backend/internal/delivery/middleware"
+	"github.com/prabalesh/loco/backend/internal/domain/dto"
+	"github.com/prabalesh/loco/backend/internal/domain/uerror"
+	"github.com/prabalesh/loco/backend/internal/usecase"
+	"go.uber.org/zap"
+)
+
+type ContestHandler struct {
+	contestUsecase *usecase.ContestUsecase
+	logger         *zap.Logger
+}
+
+func NewContestHandler(contestUsecase *usecase.ContestUsecase, logger *zap.Logger) *ContestHandler {
+	return &ContestHandler{
+		contestUsecase: contestUsecase,
+		logger:         logger,
+	}
+}
+
+// respondContestError maps the errors of the contest usecase to responses
+func (h *ContestHandler) respondContestError(w http.ResponseWriter, err error) {
+	var validationErr *uerror.ValidationError
+	if errors.As(err, &validationErr) {
+		RespondValidationError(w, validationErr.Errors)
+		return
+	}
+
+	errMsg := err.Error()
+	switch errMsg {
+	case "contest not found", "user not found", "problem not in contest":
+		RespondError(w, http.StatusNotFound, errMsg)
+	case "contest slug already exists", "already registered":
+		RespondError(w, http.StatusConflict, errMsg)
+	case "contest has ended", "contest has started", "contest is not running":
+		RespondError(w, http.StatusConflict, errMsg)
+	case "not registered for this contest", "registration is by invitation only":
+		RespondError(w, http.StatusForbidden, errMsg)
+	default:
+		h.logger.Error("Contest request failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, errMsg)
+	}
+}
+
+// ========== USER ENDPOINTS ==========
+
+// ListContests lists the contests visible to the caller
+func (h *ContestHandler) ListContests(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+	contests, err := h.contestUsecase.ListContests(userID)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, contests)
+}
+
+// GetContest retrieves a contest by slug, with its problems once it starts
+func (h *ContestHandler) GetContest(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+	contest, err := h.contestUsecase.GetContest(r.PathValue("slug"), userID)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, contest)
+}
+
+// Register registers the caller to a public contest
+func (h *ContestHandler) Register(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		RespondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.contestUsecase.Register(r.PathValue("slug"), userID); err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "Registered successfully",
+	})
+}
+
+// Unregister withdraws the caller from a contest that hasn't started
+func (h *ContestHandler) Unregister(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		RespondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.contestUsecase.Unregister(r.PathValue("slug"), userID); err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "Unregistered successfully",
+	})
+}
+
+// Submit submits a solution to a problem of a running contest
+func (h *ContestHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		RespondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	problemID, err := strconv.Atoi(r.PathValue("problem_id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid problem id")
+		return
+	}
+
+	var req dto.CreateSubmissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	submission, err := h.contestUsecase.Submit(r.PathValue("slug"), problemID, userID, &req)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, dto.ToSubmissionResponse(submission))
+}
+
+// ListMySubmissions lists the caller's submissions in a contest
+func (h *ContestHandler) ListMySubmissions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		RespondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	submissions, err := h.contestUsecase.ListMySubmissions(r.PathValue("slug"), userID, limit, (page-1)*limit)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	responses := make([]dto.SubmissionResponse, len(submissions))
+	for i := range submissions {
+		submissions[i].Sanitize()
+		responses[i] = dto.ToSubmissionResponse(&submissions[i])
+	}
+
+	RespondJSON(w, http.StatusOK, responses)
+}
+
+// GetScoreboard retrieves the public scoreboard of a contest
+func (h *ContestHandler) GetScoreboard(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+	scoreboard, err := h.contestUsecase.Scoreboard(r.PathValue("slug"), userID)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, scoreboard)
+}
+
+// ========== ADMIN ENDPOINTS ==========
+
+// ListAllContests lists every contest (admin only)
+func (h *ContestHandler) ListAllContests(w http.ResponseWriter, r *http.Request) {
+	contests, err := h.contestUsecase.ListAllContests()
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, contests)
+}
+
+// CreateContest creates a contest (admin only)
+func (h *ContestHandler) CreateContest(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		RespondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req dto.CreateContestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid JSON in create contest request", zap.Error(err))
+		RespondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	contest, err := h.contestUsecase.CreateContest(&req, adminID)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, contest)
+}
+
+// AdminGetContest retrieves a contest with its problems (admin only)
+func (h *ContestHandler) AdminGetContest(w http.ResponseWriter, r *http.Request) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+
+	contest, err := h.contestUsecase.AdminGetContest(contestID)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, contest)
+}
+
+// UpdateContest replaces a contest's settings and problems (admin only)
+func (h *ContestHandler) UpdateContest(w http.ResponseWriter, r *http.Request) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+
+	var req dto.CreateContestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid JSON in update contest request", zap.Error(err))
+		RespondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	contest, err := h.contestUsecase.UpdateContest(contestID, &req)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, contest)
+}
+
+// DeleteContest deletes a contest (admin only)
+func (h *ContestHandler) DeleteContest(w http.ResponseWriter, r *http.Request) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+
+	if err := h.contestUsecase.DeleteContest(contestID); err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "Contest deleted successfully",
+	})
+}
+
+// UnfreezeScoreboard reveals the results hidden by the freeze (admin only)
+func (h *ContestHandler) UnfreezeScoreboard(w http.ResponseWriter, r *http.Request) {
+	h.setUnfrozen(w, r, true)
+}
+
+// FreezeScoreboard hides the results of the freeze again (admin only)
+func (h *ContestHandler) FreezeScoreboard(w http.ResponseWriter, r *http.Request) {
+	h.setUnfrozen(w, r, false)
+}
+
+func (h *ContestHandler) setUnfrozen(w http.ResponseWriter, r *http.Request, unfrozen bool) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+
+	if err := h.contestUsecase.SetUnfrozen(contestID, unfrozen); err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]bool{
+		"unfrozen": unfrozen,
+	})
+}
+
+// AdminGetScoreboard retrieves the scoreboard with every result, freeze or not
+// (admin only)
+func (h *ContestHandler) AdminGetScoreboard(w http.ResponseWriter, r *http.Request) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+
+	scoreboard, err := h.contestUsecase.AdminScoreboard(contestID)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, scoreboard)
+}
+
+// ListRegistrations lists the users registered to a contest (admin only)
+func (h *ContestHandler) ListRegistrations(w http.ResponseWriter, r *http.Request) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+
+	registrations, err := h.contestUsecase.ListRegistrations(contestID)
+	if err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, registrations)
+}
+
+// RegisterUser registers a user to a contest, private ones included (admin only)
+func (h *ContestHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+
+	var req dto.RegisterContestUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID <= 0 {
+		RespondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.contestUsecase.RegisterUser(contestID, req.UserID); err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, map[string]string{
+		"message": "User registered successfully",
+	})
+}
+
+// UnregisterUser removes a user from a contest (admin only)
+func (h *ContestHandler) UnregisterUser(w http.ResponseWriter, r *http.Request) {
+	contestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid contest ID")
+		return
+	}
+	userID, err := strconv.Atoi(r.PathValue("user_id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err := h.contestUsecase.UnregisterUser(contestID, userID); err != nil {
+		h.respondContestError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "User unregistered successfully",
+	})
+}
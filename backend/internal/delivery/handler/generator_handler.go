@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/prabalesh/loco/backend/internal/domain/uerror"
+	"github.com/prabalesh/loco/backend/internal/services/generator"
+)
+
+type GeneratorHandler struct {
+	generatorService *generator.GeneratorService
+}
+
+func NewGeneratorHandler(generatorService *generator.GeneratorService) *GeneratorHandler {
+	return &GeneratorHandler{
+		generatorService: generatorService,
+	}
+}
+
+// POST /api/v2/admin/problems/{id}/test-cases/generate - Generate test cases with
+// the problem's generator and reference solution
+func (h *GeneratorHandler) GenerateTestCases(w http.ResponseWriter, r *http.Request) {
+	problemID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid problem ID")
+		return
+	}
+
+	var req generator.GenerateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	testCases, err := h.generatorService.GenerateTestCases(r.Context(), problemID, req)
+	if err != nil {
+		respondGeneratorError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, testCases)
+}
+
+// POST /api/v2/admin/problems/{id}/stress - Run a candidate solution against the
+// reference solution on generated inputs
+func (h *GeneratorHandler) StressTest(w http.ResponseWriter, r *http.Request) {
+	problemID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid problem ID")
+		return
+	}
+
+	var req generator.StressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.generatorService.Stress(r.Context(), problemID, req)
+	if err != nil {
+		respondGeneratorError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, result)
+}
+
+// respondGeneratorError tells the problem author's mistakes, which they can fix,
+// from failures of the judge
+func respondGeneratorError(w http.ResponseWriter, err error) {
+	var validationErr *uerror.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		RespondValidationError(w, validationErr.Errors)
+	case uerror.IsNotFoundError(err):
+		RespondError(w, http.StatusNotFound, "problem not found")
+	case errors.Is(err, generator.ErrNoGenerator), errors.Is(err, generator.ErrNoReference),
+		errors.Is(err, generator.ErrGeneratorFailed), errors.Is(err, generator.ErrReferenceFailed):
+		RespondError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		RespondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -241,13 +241,25 @@ func (h *ProblemHandler) ValidateTestCases(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = h.problemUsecase.ValidateTestCases(problemID, adminID)
+	violations, err := h.problemUsecase.ValidateTestCases(problemID, adminID)
 	if err != nil {
 		h.logger.Warn("Test case validation failed", zap.Error(err))
 		RespondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if len(violations) > 0 {
+		h.logger.Warn("Test cases violate problem constraints",
+			zap.Int("problem_id", problemID),
+			zap.Int("violations", len(violations)),
+		)
+		RespondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"message":    "some test cases violate the problem's constraints",
+			"violations": violations,
+		})
+		return
+	}
+
 	RespondJSON(w, http.StatusOK, map[string]string{
 		"message": "Test cases validated, problem step updated",
 	})
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/prabalesh/loco/backend/internal/delivery/middleware"
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/domain/dto"
+	"github.com/prabalesh/loco/backend/internal/domain/uerror"
+	"github.com/prabalesh/loco/backend/internal/usecase"
+	"go.uber.org/zap"
+)
+
+type RejudgeHandler struct {
+	rejudgeUsecase *usecase.RejudgeUsecase
+	logger         *zap.Logger
+}
+
+func NewRejudgeHandler(rejudgeUsecase *usecase.RejudgeUsecase, logger *zap.Logger) *RejudgeHandler {
+	return &RejudgeHandler{
+		rejudgeUsecase: rejudgeUsecase,
+		logger:         logger,
+	}
+}
+
+// respondRejudgeError maps the errors of the rejudge usecase to responses
+func (h *RejudgeHandler) respondRejudgeError(w http.ResponseWriter, err error) {
+	var validationErr *uerror.ValidationError
+	if errors.As(err, &validationErr) {
+		RespondValidationError(w, validationErr.Errors)
+		return
+	}
+
+	errMsg := err.Error()
+	switch {
+	case errMsg == "rejudge not found", errMsg == "submission not found":
+		RespondError(w, http.StatusNotFound, errMsg)
+	case errMsg == "no submissions to rejudge", errors.Is(err, domain.ErrSubmissionsBeingJudged):
+		RespondError(w, http.StatusConflict, errMsg)
+	default:
+		h.logger.Error("Rejudge request failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, errMsg)
+	}
+}
+
+// CreateRejudge judges again the submissions picked by the request body (admin only)
+func (h *RejudgeHandler) CreateRejudge(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		RespondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req dto.RejudgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid JSON in rejudge request", zap.Error(err))
+		RespondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rejudge, err := h.rejudgeUsecase.Rejudge(&req, adminID)
+	if err != nil {
+		h.respondRejudgeError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, rejudge)
+}
+
+// RejudgeSubmission judges a single submission again (admin only)
+func (h *RejudgeHandler) RejudgeSubmission(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		RespondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	submissionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid submission ID")
+		return
+	}
+
+	rejudge, err := h.rejudgeUsecase.RejudgeSubmission(submissionID, adminID)
+	if err != nil {
+		h.respondRejudgeError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, rejudge)
+}
+
+// ListRejudges lists the rejudges, latest first (admin only)
+func (h *RejudgeHandler) ListRejudges(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	rejudges, total, err := h.rejudgeUsecase.ListRejudges(page, limit)
+	if err != nil {
+		h.respondRejudgeError(w, err)
+		return
+	}
+
+	RespondPaginatedJSON(w, http.StatusOK, PaginatedResponse[[]domain.Rejudge]{
+		Total: int(total),
+		Page:  page,
+		Limit: limit,
+		Data:  rejudges,
+	})
+}
+
+// GetRejudge retrieves a rejudge's progress and results; ?changed=true keeps only the
+// submissions whose verdict changed (admin only)
+func (h *RejudgeHandler) GetRejudge(w http.ResponseWriter, r *http.Request) {
+	rejudgeID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid rejudge ID")
+		return
+	}
+	changedOnly, _ := strconv.ParseBool(r.URL.Query().Get("changed"))
+
+	rejudge, err := h.rejudgeUsecase.GetRejudge(rejudgeID, changedOnly)
+	if err != nil {
+		h.respondRejudgeError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, rejudge)
+}
+
+// GetVerdictHistory lists a submission's verdicts across rejudges (admin only)
+func (h *RejudgeHandler) GetVerdictHistory(w http.ResponseWriter, r *http.Request) {
+	submissionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid submission ID")
+		return
+	}
+
+	history, err := h.rejudgeUsecase.GetVerdictHistory(submissionID)
+	if err != nil {
+		h.respondRejudgeError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, history)
+}
@@ -78,7 +78,9 @@ func (h *SubmissionHandler) GetSubmission(w http.ResponseWriter, r *http.Request
 		submission.Sanitize()
 	}
 
-	RespondJSON(w, http.StatusOK, dto.ToSubmissionResponse(submission))
+	resp := dto.ToSubmissionResponse(submission)
+	resp.Ranking = h.submissionUsecase.RankSubmission(submission)
+	RespondJSON(w, http.StatusOK, resp)
 }
 
 func (h *SubmissionHandler) ListUserProblemSubmissions(w http.ResponseWriter, r *http.Request) {
@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -177,6 +178,41 @@ func (h *TestCaseHandler) DeleteTestCase(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// GetTestCaseData streams a test case's input or expected output as text, for
+// values too large to come with the test case (admin only)
+func (h *TestCaseHandler) GetTestCaseData(w http.ResponseWriter, r *http.Request) {
+	testCaseID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid test case ID")
+		return
+	}
+
+	data, err := h.testCaseUsecase.OpenTestCaseData(r.Context(), testCaseID, r.PathValue("field"))
+	if err != nil {
+		var validationErr *uerror.ValidationError
+		if errors.As(err, &validationErr) {
+			RespondValidationError(w, validationErr.Errors)
+			return
+		}
+
+		errMsg := err.Error()
+		switch errMsg {
+		case "test case not found":
+			RespondError(w, http.StatusNotFound, errMsg)
+		default:
+			RespondError(w, http.StatusInternalServerError, errMsg)
+		}
+		return
+	}
+	defer data.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, data); err != nil {
+		h.logger.Warn("Failed to stream test data", zap.Error(err), zap.Int("test_case_id", testCaseID))
+	}
+}
+
 // DeleteAllTestCases deletes all test cases for a problem (admin only)
 func (h *TestCaseHandler) DeleteAllTestCases(w http.ResponseWriter, r *http.Request) {
 	// Get admin ID from context
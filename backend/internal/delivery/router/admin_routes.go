@@ -49,7 +49,10 @@ func SetupAdminRoutes(mux *http.ServeMux, deps *Dependencies, adminAuthMiddlewar
 	mux.Handle("POST /admin/problems/{problem_id}/test-cases/reorder", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.ReorderTestCases)))
 	mux.Handle("PUT /admin/test-cases/{id}", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.UpdateTestCase)))
 	mux.Handle("DELETE /admin/test-cases/{id}", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.DeleteTestCase)))
+	mux.Handle("GET /admin/test-cases/{id}/data/{field}", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.GetTestCaseData)))
 	mux.Handle("POST /admin/problems/{id}/test-cases/validate", adminAuthMiddleware(http.HandlerFunc(deps.ProblemHandler.ValidateTestCases)))
+	mux.Handle("POST /admin/problems/{id}/test-cases/generate", adminAuthMiddleware(http.HandlerFunc(deps.GeneratorHandler.GenerateTestCases)))
+	mux.Handle("POST /admin/problems/{id}/stress", adminAuthMiddleware(http.HandlerFunc(deps.GeneratorHandler.StressTest)))
 
 	// ========== ADMIN LANGUAGE ROUTES ==========
 	mux.Handle("POST /admin/languages", adminAuthMiddleware(http.HandlerFunc(deps.LanguageHandler.CreateLanguage)))
@@ -66,6 +69,28 @@ func SetupAdminRoutes(mux *http.ServeMux, deps *Dependencies, adminAuthMiddlewar
 	mux.Handle("POST /admin/problems/{id}/submit", adminAuthMiddleware(http.HandlerFunc(deps.SubmissionHandler.AdminSubmit)))
 	mux.Handle("GET /admin/problems/{id}/submissions", adminAuthMiddleware(http.HandlerFunc(deps.SubmissionHandler.ListProblemSubmissions)))
 
+	// ========== ADMIN CONTEST ROUTES ==========
+	mux.Handle("GET /admin/contests", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.ListAllContests)))
+	mux.Handle("POST /admin/contests", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.CreateContest)))
+	mux.Handle("GET /admin/contests/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.AdminGetContest)))
+	mux.Handle("PUT /admin/contests/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.UpdateContest)))
+	mux.Handle("DELETE /admin/contests/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.DeleteContest)))
+	mux.Handle("GET /admin/contests/{id}/scoreboard", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.AdminGetScoreboard)))
+	mux.Handle("POST /admin/contests/{id}/unfreeze", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.UnfreezeScoreboard)))
+	mux.Handle("POST /admin/contests/{id}/freeze", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.FreezeScoreboard)))
+	mux.Handle("GET /admin/contests/{id}/registrations", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.ListRegistrations)))
+	mux.Handle("POST /admin/contests/{id}/registrations", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.RegisterUser)))
+	mux.Handle("DELETE /admin/contests/{id}/registrations/{user_id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.UnregisterUser)))
+
+	// ========== ADMIN REJUDGE ROUTES ==========
+	mux.Handle("GET /admin/rejudges", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.ListRejudges)))
+	mux.Handle("POST /admin/rejudges", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.CreateRejudge)))
+	mux.Handle("GET /admin/rejudges/{id}", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.GetRejudge)))
+	mux.Handle("POST /admin/submissions/{id}/rejudge", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.RejudgeSubmission)))
+	mux.Handle("GET /admin/submissions/{id}/verdicts", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.GetVerdictHistory)))
+	mux.Handle("GET /admin/submissions/{id}/judgements", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.ListSubmissionJudgements)))
+	mux.Handle("GET /admin/judgements/{id}", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.GetJudgement)))
+
 	// ========== ADMIN TAG ROUTES ==========
 	mux.Handle("POST /admin/tags", adminAuthMiddleware(http.HandlerFunc(deps.ProblemHandler.CreateTag)))
 	mux.Handle("PUT /admin/tags/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ProblemHandler.UpdateTag)))
@@ -37,6 +37,9 @@ type Dependencies struct {
 	CodeGenHandler      *handler.CodeGenHandler
 	ValidationHandler   *handler.ValidationHandler
 	BulkHandler         *handler.BulkHandler
+	GeneratorHandler    *handler.GeneratorHandler
+	ContestHandler      *handler.ContestHandler
+	RejudgeHandler      *handler.RejudgeHandler
 }
 
 func SetupRouter(deps *Dependencies) http.Handler {
@@ -86,6 +89,16 @@ func SetupRouter(deps *Dependencies) http.Handler {
 	mux.Handle("GET /submissions", authMiddleware(http.HandlerFunc(deps.SubmissionHandler.ListUserSubmissions)))
 	mux.Handle("GET /problems/{problem_id}/submissions/{id}", middleware.RegularOrAdminAuth(deps.JWTService, deps.Log)(http.HandlerFunc(deps.SubmissionHandler.GetSubmission)))
 
+	// ========== CONTEST ROUTES ==========
+	optionalAuthMiddleware := middleware.OptionalAuth(deps.JWTService, deps.Log)
+	mux.Handle("GET /contests", optionalAuthMiddleware(http.HandlerFunc(deps.ContestHandler.ListContests)))
+	mux.Handle("GET /contests/{slug}", optionalAuthMiddleware(http.HandlerFunc(deps.ContestHandler.GetContest)))
+	mux.Handle("GET /contests/{slug}/scoreboard", optionalAuthMiddleware(http.HandlerFunc(deps.ContestHandler.GetScoreboard)))
+	mux.Handle("POST /contests/{slug}/register", authMiddleware(http.HandlerFunc(deps.ContestHandler.Register)))
+	mux.Handle("DELETE /contests/{slug}/register", authMiddleware(http.HandlerFunc(deps.ContestHandler.Unregister)))
+	mux.Handle("POST /contests/{slug}/problems/{problem_id}/submissions", authMiddleware(submissionRateLimit(http.HandlerFunc(deps.ContestHandler.Submit))))
+	mux.Handle("GET /contests/{slug}/submissions", authMiddleware(http.HandlerFunc(deps.ContestHandler.ListMySubmissions)))
+
 	// ========== LEADERBOARD ROUTES ==========
 	mux.HandleFunc("GET /leaderboard", deps.LeaderboardHandler.GetLeaderboard)
 
@@ -115,6 +128,8 @@ func SetupRouter(deps *Dependencies) http.Handler {
 	mux.Handle("GET /admin/analytics", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.GetAnalytics)))
 	mux.Handle("GET /admin/piston/executions", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.ListPistonExecutions)))
 	mux.Handle("GET /admin/submissions", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.ListSubmissions)))
+	mux.Handle("GET /admin/queue/dead", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.ListDeadSubmissions)))
+	mux.Handle("POST /admin/queue/dead/{id}/replay", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.ReplayDeadSubmission)))
 
 	// ========== ADMIN PROBLEM ROUTES ==========
 	mux.Handle("GET /admin/problems", adminAuthMiddleware(http.HandlerFunc(deps.ProblemHandler.ListAllProblems)))
@@ -140,7 +155,10 @@ func SetupRouter(deps *Dependencies) http.Handler {
 	mux.Handle("POST /admin/problems/{problem_id}/test-cases/reorder", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.ReorderTestCases)))
 	mux.Handle("PUT /admin/test-cases/{id}", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.UpdateTestCase)))
 	mux.Handle("DELETE /admin/test-cases/{id}", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.DeleteTestCase)))
+	mux.Handle("GET /admin/test-cases/{id}/data/{field}", adminAuthMiddleware(http.HandlerFunc(deps.TestCaseHandler.GetTestCaseData)))
 	mux.Handle("POST /admin/problems/{id}/test-cases/validate", adminAuthMiddleware(http.HandlerFunc(deps.ProblemHandler.ValidateTestCases)))
+	mux.Handle("POST /admin/problems/{id}/test-cases/generate", adminAuthMiddleware(http.HandlerFunc(deps.GeneratorHandler.GenerateTestCases)))
+	mux.Handle("POST /admin/problems/{id}/stress", adminAuthMiddleware(http.HandlerFunc(deps.GeneratorHandler.StressTest)))
 
 	// ========== ADMIN LANGUAGE ROUTES ==========
 	mux.Handle("POST /admin/languages", adminAuthMiddleware(http.HandlerFunc(deps.LanguageHandler.CreateLanguage)))
@@ -157,6 +175,28 @@ func SetupRouter(deps *Dependencies) http.Handler {
 	mux.Handle("POST /admin/problems/{id}/submit", adminAuthMiddleware(http.HandlerFunc(deps.SubmissionHandler.AdminSubmit)))
 	mux.Handle("GET /admin/problems/{id}/submissions", adminAuthMiddleware(http.HandlerFunc(deps.SubmissionHandler.ListProblemSubmissions)))
 
+	// ========== ADMIN CONTEST ROUTES ==========
+	mux.Handle("GET /admin/contests", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.ListAllContests)))
+	mux.Handle("POST /admin/contests", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.CreateContest)))
+	mux.Handle("GET /admin/contests/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.AdminGetContest)))
+	mux.Handle("PUT /admin/contests/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.UpdateContest)))
+	mux.Handle("DELETE /admin/contests/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.DeleteContest)))
+	mux.Handle("GET /admin/contests/{id}/scoreboard", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.AdminGetScoreboard)))
+	mux.Handle("POST /admin/contests/{id}/unfreeze", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.UnfreezeScoreboard)))
+	mux.Handle("POST /admin/contests/{id}/freeze", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.FreezeScoreboard)))
+	mux.Handle("GET /admin/contests/{id}/registrations", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.ListRegistrations)))
+	mux.Handle("POST /admin/contests/{id}/registrations", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.RegisterUser)))
+	mux.Handle("DELETE /admin/contests/{id}/registrations/{user_id}", adminAuthMiddleware(http.HandlerFunc(deps.ContestHandler.UnregisterUser)))
+
+	// ========== ADMIN REJUDGE ROUTES ==========
+	mux.Handle("GET /admin/rejudges", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.ListRejudges)))
+	mux.Handle("POST /admin/rejudges", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.CreateRejudge)))
+	mux.Handle("GET /admin/rejudges/{id}", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.GetRejudge)))
+	mux.Handle("POST /admin/submissions/{id}/rejudge", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.RejudgeSubmission)))
+	mux.Handle("GET /admin/submissions/{id}/verdicts", adminAuthMiddleware(http.HandlerFunc(deps.RejudgeHandler.GetVerdictHistory)))
+	mux.Handle("GET /admin/submissions/{id}/judgements", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.ListSubmissionJudgements)))
+	mux.Handle("GET /admin/judgements/{id}", adminAuthMiddleware(http.HandlerFunc(deps.AdminHandler.GetJudgement)))
+
 	// ========== ADMIN TAG ROUTES ==========
 	mux.Handle("POST /admin/tags", adminAuthMiddleware(http.HandlerFunc(deps.ProblemHandler.CreateTag)))
 	mux.Handle("PUT /admin/tags/{id}", adminAuthMiddleware(http.HandlerFunc(deps.ProblemHandler.UpdateTag)))
@@ -169,6 +209,8 @@ func SetupRouter(deps *Dependencies) http.Handler {
 
 	// ========== CODEGEN ROUTES ==========
 	mux.Handle("POST /codegen/stub", adminAuthMiddleware(http.HandlerFunc(deps.CodeGenHandler.GenerateStub)))
+	mux.Handle("POST /codegen/harness", adminAuthMiddleware(http.HandlerFunc(deps.CodeGenHandler.PreviewHarness)))
+	mux.Handle("GET /admin/problems/{id}/harness", adminAuthMiddleware(http.HandlerFunc(deps.CodeGenHandler.PreviewProblemHarness)))
 	mux.Handle("GET /problems/{problem_id}/stub", http.HandlerFunc(deps.CodeGenHandler.GetProblemStub))
 	// mux.Handle("GET /problems/{problem_id}/boilerplates", adminAuthMiddleware(http.HandlerFunc(deps.CodeGenHandler.GetProblemBoilerplates)))
 
@@ -8,16 +8,20 @@ import (
 	"github.com/prabalesh/loco/backend/internal/delivery/middleware"
 	"github.com/prabalesh/loco/backend/internal/delivery/router"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/auth"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/blobstore"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/cache"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/email"
-	"github.com/prabalesh/loco/backend/internal/infrastructure/piston"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/worker"
 	"github.com/prabalesh/loco/backend/internal/repository/postgres"
 	"github.com/prabalesh/loco/backend/internal/services/bulk"
 	"github.com/prabalesh/loco/backend/internal/services/codegen"
 	"github.com/prabalesh/loco/backend/internal/services/execution"
+	"github.com/prabalesh/loco/backend/internal/services/generator"
+	"github.com/prabalesh/loco/backend/internal/services/inputvalidator"
 	"github.com/prabalesh/loco/backend/internal/services/problem"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
 	"github.com/prabalesh/loco/backend/internal/services/validation"
 	"github.com/prabalesh/loco/backend/internal/usecase"
 	"github.com/prabalesh/loco/backend/pkg/config"
@@ -46,6 +50,9 @@ func NewContainer(db *database.Database, cfg *config.Config, logger *zap.Logger)
 	referenceSolutionRepo := postgres.NewReferenceSolutionRepository(db)
 	customTypeRepo := postgres.NewCustomTypeRepository(db.DB)
 	pistonExecutionRepo := postgres.NewPistonExecutionRepository(db)
+	contestRepo := postgres.NewContestRepository(db)
+	rejudgeRepo := postgres.NewRejudgeRepository(db)
+	judgementRepo := postgres.NewSubmissionJudgementRepository(db)
 
 	// Redis client
 	redisClient, err := redis.NewRedisClient(cfg.Redis, logger)
@@ -57,29 +64,38 @@ func NewContainer(db *database.Database, cfg *config.Config, logger *zap.Logger)
 	cacheService := cache.NewCacheService(redisClient.Client, logger)
 	jwtService := auth.NewJWTService(cfg.JWT.AccessTokenSecret, cfg.JWT.RefreshTokenSecret, cfg.JWT.AccessTokenExpiration, cfg.JWT.RefreshTokenExpiration)
 	emailService := email.NewEmailService(cfg, logger)
-	pistonService := piston.NewPistonService(cfg, pistonExecutionRepo, logger)
+	codeExecutor, err := executor.New(cfg, pistonExecutionRepo, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize code executor", zap.Error(err))
+	}
 	jobQueue := queue.NewJobQueue(redisClient, logger)
 	typeImplementationRepo := postgres.NewTypeImplementationRepository(db.DB)
-	codeGenService := codegen.NewCodeGenService(typeImplementationRepo)
+	codeGenService := codegen.NewCodeGenService(customTypeRepo, typeImplementationRepo)
 	boilerplateService := codegen.NewBoilerplateService(boilerplateRepo, languageRepo, testCaseRepo, codeGenService)
-	executionService := execution.NewExecutionService(cfg.Server.PistonURL, boilerplateService, codeGenService, problemRepo, pistonExecutionRepo)
+	testDataStore, err := blobstore.New(cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize test data store", zap.Error(err))
+	}
+	testDataService := testdata.NewTestDataService(testDataStore, cfg.TestData.InlineLimitBytes)
+	executionService := execution.NewExecutionService(codeExecutor, boilerplateService, codeGenService, problemRepo, languageRepo, testDataService)
+	inputValidatorService := inputvalidator.NewInputValidatorService(codeExecutor, testDataService)
 
 	cookieManager := cookies.NewCookieManager(cfg)
 
 	// Usecases
 	authUsecase := usecase.NewAuthUsecase(userRepo, jwtService, emailService, cfg, logger)
 	userUsecase := usecase.NewUserUsecase(userRepo, submissionRepo, achievementRepo, logger)
-	adminUsecase := usecase.NewAdminUsecase(userRepo, problemRepo, submissionRepo, pistonExecutionRepo, redisClient.Client, logger)
+	adminUsecase := usecase.NewAdminUsecase(userRepo, problemRepo, submissionRepo, pistonExecutionRepo, judgementRepo, jobQueue, redisClient.Client, logger)
 	problemLanguageUsecase := usecase.NewProblemLanguageUsecase(problemLanguageRepo, problemRepo, languageRepo, logger)
-	problemUsecase := usecase.NewProblemUsecase(problemRepo, testCaseRepo, userProblemStatsRepo, tagRepo, categoryRepo, customTypeRepo, boilerplateService, cacheService, cfg, logger)
+	problemUsecase := usecase.NewProblemUsecase(problemRepo, testCaseRepo, userProblemStatsRepo, tagRepo, categoryRepo, customTypeRepo, boilerplateService, inputValidatorService, testDataService, cacheService, cfg, logger)
 	languageUsecase := usecase.NewLanguageUsecase(languageRepo, cfg, logger)
-	testCaseUsecase := usecase.NewTestCaseUsecase(testCaseRepo, problemRepo, cfg, logger)
+	testCaseUsecase := usecase.NewTestCaseUsecase(testCaseRepo, problemRepo, inputValidatorService, testDataService, cfg, logger)
 	achievementUsecase := usecase.NewAchievementUsecase(achievementRepo, userRepo, submissionRepo, problemRepo, redisClient, logger)
-	submissionUsecase := usecase.NewSubmissionUsecase(submissionRepo, problemRepo, testCaseRepo, languageRepo, problemLanguageRepo, pistonService, executionService, jobQueue, achievementUsecase, cfg, logger)
+	submissionUsecase := usecase.NewSubmissionUsecase(submissionRepo, problemRepo, testCaseRepo, languageRepo, problemLanguageRepo, codeExecutor, executionService, jobQueue, achievementUsecase, cfg, logger)
 	notificationUsecase := usecase.NewNotificationUsecase(redisClient, logger)
 
 	// Worker
-	submissionWorker := worker.NewWorker(jobQueue, submissionRepo, problemRepo, testCaseRepo, languageRepo, problemLanguageRepo, referenceSolutionRepo, pistonService, boilerplateService, userProblemStatsRepo, logger, redisClient.Client, cfg)
+	submissionWorker := worker.NewWorker(jobQueue, submissionRepo, problemRepo, testCaseRepo, languageRepo, problemLanguageRepo, referenceSolutionRepo, codeExecutor, boilerplateService, testDataService, userProblemStatsRepo, rejudgeRepo, judgementRepo, logger, redisClient.Client, cfg)
 
 	// Handlers
 	authHanlder := handler.NewAuthHandler(authUsecase, logger, cfg, cookieManager)
@@ -109,11 +125,20 @@ func NewContainer(db *database.Database, cfg *config.Config, logger *zap.Logger)
 	// We need to keep ProblemService for BulkImport implementation for now, or check if we can migrate.
 	// But let's check imports.
 	// "github.com/prabalesh/loco/backend/internal/services/problem" is imported.
-	v2ProblemService := problem.NewProblemService(problemRepo, testCaseRepo, tagRepo, categoryRepo, customTypeRepo, referenceSolutionRepo, boilerplateService)
+	v2ProblemService := problem.NewProblemService(problemRepo, testCaseRepo, tagRepo, categoryRepo, customTypeRepo, referenceSolutionRepo, boilerplateService, inputValidatorService, testDataService)
 
 	bulkImportService := bulk.NewBulkImportService(v2ProblemService, validationService, db.DB)
 	bulkHandler := handler.NewBulkHandler(bulkImportService)
 
+	generatorService := generator.NewGeneratorService(codeExecutor, executionService, problemRepo, testCaseRepo, referenceSolutionRepo, testDataService)
+	generatorHandler := handler.NewGeneratorHandler(generatorService)
+
+	contestUsecase := usecase.NewContestUsecase(contestRepo, problemRepo, userRepo, submissionRepo, submissionUsecase, logger)
+	contestHandler := handler.NewContestHandler(contestUsecase, logger)
+
+	rejudgeUsecase := usecase.NewRejudgeUsecase(rejudgeRepo, submissionRepo, problemRepo, languageRepo, jobQueue, cacheService, logger)
+	rejudgeHandler := handler.NewRejudgeHandler(rejudgeUsecase, logger)
+
 	// Middleware
 	rateLimitMiddleware := middleware.NewRateLimitMiddleware(redisClient.Client, logger, &cfg.RateLimit)
 	submissionRateLimitMiddleware := middleware.NewSubmissionRateLimitMiddleware(redisClient.Client, logger, &cfg.SubmissionRateLimit)
@@ -138,6 +163,9 @@ func NewContainer(db *database.Database, cfg *config.Config, logger *zap.Logger)
 		CodeGenHandler:      codeGenHandler,
 		ValidationHandler:   validationHandler,
 		BulkHandler:         bulkHandler,
+		GeneratorHandler:    generatorHandler,
+		ContestHandler:      contestHandler,
+		RejudgeHandler:      rejudgeHandler,
 		RateLimit:           rateLimitMiddleware,
 		SubmissionRateLimit: submissionRateLimitMiddleware,
 		RunCodeRateLimit:    runCodeRateLimitMiddleware,
@@ -33,5 +33,8 @@ type AchievementRepository interface {
 	GetUnlockedByUser(userID int) ([]UserAchievement, error)
 	Unlock(userID int, achievementID int) error
 	HasUnlocked(userID int, achievementID int) (bool, error)
+	// Revoke takes back an achievement and the XP it awarded in one transaction,
+	// reporting false when the user didn't have it
+	Revoke(userID int, achievement *Achievement) (bool, error)
 	Create(achievement *Achievement) error
 }
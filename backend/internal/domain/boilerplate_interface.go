@@ -7,6 +7,7 @@ type BoilerplateService interface {
 	GenerateBoilerplateForLanguage(problemID, languageID int, signature ProblemSchema, languageSlug string, testCases []TestCase, validationType string) error
 	GetStubCode(problemID, languageID int) (string, error)
 	GetTestHarnessTemplate(problemID, languageID int) (string, error)
+	GetHarnessVersion(problemID, languageID int) (string, error)
 	InjectUserCodeIntoHarness(template, userCode string) string
 	GetBoilerplateStats(problemID int) (map[string]interface{}, error)
 	GetBoilerplatesByProblemID(problemID int) ([]ProblemBoilerplate, error)
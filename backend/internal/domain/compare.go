@@ -0,0 +1,69 @@
+package domain
+
+// Keys in TestCase.ValidationConfig that tune how harnesses compare an answer with
+// the expected output, e.g. {"abs_epsilon": 1e-6, "case_insensitive": true}
+const (
+	ValidationConfigAbsEpsilon       = "abs_epsilon"
+	ValidationConfigRelEpsilon       = "rel_epsilon"
+	ValidationConfigCaseInsensitive  = "case_insensitive"
+	ValidationConfigIgnoreWhitespace = "ignore_whitespace"
+)
+
+// DefaultFloatEpsilon is the absolute and relative tolerance for problems returning
+// doubles whose test cases don't set their own, so 0.1+0.2 matches 0.3
+const DefaultFloatEpsilon = 1e-9
+
+// CompareOptions is how a harness compares values: numbers match when they are within
+// AbsEpsilon or RelEpsilon of each other, strings after optional case folding and
+// whitespace collapsing. The zero value compares exactly.
+type CompareOptions struct {
+	AbsEpsilon       float64 `json:"abs_epsilon,omitempty"`
+	RelEpsilon       float64 `json:"rel_epsilon,omitempty"`
+	CaseInsensitive  bool    `json:"case_insensitive,omitempty"`
+	IgnoreWhitespace bool    `json:"ignore_whitespace,omitempty"`
+}
+
+// IsZero reports whether the options ask for an exact comparison
+func (o CompareOptions) IsZero() bool {
+	return o == CompareOptions{}
+}
+
+// CompareOptions returns how this test case's answers are compared: its
+// ValidationConfig where set, with DefaultFloatEpsilon for problems returning doubles
+func (tc *TestCase) CompareOptions(problem *Problem) CompareOptions {
+	opts := CompareOptions{
+		AbsEpsilon:       tc.ValidationConfig.epsilon(ValidationConfigAbsEpsilon),
+		RelEpsilon:       tc.ValidationConfig.epsilon(ValidationConfigRelEpsilon),
+		CaseInsensitive:  tc.ValidationConfig.flag(ValidationConfigCaseInsensitive),
+		IgnoreWhitespace: tc.ValidationConfig.flag(ValidationConfigIgnoreWhitespace),
+	}
+
+	_, hasAbs := tc.ValidationConfig[ValidationConfigAbsEpsilon]
+	_, hasRel := tc.ValidationConfig[ValidationConfigRelEpsilon]
+	if !hasAbs && !hasRel && problem != nil && problem.ReturnType != nil && GenericType(*problem.ReturnType).IsFloating() {
+		opts.AbsEpsilon = DefaultFloatEpsilon
+		opts.RelEpsilon = DefaultFloatEpsilon
+	}
+
+	return opts
+}
+
+// epsilon reads a non-negative tolerance, defaulting to 0
+func (vc ValidationConfig) epsilon(key string) float64 {
+	var eps float64
+	switch v := vc[key].(type) {
+	case float64:
+		eps = v
+	case int:
+		eps = float64(v)
+	}
+	if eps < 0 {
+		return 0
+	}
+	return eps
+}
+
+func (vc ValidationConfig) flag(key string) bool {
+	b, _ := vc[key].(bool)
+	return b
+}
@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Directions a list parameter may be sorted in
+const (
+	SortedAsc  = "asc"  // non-decreasing
+	SortedDesc = "desc" // non-increasing
+)
+
+// ParamConstraints limit the values a parameter may take in test cases, the
+// declarative counterpart of a problem's validator program. Min and Max bound every
+// number in the value, however deep in lists and maps; the length bounds apply to a
+// string or list value itself, as do Unique and Sorted to a list's elements. Null
+// values of nullable types have nothing to check.
+type ParamConstraints struct {
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Unique    bool     `json:"unique,omitempty"`
+	Sorted    string   `json:"sorted,omitempty"` // SortedAsc or SortedDesc
+}
+
+// Validate checks the constraints make sense for a parameter of type t
+func (c *ParamConstraints) Validate(t *TypeExpr) error {
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return errors.New("min is greater than max")
+	}
+	if (c.Min != nil || c.Max != nil) && !t.Contains(KindInt) && !t.Contains(KindLong) && !t.Contains(KindDouble) {
+		return fmt.Errorf("min and max need numbers, %s has none", t)
+	}
+	if (c.MinLength != nil && *c.MinLength < 0) || (c.MaxLength != nil && *c.MaxLength < 0) {
+		return errors.New("lengths cannot be negative")
+	}
+	if c.MinLength != nil && c.MaxLength != nil && *c.MinLength > *c.MaxLength {
+		return errors.New("min_length is greater than max_length")
+	}
+	if (c.MinLength != nil || c.MaxLength != nil) && t.Kind != KindString && t.Kind != KindList {
+		return fmt.Errorf("min_length and max_length need a string or a list, not %s", t)
+	}
+	if c.Sorted != "" && c.Sorted != SortedAsc && c.Sorted != SortedDesc {
+		return fmt.Errorf("sorted must be %s or %s", SortedAsc, SortedDesc)
+	}
+	if (c.Unique || c.Sorted != "") && t.Kind != KindList {
+		return fmt.Errorf("unique and sorted need a list, not %s", t)
+	}
+	if c.Sorted != "" && !t.Elem.IsNumeric() && t.Elem.Kind != KindString && t.Elem.Kind != KindChar {
+		return fmt.Errorf("sorted needs a list of numbers, strings or chars, not %s", t)
+	}
+	return nil
+}
+
+// Check returns what is wrong with a value, one message per broken constraint
+func (c *ParamConstraints) Check(value interface{}) []string {
+	if value == nil {
+		return nil
+	}
+	var violations []string
+
+	if c.Min != nil || c.Max != nil {
+		walkNumbers(value, func(n float64) bool {
+			if c.Min != nil && n < *c.Min {
+				violations = append(violations, fmt.Sprintf("%v is less than %v", n, *c.Min))
+				return false
+			}
+			if c.Max != nil && n > *c.Max {
+				violations = append(violations, fmt.Sprintf("%v is greater than %v", n, *c.Max))
+				return false
+			}
+			return true
+		})
+	}
+
+	length := -1
+	switch v := value.(type) {
+	case string:
+		length = utf8.RuneCountInString(v)
+	case []interface{}:
+		length = len(v)
+	}
+	if length >= 0 && c.MinLength != nil && length < *c.MinLength {
+		violations = append(violations, fmt.Sprintf("length %d is less than %d", length, *c.MinLength))
+	}
+	if length >= 0 && c.MaxLength != nil && length > *c.MaxLength {
+		violations = append(violations, fmt.Sprintf("length %d is greater than %d", length, *c.MaxLength))
+	}
+
+	list, _ := value.([]interface{})
+	if c.Unique {
+		seen := make(map[string]bool, len(list))
+		for i, elem := range list {
+			key, _ := json.Marshal(elem)
+			if seen[string(key)] {
+				violations = append(violations, fmt.Sprintf("element %d repeats %s", i, key))
+				break
+			}
+			seen[string(key)] = true
+		}
+	}
+	if c.Sorted != "" {
+		for i := 1; i < len(list); i++ {
+			cmp := compareScalars(list[i-1], list[i])
+			if (c.Sorted == SortedAsc && cmp > 0) || (c.Sorted == SortedDesc && cmp < 0) {
+				violations = append(violations, fmt.Sprintf("elements %d and %d are out of %s order", i-1, i, c.Sorted))
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// walkNumbers calls fn on every number in a decoded JSON value until it returns false
+func walkNumbers(value interface{}, fn func(float64) bool) bool {
+	switch v := value.(type) {
+	case float64:
+		return fn(v)
+	case []interface{}:
+		for _, elem := range v {
+			if !walkNumbers(elem, fn) {
+				return false
+			}
+		}
+	case map[string]interface{}:
+		for _, elem := range v {
+			if !walkNumbers(elem, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// compareScalars orders two decoded JSON numbers or strings
+func compareScalars(a, b interface{}) int {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// CheckConstraints checks a decoded test input against the constraints of the
+// parameters it is for: the parameter values of a function problem, where a lone
+// parameter's value may be given without the surrounding array, or the arguments
+// of each operation of a design problem. Messages name the parameter, and the
+// operation for design problems.
+func (s ProblemSchema) CheckConstraints(input interface{}) []string {
+	if s.Design != nil {
+		return s.Design.checkConstraints(input)
+	}
+
+	values, ok := input.([]interface{})
+	if len(s.Parameters) == 1 {
+		// Unwrapped unless it is the wrapper of a value of the parameter's type
+		t, err := s.Parameters[0].Type.Parse()
+		if !ok || len(values) != 1 || err != nil || t.Check(values[0]) != nil {
+			values = []interface{}{input}
+		}
+	}
+	return checkParams(s.Parameters, values, "")
+}
+
+func (d *DesignSchema) checkConstraints(input interface{}) []string {
+	pair, _ := input.([]interface{})
+	if len(pair) != 2 {
+		return nil
+	}
+	operations, _ := pair[0].([]interface{})
+	arguments, _ := pair[1].([]interface{})
+
+	var violations []string
+	for k := 0; k < len(operations) && k < len(arguments); k++ {
+		name, _ := operations[k].(string)
+		params := d.Constructor
+		if k > 0 {
+			method, ok := d.Method(name)
+			if !ok {
+				continue
+			}
+			params = method.Parameters
+		}
+		args, _ := arguments[k].([]interface{})
+		violations = append(violations, checkParams(params, args, fmt.Sprintf("operation %d (%s): ", k+1, name))...)
+	}
+	return violations
+}
+
+func checkParams(params []SchemaParameter, values []interface{}, prefix string) []string {
+	var violations []string
+	for i, param := range params {
+		if param.Constraints == nil || i >= len(values) {
+			continue
+		}
+		for _, v := range param.Constraints.Check(values[i]) {
+			violations = append(violations, fmt.Sprintf("%s%s: %s", prefix, param.Name, v))
+		}
+	}
+	return violations
+}
+
+// TestCaseViolation is what is wrong with the input of one test case
+type TestCaseViolation struct {
+	TestCase   int      `json:"test_case"` // position in the checked list, from 1
+	TestCaseID int      `json:"test_case_id,omitempty"`
+	Errors     []string `json:"errors"`
+}
+
+// ErrValidatorFailed means a problem's validator program is broken (didn't compile,
+// crashed or answered with an unknown exit code), so nothing can be said about the
+// inputs it was given
+var ErrValidatorFailed = errors.New("validator failed")
+
+// InputValidator checks test inputs against a problem's declared constraints and
+// validator program
+type InputValidator interface {
+	ValidateTestCases(ctx context.Context, problem *Problem, testCases []TestCase) ([]TestCaseViolation, error)
+}
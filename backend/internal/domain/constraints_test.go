@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestParamConstraintsCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints ParamConstraints
+		value       string
+		violations  []string
+	}{
+		{"in range", ParamConstraints{Min: ptr(1.0), Max: ptr(100000.0)}, `5`, nil},
+		{"too large", ParamConstraints{Min: ptr(1.0), Max: ptr(100000.0)}, `100001`, []string{"100001 is greater than 100000"}},
+		{"nested too small", ParamConstraints{Min: ptr(-10.0)}, `[[1,2],[-11]]`, []string{"-11 is less than -10"}},
+		{"string length", ParamConstraints{MinLength: ptr(1), MaxLength: ptr(3)}, `"héllo"`, []string{"length 5 is greater than 3"}},
+		{"list length", ParamConstraints{MinLength: ptr(2)}, `[1]`, []string{"length 1 is less than 2"}},
+		{"unique", ParamConstraints{Unique: true}, `[3,1,3]`, []string{"element 2 repeats 3"}},
+		{"sorted asc", ParamConstraints{Sorted: SortedAsc}, `[1,2,2,5]`, nil},
+		{"not sorted asc", ParamConstraints{Sorted: SortedAsc}, `[1,3,2]`, []string{"elements 1 and 2 are out of asc order"}},
+		{"sorted desc strings", ParamConstraints{Sorted: SortedDesc}, `["c","b","a"]`, nil},
+		{"null", ParamConstraints{Min: ptr(0.0), MinLength: ptr(1)}, `null`, nil},
+		{"several", ParamConstraints{Max: ptr(5.0), MaxLength: ptr(1), Unique: true}, `[7,7]`, []string{"7 is greater than 5", "length 2 is greater than 1", "element 1 repeats 7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			got := tt.constraints.Check(value)
+			if strings.Join(got, "; ") != strings.Join(tt.violations, "; ") {
+				t.Errorf("Expected %q, got %q", tt.violations, got)
+			}
+		})
+	}
+}
+
+func TestParamConstraintsValidate(t *testing.T) {
+	tests := []struct {
+		typ         string
+		constraints ParamConstraints
+		ok          bool
+	}{
+		{"int", ParamConstraints{Min: ptr(1.0), Max: ptr(10.0)}, true},
+		{"int", ParamConstraints{Min: ptr(10.0), Max: ptr(1.0)}, false},
+		{"string", ParamConstraints{Min: ptr(1.0)}, false},
+		{"list<list<int>>", ParamConstraints{Max: ptr(1e9), MaxLength: ptr(100000)}, true},
+		{"int", ParamConstraints{MaxLength: ptr(3)}, false},
+		{"string", ParamConstraints{MinLength: ptr(-1)}, false},
+		{"list<int>", ParamConstraints{Unique: true, Sorted: SortedAsc}, true},
+		{"list<int>", ParamConstraints{Sorted: "up"}, false},
+		{"list<list<int>>", ParamConstraints{Sorted: SortedAsc}, false},
+		{"string", ParamConstraints{Unique: true}, false},
+	}
+
+	for _, tt := range tests {
+		typ, err := GenericType(tt.typ).Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tt.constraints.Validate(typ); (err == nil) != tt.ok {
+			t.Errorf("%s %+v: expected ok=%v, got %v", tt.typ, tt.constraints, tt.ok, err)
+		}
+	}
+}
+
+func TestCheckConstraints(t *testing.T) {
+	n := SchemaParameter{Name: "n", Type: "int", Constraints: &ParamConstraints{Max: ptr(100.0)}}
+	nums := SchemaParameter{Name: "nums", Type: "list<int>", Constraints: &ParamConstraints{Unique: true}}
+
+	decode := func(s string) interface{} {
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	function := ProblemSchema{FunctionName: "f", Parameters: []SchemaParameter{nums, n}}
+	if got := function.CheckConstraints(decode(`[[1,1],101]`)); strings.Join(got, "; ") != "nums: element 1 repeats 1; n: 101 is greater than 100" {
+		t.Errorf("Unexpected violations %q", got)
+	}
+
+	// A lone parameter's value may come with or without its wrapper
+	lone := ProblemSchema{FunctionName: "f", Parameters: []SchemaParameter{nums}}
+	for _, input := range []string{`[2,2]`, `[[2,2]]`} {
+		if got := lone.CheckConstraints(decode(input)); len(got) != 1 {
+			t.Errorf("%s: expected one violation, got %q", input, got)
+		}
+	}
+
+	design := ProblemSchema{Design: &DesignSchema{
+		ClassName:   "Counter",
+		Constructor: []SchemaParameter{n},
+		Methods:     []MethodSchema{{Name: "add", Parameters: []SchemaParameter{n}, ReturnType: "void"}},
+	}}
+	if got := design.CheckConstraints(decode(`[["Counter","add","add"],[[5],[7],[500]]]`)); strings.Join(got, "; ") != "operation 3 (add): n: 500 is greater than 100" {
+		t.Errorf("Unexpected violations %q", got)
+	}
+}
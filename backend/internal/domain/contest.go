@@ -0,0 +1,332 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Contest scoring modes
+const (
+	// ContestScoringICPC ranks by problems solved, then by penalty time: the
+	// minutes from the start to each first accepted submission, plus a fixed
+	// penalty for every rejected attempt before it
+	ContestScoringICPC = "icpc"
+	// ContestScoringIOI ranks by points, a problem being worth the best share of
+	// its score any submission got, or of its tests for problems without subtasks
+	ContestScoringIOI = "ioi"
+)
+
+// Contest visibilities
+const (
+	// ContestVisibilityPublic contests are listed and open to registration
+	ContestVisibilityPublic = "public"
+	// ContestVisibilityPrivate contests are only shown to the users an admin
+	// registered
+	ContestVisibilityPrivate = "private"
+)
+
+// Contest phases, from ContestStatus
+const (
+	ContestStatusUpcoming = "upcoming"
+	ContestStatusRunning  = "running"
+	ContestStatusEnded    = "ended"
+)
+
+// DefaultContestPenaltyMinutes is the ICPC penalty for a rejected attempt
+const DefaultContestPenaltyMinutes = 20
+
+// Contest is a timed round over a set of problems, scored among the users
+// registered to it
+type Contest struct {
+	ID             int       `json:"id" gorm:"primaryKey"`
+	Slug           string    `json:"slug" gorm:"size:255;uniqueIndex;not null"`
+	Title          string    `json:"title" gorm:"size:255;not null"`
+	Description    string    `json:"description" gorm:"type:text"`
+	StartTime      time.Time `json:"start_time" gorm:"not null;index"`
+	EndTime        time.Time `json:"end_time" gorm:"not null"`
+	Scoring        string    `json:"scoring" gorm:"size:20;default:'icpc'"`
+	PenaltyMinutes int       `json:"penalty_minutes"` // defaulted by the usecase, as a column default would turn 0 into 20
+	// FreezeMinutes is how long before the end the public scoreboard stops
+	// showing results, until an admin unfreezes it
+	FreezeMinutes int    `json:"freeze_minutes" gorm:"default:0"`
+	Unfrozen      bool   `json:"unfrozen" gorm:"default:false"`
+	Visibility    string `json:"visibility" gorm:"size:50;default:'private'"`
+	CreatedBy     int    `json:"created_by"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Problems []ContestProblem `json:"problems,omitempty" gorm:"foreignKey:ContestID;constraint:OnDelete:CASCADE"`
+}
+
+// ContestProblem is a problem of a contest, under the label it has there
+type ContestProblem struct {
+	ID        int    `json:"id" gorm:"primaryKey"`
+	ContestID int    `json:"contest_id" gorm:"not null;uniqueIndex:idx_contest_problem"`
+	ProblemID int    `json:"problem_id" gorm:"not null;uniqueIndex:idx_contest_problem"`
+	Label     string `json:"label" gorm:"size:10;not null"` // A, B, C...
+	Points    int    `json:"points" gorm:"default:100"`     // what solving it is worth under IOI scoring
+	Order     int    `json:"order" gorm:"default:0"`
+
+	Problem *Problem `json:"problem,omitempty" gorm:"foreignKey:ProblemID;references:ID"`
+}
+
+// ContestRegistration admits a user to a contest
+type ContestRegistration struct {
+	ID           int       `json:"id" gorm:"primaryKey"`
+	ContestID    int       `json:"contest_id" gorm:"not null;uniqueIndex:idx_contest_registration"`
+	UserID       int       `json:"user_id" gorm:"not null;uniqueIndex:idx_contest_registration"`
+	RegisteredAt time.Time `json:"registered_at" gorm:"autoCreateTime"`
+
+	Contest *Contest `json:"-" gorm:"foreignKey:ContestID;constraint:OnDelete:CASCADE"`
+	User    *User    `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// Status is the phase the contest is in at now
+func (c *Contest) Status(now time.Time) string {
+	switch {
+	case now.Before(c.StartTime):
+		return ContestStatusUpcoming
+	case now.Before(c.EndTime):
+		return ContestStatusRunning
+	default:
+		return ContestStatusEnded
+	}
+}
+
+// FreezeTime is when the scoreboard freezes, or nil if it never does
+func (c *Contest) FreezeTime() *time.Time {
+	if c.FreezeMinutes <= 0 {
+		return nil
+	}
+	freeze := c.EndTime.Add(-time.Duration(c.FreezeMinutes) * time.Minute)
+	return &freeze
+}
+
+// IsFrozen reports whether the public scoreboard hides results at now
+func (c *Contest) IsFrozen(now time.Time) bool {
+	freeze := c.FreezeTime()
+	return freeze != nil && !c.Unfrozen && !now.Before(*freeze)
+}
+
+// Problem returns the contest's entry for problemID, or nil if it isn't part of it
+func (c *Contest) Problem(problemID int) *ContestProblem {
+	for i := range c.Problems {
+		if c.Problems[i].ProblemID == problemID {
+			return &c.Problems[i]
+		}
+	}
+	return nil
+}
+
+// ContestRepository defines the interface for contest persistence
+type ContestRepository interface {
+	Create(contest *Contest) error
+	// Update saves the contest and replaces its problems with contest.Problems
+	Update(contest *Contest) error
+	Delete(id int) error
+	GetByID(id int) (*Contest, error)
+	GetBySlug(slug string) (*Contest, error)
+	SlugExists(slug string, excludeID int) (bool, error)
+	List(visibility string) ([]Contest, error)
+	ListForUser(userID int) ([]Contest, error)
+	SetUnfrozen(id int, unfrozen bool) error
+
+	Register(registration *ContestRegistration) error
+	Unregister(contestID, userID int) error
+	IsRegistered(contestID, userID int) (bool, error)
+	ListRegistrations(contestID int) ([]ContestRegistration, error)
+}
+
+// Scoreboard is the standings of a contest
+type Scoreboard struct {
+	ContestID  int                 `json:"contest_id"`
+	Scoring    string              `json:"scoring"`
+	Frozen     bool                `json:"frozen"`
+	FreezeTime *time.Time          `json:"freeze_time,omitempty"`
+	Problems   []ScoreboardProblem `json:"problems"`
+	Rows       []ScoreboardRow     `json:"rows"`
+}
+
+type ScoreboardProblem struct {
+	ProblemID int    `json:"problem_id"`
+	Label     string `json:"label"`
+	Title     string `json:"title"`
+	Points    int    `json:"points"`
+}
+
+type ScoreboardRow struct {
+	Rank     int              `json:"rank"`
+	UserID   int              `json:"user_id"`
+	Username string           `json:"username"`
+	Solved   int              `json:"solved"`
+	Penalty  int              `json:"penalty"` // minutes, under ICPC scoring
+	Score    float64          `json:"score"`   // points, under IOI scoring
+	Cells    []ScoreboardCell `json:"cells"`   // in the order of Scoreboard.Problems
+}
+
+// ScoreboardCell is how a user did on one problem
+type ScoreboardCell struct {
+	ProblemID int `json:"problem_id"`
+	// Attempts counts the judged submissions up to and including the first
+	// accepted one; compilation and internal errors don't count
+	Attempts int `json:"attempts"`
+	// Pending counts the submissions not judged yet, or made after the freeze
+	Pending      int     `json:"pending"`
+	Solved       bool    `json:"solved"`
+	SolvedAt     int     `json:"solved_at,omitempty"` // minutes from the start
+	FirstToSolve bool    `json:"first_to_solve,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+// countsAsAttempt reports whether a judged submission with status counts
+// against the user; compilation and internal errors are forgiven
+func countsAsAttempt(status SubmissionStatus) bool {
+	return status != SubmissionStatusCompilationError && status != SubmissionStatusInternalError
+}
+
+func isJudged(status SubmissionStatus) bool {
+	return status != SubmissionStatusPending && status != SubmissionStatusProcessing
+}
+
+// scoreShare is the part of a problem's points a judged submission earns under IOI
+// scoring: its share of the problem's score when the problem has subtasks, else of
+// the tests it passed
+func scoreShare(problem *Problem, s *Submission) (float64, bool) {
+	if problem != nil && problem.HasSubtasks() {
+		if problem.MaxScore() == 0 {
+			return 0, false
+		}
+		return float64(s.Score) / float64(problem.MaxScore()), true
+	}
+	if s.TotalTestCases == 0 {
+		return 0, false
+	}
+	return float64(s.PassedTestCases) / float64(s.TotalTestCases), true
+}
+
+// BuildScoreboard ranks the registered users of a contest from its submissions.
+// With frozen set, submissions made after the freeze show as pending.
+func BuildScoreboard(contest *Contest, registrations []ContestRegistration, submissions []Submission, frozen bool) *Scoreboard {
+	board := &Scoreboard{
+		ContestID:  contest.ID,
+		Scoring:    contest.Scoring,
+		Frozen:     frozen,
+		FreezeTime: contest.FreezeTime(),
+		Problems:   make([]ScoreboardProblem, len(contest.Problems)),
+		Rows:       make([]ScoreboardRow, 0, len(registrations)),
+	}
+
+	column := make(map[int]int, len(contest.Problems))
+	for i, cp := range contest.Problems {
+		board.Problems[i] = ScoreboardProblem{ProblemID: cp.ProblemID, Label: cp.Label, Points: cp.Points}
+		if cp.Problem != nil {
+			board.Problems[i].Title = cp.Problem.Title
+		}
+		column[cp.ProblemID] = i
+	}
+
+	row := make(map[int]int, len(registrations))
+	for _, reg := range registrations {
+		r := ScoreboardRow{UserID: reg.UserID, Cells: make([]ScoreboardCell, len(contest.Problems))}
+		if reg.User != nil {
+			r.Username = reg.User.Username
+		}
+		for i := range r.Cells {
+			r.Cells[i].ProblemID = board.Problems[i].ProblemID
+		}
+		row[reg.UserID] = len(board.Rows)
+		board.Rows = append(board.Rows, r)
+	}
+
+	sorted := append([]Submission(nil), submissions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	freeze := contest.FreezeTime()
+	firstSolver := make(map[int]*ScoreboardCell)
+	for _, s := range sorted {
+		ri, ok := row[s.UserID]
+		ci, inContest := column[s.ProblemID]
+		if !ok || !inContest || s.IsRunOnly || s.CreatedAt.Before(contest.StartTime) || !s.CreatedAt.Before(contest.EndTime) {
+			continue
+		}
+		cell := &board.Rows[ri].Cells[ci]
+
+		if !isJudged(s.Status) || (frozen && freeze != nil && !s.CreatedAt.Before(*freeze)) {
+			if !cell.Solved {
+				cell.Pending++
+			}
+			continue
+		}
+
+		if share, ok := scoreShare(contest.Problems[ci].Problem, &s); ok {
+			score := float64(board.Problems[ci].Points) * share
+			cell.Score = max(cell.Score, math.Round(score*100)/100)
+		}
+		if cell.Solved || !countsAsAttempt(s.Status) {
+			continue
+		}
+		cell.Attempts++
+		if s.Status == SubmissionStatusAccepted {
+			cell.Solved = true
+			cell.SolvedAt = int(s.CreatedAt.Sub(contest.StartTime).Minutes())
+			cell.Score = float64(board.Problems[ci].Points)
+			// Submissions are in order, so the first accepted is the earliest
+			if _, ok := firstSolver[s.ProblemID]; !ok {
+				firstSolver[s.ProblemID] = cell
+			}
+		}
+	}
+	for _, cell := range firstSolver {
+		cell.FirstToSolve = true
+	}
+
+	for i := range board.Rows {
+		r := &board.Rows[i]
+		for _, cell := range r.Cells {
+			r.Score += cell.Score
+			if cell.Solved {
+				r.Solved++
+				r.Penalty += cell.SolvedAt + (cell.Attempts-1)*contest.PenaltyMinutes
+			}
+		}
+		r.Score = math.Round(r.Score*100) / 100
+	}
+
+	rankScoreboard(board)
+	return board
+}
+
+// rankScoreboard orders the rows and ranks them, equal rows sharing a rank
+func rankScoreboard(board *Scoreboard) {
+	compare := func(a, b *ScoreboardRow) int {
+		if board.Scoring == ContestScoringIOI {
+			switch {
+			case a.Score > b.Score:
+				return -1
+			case a.Score < b.Score:
+				return 1
+			}
+			return 0
+		}
+		if a.Solved != b.Solved {
+			return b.Solved - a.Solved
+		}
+		return a.Penalty - b.Penalty
+	}
+
+	sort.SliceStable(board.Rows, func(i, j int) bool {
+		if c := compare(&board.Rows[i], &board.Rows[j]); c != 0 {
+			return c < 0
+		}
+		return board.Rows[i].Username < board.Rows[j].Username
+	})
+	for i := range board.Rows {
+		if i > 0 && compare(&board.Rows[i-1], &board.Rows[i]) == 0 {
+			board.Rows[i].Rank = board.Rows[i-1].Rank
+		} else {
+			board.Rows[i].Rank = i + 1
+		}
+	}
+}
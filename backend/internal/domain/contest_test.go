@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func testContest(scoring string) *Contest {
+	start := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	return &Contest{
+		ID:             1,
+		StartTime:      start,
+		EndTime:        start.Add(2 * time.Hour),
+		Scoring:        scoring,
+		PenaltyMinutes: DefaultContestPenaltyMinutes,
+		FreezeMinutes:  30,
+		Problems: []ContestProblem{
+			{ProblemID: 10, Label: "A", Points: 100},
+			{ProblemID: 20, Label: "B", Points: 50},
+		},
+	}
+}
+
+func testSubmission(c *Contest, userID, problemID, minute int, status SubmissionStatus, passed int) Submission {
+	return Submission{
+		UserID:          userID,
+		ProblemID:       problemID,
+		ContestID:       &c.ID,
+		Status:          status,
+		PassedTestCases: passed,
+		TotalTestCases:  4,
+		CreatedAt:       c.StartTime.Add(time.Duration(minute) * time.Minute),
+	}
+}
+
+var testRegistrations = []ContestRegistration{
+	{UserID: 1, User: &User{Username: "ada"}},
+	{UserID: 2, User: &User{Username: "bob"}},
+	{UserID: 3, User: &User{Username: "cyd"}},
+}
+
+func TestBuildScoreboardICPC(t *testing.T) {
+	c := testContest(ContestScoringICPC)
+	submissions := []Submission{
+		// ada solves A at 30 after a wrong answer and a forgiven compilation error
+		testSubmission(c, 1, 10, 5, SubmissionStatusWrongAnswer, 2),
+		testSubmission(c, 1, 10, 10, SubmissionStatusCompilationError, 0),
+		testSubmission(c, 1, 10, 30, SubmissionStatusAccepted, 4),
+		testSubmission(c, 1, 10, 40, SubmissionStatusWrongAnswer, 0),
+		// bob solves A at 20 and B at 50
+		testSubmission(c, 2, 10, 20, SubmissionStatusAccepted, 4),
+		testSubmission(c, 2, 20, 50, SubmissionStatusAccepted, 4),
+		// cyd solves B after the freeze, and submits after the end and to another problem
+		testSubmission(c, 3, 20, 100, SubmissionStatusAccepted, 4),
+		testSubmission(c, 3, 10, 125, SubmissionStatusAccepted, 4),
+		testSubmission(c, 3, 30, 15, SubmissionStatusAccepted, 4),
+		// Not registered
+		testSubmission(c, 4, 10, 1, SubmissionStatusAccepted, 4),
+	}
+
+	board := BuildScoreboard(c, testRegistrations, submissions, false)
+	want := []struct {
+		username string
+		rank     int
+		solved   int
+		penalty  int
+	}{
+		{"bob", 1, 2, 70},
+		{"ada", 2, 1, 50},
+		{"cyd", 3, 1, 100},
+	}
+	for i, w := range want {
+		r := board.Rows[i]
+		if r.Username != w.username || r.Rank != w.rank || r.Solved != w.solved || r.Penalty != w.penalty {
+			t.Errorf("Row %d: expected %+v, got %+v", i, w, r)
+		}
+	}
+
+	ada := board.Rows[1].Cells[0]
+	if ada.Attempts != 2 || !ada.Solved || ada.SolvedAt != 30 || ada.FirstToSolve {
+		t.Errorf("Unexpected cell %+v", ada)
+	}
+	if bob := board.Rows[0].Cells[0]; !bob.FirstToSolve {
+		t.Errorf("Expected bob to be first to solve A, got %+v", bob)
+	}
+
+	// Frozen, cyd's solve shows as pending
+	frozen := BuildScoreboard(c, testRegistrations, submissions, true)
+	cyd := frozen.Rows[2]
+	if cyd.Username != "cyd" || cyd.Solved != 0 || cyd.Cells[1].Pending != 1 || cyd.Rank != 3 {
+		t.Errorf("Expected cyd's solve pending, got %+v", cyd)
+	}
+	if !frozen.Frozen || frozen.FreezeTime == nil || !frozen.FreezeTime.Equal(c.EndTime.Add(-30*time.Minute)) {
+		t.Errorf("Unexpected freeze %v at %v", frozen.Frozen, frozen.FreezeTime)
+	}
+}
+
+func TestBuildScoreboardIOI(t *testing.T) {
+	c := testContest(ContestScoringIOI)
+	submissions := []Submission{
+		testSubmission(c, 1, 10, 5, SubmissionStatusWrongAnswer, 3),
+		testSubmission(c, 1, 10, 6, SubmissionStatusWrongAnswer, 1),
+		testSubmission(c, 1, 20, 7, SubmissionStatusAccepted, 4),
+		testSubmission(c, 2, 10, 8, SubmissionStatusAccepted, 4),
+		testSubmission(c, 2, 20, 9, SubmissionStatusPending, 0),
+		testSubmission(c, 3, 10, 10, SubmissionStatusTimeLimitExceeded, 1),
+		testSubmission(c, 3, 20, 11, SubmissionStatusWrongAnswer, 2),
+	}
+
+	board := BuildScoreboard(c, testRegistrations, submissions, false)
+	want := []struct {
+		username string
+		rank     int
+		score    float64
+	}{
+		{"ada", 1, 125},
+		{"bob", 2, 100},
+		{"cyd", 3, 50},
+	}
+	for i, w := range want {
+		r := board.Rows[i]
+		if r.Username != w.username || r.Rank != w.rank || r.Score != w.score {
+			t.Errorf("Row %d: expected %+v, got %+v", i, w, r)
+		}
+	}
+	if pending := board.Rows[1].Cells[1].Pending; pending != 1 {
+		t.Errorf("Expected bob's submission to B pending, got %d", pending)
+	}
+}
+
+func TestBuildScoreboardIOISubtasks(t *testing.T) {
+	c := testContest(ContestScoringIOI)
+	c.Problems[0].Problem = &Problem{ID: 10, Subtasks: Subtasks{{Name: "small", Points: 30}, {Name: "large", Points: 70}}}
+	partial := testSubmission(c, 1, 10, 5, SubmissionStatusWrongAnswer, 3)
+	partial.Score = 30
+	worse := testSubmission(c, 1, 10, 6, SubmissionStatusWrongAnswer, 3)
+	worse.Score = 0
+
+	board := BuildScoreboard(c, testRegistrations, []Submission{partial, worse}, false)
+	if got := board.Rows[0].Cells[0].Score; got != 30 {
+		t.Errorf("Expected ada's best subtask score of 30 for A, got %v", got)
+	}
+}
+
+func TestContestStatus(t *testing.T) {
+	c := testContest(ContestScoringICPC)
+	if got := c.Status(c.StartTime.Add(-time.Second)); got != ContestStatusUpcoming {
+		t.Errorf("Expected upcoming, got %s", got)
+	}
+	if got := c.Status(c.StartTime); got != ContestStatusRunning {
+		t.Errorf("Expected running, got %s", got)
+	}
+	if got := c.Status(c.EndTime); got != ContestStatusEnded {
+		t.Errorf("Expected ended, got %s", got)
+	}
+	if c.IsFrozen(c.EndTime.Add(-31*time.Minute)) || !c.IsFrozen(c.EndTime.Add(time.Hour)) {
+		t.Error("Expected the scoreboard frozen for the last 30 minutes and after")
+	}
+	c.Unfrozen = true
+	if c.IsFrozen(c.EndTime) {
+		t.Error("Expected an unfrozen scoreboard to stay unfrozen")
+	}
+}
@@ -11,6 +11,8 @@ type AdminAnalytics struct {
 	PendingSubmissions int                          `json:"pending_submissions"`
 	ActiveWorkers      int                          `json:"active_workers"`
 	QueueSize          int64                        `json:"queue_size"`
+	QueueLanes         map[string]int64             `json:"queue_lanes"`
+	DeadLetterSize     int64                        `json:"dead_letter_size"`
 	OldestPendingAge   int64                        `json:"oldest_pending_age_seconds"`
 	QueueHealthStatus  string                       `json:"queue_health_status"`
 	SubmissionHistory  []domain.DailySubmissionStat `json:"submission_history"`
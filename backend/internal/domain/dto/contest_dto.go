@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// ContestProblemRequest adds a problem to a contest
+type ContestProblemRequest struct {
+	ProblemID int    `json:"problem_id" validate:"required"`
+	Label     string `json:"label" validate:"required,max=10"`
+	Points    int    `json:"points" validate:"omitempty,min=0"` // defaults to 100
+}
+
+// CreateContestRequest for admin contest creation; updates take the same body
+// and replace the whole contest, problems included
+type CreateContestRequest struct {
+	Title          string                  `json:"title" validate:"required,max=255"`
+	Slug           string                  `json:"slug,omitempty"`
+	Description    string                  `json:"description"`
+	StartTime      time.Time               `json:"start_time" validate:"required"`
+	EndTime        time.Time               `json:"end_time" validate:"required,gtfield=StartTime"`
+	Scoring        string                  `json:"scoring" validate:"omitempty,oneof=icpc ioi"`
+	PenaltyMinutes *int                    `json:"penalty_minutes,omitempty" validate:"omitempty,min=0"` // defaults to 20
+	FreezeMinutes  int                     `json:"freeze_minutes" validate:"omitempty,min=0"`
+	Visibility     string                  `json:"visibility" validate:"omitempty,oneof=public private"`
+	Problems       []ContestProblemRequest `json:"problems" validate:"required,min=1"`
+}
+
+// RegisterContestUserRequest for admins registering a user to a contest
+type RegisterContestUserRequest struct {
+	UserID int `json:"user_id" validate:"required"`
+}
+
+type ContestProblemResponse struct {
+	ProblemID  int    `json:"problem_id"`
+	Label      string `json:"label"`
+	Points     int    `json:"points"`
+	Title      string `json:"title"`
+	Slug       string `json:"slug"`
+	Difficulty string `json:"difficulty"`
+}
+
+type ContestResponse struct {
+	ID             int                      `json:"id"`
+	Slug           string                   `json:"slug"`
+	Title          string                   `json:"title"`
+	Description    string                   `json:"description"`
+	StartTime      time.Time                `json:"start_time"`
+	EndTime        time.Time                `json:"end_time"`
+	Scoring        string                   `json:"scoring"`
+	PenaltyMinutes int                      `json:"penalty_minutes"`
+	FreezeMinutes  int                      `json:"freeze_minutes"`
+	Unfrozen       bool                     `json:"unfrozen"`
+	Visibility     string                   `json:"visibility"`
+	Status         string                   `json:"status"` // upcoming, running or ended
+	Registered     bool                     `json:"registered"`
+	Problems       []ContestProblemResponse `json:"problems,omitempty"`
+}
+
+// ToContestResponse describes a contest as it is at now; its problems are left
+// out unless withProblems is set
+func ToContestResponse(c *domain.Contest, now time.Time, withProblems bool) ContestResponse {
+	resp := ContestResponse{
+		ID:             c.ID,
+		Slug:           c.Slug,
+		Title:          c.Title,
+		Description:    c.Description,
+		StartTime:      c.StartTime,
+		EndTime:        c.EndTime,
+		Scoring:        c.Scoring,
+		PenaltyMinutes: c.PenaltyMinutes,
+		FreezeMinutes:  c.FreezeMinutes,
+		Unfrozen:       c.Unfrozen,
+		Visibility:     c.Visibility,
+		Status:         c.Status(now),
+	}
+
+	if withProblems {
+		for _, cp := range c.Problems {
+			p := ContestProblemResponse{ProblemID: cp.ProblemID, Label: cp.Label, Points: cp.Points}
+			if cp.Problem != nil {
+				p.Title = cp.Problem.Title
+				p.Slug = cp.Problem.Slug
+				p.Difficulty = cp.Problem.Difficulty
+			}
+			resp.Problems = append(resp.Problems, p)
+		}
+	}
+
+	return resp
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "gorm.io/datatypes"
+import (
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"gorm.io/datatypes"
+)
 
 // CreateProblemRequest defines the payload for creating a problem
 type CreateProblemRequest struct {
@@ -20,14 +23,39 @@ type CreateProblemRequest struct {
 	Constraints  string `json:"constraints"`
 	Hints        string `json:"hints"`
 
+	// JudgeMode is function (default), design, stdio or interactive; only function
+	// problems have a signature, design problems have a design instead
+	JudgeMode string `json:"judge_mode"`
+
 	// Core V2 Fields
 	FunctionName            string          `json:"function_name"`
 	ReturnType              string          `json:"return_type"`
 	Parameters              *datatypes.JSON `json:"parameters"` // Using datatypes.JSON for flexibility
+	Design                  *datatypes.JSON `json:"design"`     // domain.DesignSchema, required for design problems
 	ValidationType          string          `json:"validation_type"`
 	ExpectedTimeComplexity  string          `json:"expected_time_complexity"`
 	ExpectedSpaceComplexity string          `json:"expected_space_complexity"`
 
+	// Checker (special judge), required when ValidationType is CUSTOM
+	CheckerLanguage string `json:"checker_language"`
+	CheckerCode     string `json:"checker_code"`
+
+	// Interactor, required for interactive problems
+	InteractorLanguage string `json:"interactor_language"`
+	InteractorCode     string `json:"interactor_code"`
+	QueryLimit         int    `json:"query_limit"`
+
+	// Generator of test inputs, optional
+	GeneratorLanguage string `json:"generator_language"`
+	GeneratorCode     string `json:"generator_code"`
+
+	// Validator of test inputs, optional
+	ValidatorLanguage string `json:"validator_language"`
+	ValidatorCode     string `json:"validator_code"`
+
+	// Subtasks score submissions by groups of tests, optional
+	Subtasks domain.Subtasks `json:"subtasks"`
+
 	TestCases []TestCaseInput `json:"test_cases"`
 }
 
@@ -49,16 +77,37 @@ type UpdateProblemRequest struct {
 	Constraints  *string `json:"constraints"`
 	Hints        *string `json:"hints"`
 
+	JudgeMode *string `json:"judge_mode"`
+
 	FunctionName            *string         `json:"function_name"`
 	ReturnType              *string         `json:"return_type"`
 	Parameters              *datatypes.JSON `json:"parameters"`
+	Design                  *datatypes.JSON `json:"design"`
 	ValidationType          *string         `json:"validation_type"`
 	ExpectedTimeComplexity  *string         `json:"expected_time_complexity"`
 	ExpectedSpaceComplexity *string         `json:"expected_space_complexity"`
 
+	CheckerLanguage *string `json:"checker_language"`
+	CheckerCode     *string `json:"checker_code"`
+
+	InteractorLanguage *string `json:"interactor_language"`
+	InteractorCode     *string `json:"interactor_code"`
+	QueryLimit         *int    `json:"query_limit"`
+
+	GeneratorLanguage *string `json:"generator_language"`
+	GeneratorCode     *string `json:"generator_code"`
+
+	ValidatorLanguage *string `json:"validator_language"`
+	ValidatorCode     *string `json:"validator_code"`
+
+	Subtasks *domain.Subtasks `json:"subtasks"` // an empty list removes them
+
 	TestCases []TestCaseInput `json:"test_cases"`
 }
 
+// TestCaseInput is a test case of a problem; for stdio problems the input and
+// expected output are text, for design problems the operations with their
+// arguments and what each returns, see domain.DesignSchema
 type TestCaseInput struct {
 	Input          interface{} `json:"input"`
 	ExpectedOutput interface{} `json:"expected_output"`
@@ -66,6 +115,9 @@ type TestCaseInput struct {
 	InputSize      *int        `json:"input_size"`
 	TimeLimitMs    *int        `json:"time_limit_ms"`
 	MemoryLimitMb  *int        `json:"memory_limit_mb"`
+	Subtask        string      `json:"subtask"` // name of one of the problem's subtasks
+
+	ValidationConfig domain.ValidationConfig `json:"validation_config"`
 }
 
 type ListProblemsRequest struct {
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// RejudgeRequest picks the submissions an admin wants judged again: one
+// submission, all of a problem's, those in a language, those made in [from, to),
+// or any combination of these
+type RejudgeRequest struct {
+	SubmissionID *int       `json:"submission_id,omitempty"`
+	ProblemID    *int       `json:"problem_id,omitempty"`
+	LanguageID   *int       `json:"language_id,omitempty"`
+	From         *time.Time `json:"from,omitempty"`
+	To           *time.Time `json:"to,omitempty"`
+}
+
+// RejudgeResponse is a rejudge with how each of its submissions did
+type RejudgeResponse struct {
+	domain.Rejudge
+	Results []domain.RejudgeResult `json:"results"`
+}
@@ -29,12 +29,18 @@ type SubmissionResponse struct {
 	Memory          int                     `json:"memory"`
 	PassedTestCases int                     `json:"passed_test_cases"`
 	TotalTestCases  int                     `json:"total_test_cases"`
+	Score           int                     `json:"score"`
+	SubtaskResults  domain.SubtaskResults   `json:"subtask_results,omitempty"`
 	CreatedAt       time.Time               `json:"created_at"`
 	IsRunOnly       bool                    `json:"is_run_only"`
+	ContestID       *int                    `json:"contest_id,omitempty"`
 	TestCaseResults domain.TestCaseResults  `json:"test_case_results,omitempty"`
 	User            *UserResponse           `json:"user,omitempty"`
 	Problem         *ProblemResponse        `json:"problem,omitempty"`
 	Language        *LanguageResponse       `json:"language,omitempty"`
+
+	// Where an accepted submission stands in its Language, when fetched on its own
+	Ranking *domain.PerformanceRanking `json:"ranking,omitempty"`
 }
 
 type ProblemResponse struct {
@@ -71,6 +77,9 @@ func ToSubmissionResponse(s *domain.Submission) SubmissionResponse {
 		TotalTestCases:  s.TotalTestCases,
 		CreatedAt:       s.CreatedAt,
 		IsRunOnly:       s.IsRunOnly,
+		ContestID:       s.ContestID,
+		Score:           s.Score,
+		SubtaskResults:  s.SubtaskResults,
 		TestCaseResults: s.TestCaseResults,
 	}
 
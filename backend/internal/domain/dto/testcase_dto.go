@@ -10,6 +10,7 @@ type CreateTestCaseRequest struct {
 	IsSample         bool                    `json:"is_sample"`
 	ValidationConfig domain.ValidationConfig `json:"validation_config"`
 	OrderIndex       int                     `json:"order_index"`
+	Subtask          string                  `json:"subtask"` // name of one of the problem's subtasks
 }
 
 // UpdateTestCaseRequest for updating test cases
@@ -19,6 +20,7 @@ type UpdateTestCaseRequest struct {
 	IsSample         *bool                   `json:"is_sample"`
 	ValidationConfig domain.ValidationConfig `json:"validation_config"`
 	OrderIndex       *int                    `json:"order_index"`
+	Subtask          *string                 `json:"subtask"` // empty takes the test out of its subtask
 }
 
 // ReorderTestCasesRequest for bulk reordering
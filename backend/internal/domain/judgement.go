@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// SubmissionJudgement records one attempt at judging a submission: who judged it
+// with what, when, and the verdict it reached. Judgements are only ever added, so a
+// submission's judgements are the history of its verdicts across worker retries
+// and rejudges; the submission itself points at the current one.
+type SubmissionJudgement struct {
+	ID             int    `json:"id" gorm:"primaryKey"`
+	SubmissionID   int    `json:"submission_id" gorm:"not null;index"`
+	RejudgeID      *int   `json:"rejudge_id,omitempty" gorm:"index"`
+	WorkerID       string `json:"worker_id" gorm:"size:100"`
+	Executor       string `json:"executor" gorm:"size:50"`        // executor driver, e.g. "piston"
+	HarnessVersion string `json:"harness_version" gorm:"size:50"` // empty for programs judged without a harness
+
+	// Timings: when the submission was queued, and when judging started and ended
+	QueuedAt   *time.Time `json:"queued_at,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt time.Time  `json:"finished_at"`
+
+	Status          SubmissionStatus `json:"status" gorm:"type:varchar(50)"`
+	ErrorMessage    string           `json:"error_message,omitempty" gorm:"type:text"`
+	Score           int              `json:"score"`
+	PassedTestCases int              `json:"passed_test_cases"`
+	TotalTestCases  int              `json:"total_test_cases"`
+	Runtime         int              `json:"runtime"` // in milliseconds
+	Memory          int              `json:"memory"`  // in kilobytes
+	TestCaseResults TestCaseResults  `json:"test_case_results,omitempty" gorm:"type:jsonb"`
+	SubtaskResults  SubtaskResults   `json:"subtask_results,omitempty" gorm:"type:jsonb"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Finish stores the submission's verdict in the judgement
+func (j *SubmissionJudgement) Finish(s *Submission, finishedAt time.Time) {
+	j.SubmissionID = s.ID
+	j.FinishedAt = finishedAt
+	j.Status = s.Status
+	j.ErrorMessage = s.ErrorMessage
+	j.Score = s.Score
+	j.PassedTestCases = s.PassedTestCases
+	j.TotalTestCases = s.TotalTestCases
+	j.Runtime = s.Runtime
+	j.Memory = s.Memory
+	j.TestCaseResults = s.TestCaseResults
+	j.SubtaskResults = s.SubtaskResults
+}
+
+// SubmissionJudgementRepository defines the interface for judgement persistence.
+// Judgements are append-only, so there is no way to update or delete one.
+type SubmissionJudgementRepository interface {
+	Create(judgement *SubmissionJudgement) error
+	GetByID(id int) (*SubmissionJudgement, error)
+	// ListBySubmission returns a submission's judgements, oldest first, without their
+	// test results
+	ListBySubmission(submissionID int) ([]SubmissionJudgement, error)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import "math"
+
+// Fallback limits for problems created before limits were mandatory; they match the
+// column defaults on Problem
+const (
+	DefaultTimeLimitMs   = 1000
+	DefaultMemoryLimitMb = 256
+)
+
+// Keys in Language.ExecutorConfig that scale a problem's limits for slower runtimes,
+// e.g. {"time_multiplier": 3, "memory_multiplier": 2} for Python
+const (
+	ExecutorConfigTimeMultiplier   = "time_multiplier"
+	ExecutorConfigMemoryMultiplier = "memory_multiplier"
+)
+
+// ExecutionLimits are the time and memory budgets for running code
+type ExecutionLimits struct {
+	TimeMs   int `json:"time_limit_ms"`
+	MemoryMb int `json:"memory_limit_mb"`
+}
+
+// Limits returns the limits that apply to this test case: its own overrides where set,
+// otherwise the problem's limits
+func (tc *TestCase) Limits(problem *Problem) ExecutionLimits {
+	limits := ExecutionLimits{TimeMs: DefaultTimeLimitMs, MemoryMb: DefaultMemoryLimitMb}
+	if problem != nil {
+		if problem.TimeLimit > 0 {
+			limits.TimeMs = problem.TimeLimit
+		}
+		if problem.MemoryLimit > 0 {
+			limits.MemoryMb = problem.MemoryLimit
+		}
+	}
+
+	if tc.TimeLimitMs != nil && *tc.TimeLimitMs > 0 {
+		limits.TimeMs = *tc.TimeLimitMs
+	}
+	if tc.MemoryLimitMb != nil && *tc.MemoryLimitMb > 0 {
+		limits.MemoryMb = *tc.MemoryLimitMb
+	}
+
+	return limits
+}
+
+// ScaleLimits applies the language's multipliers to limits
+func (l *Language) ScaleLimits(limits ExecutionLimits) ExecutionLimits {
+	if l == nil {
+		return limits
+	}
+	return ExecutionLimits{
+		TimeMs:   int(math.Ceil(float64(limits.TimeMs) * l.ExecutorConfig.Multiplier(ExecutorConfigTimeMultiplier))),
+		MemoryMb: int(math.Ceil(float64(limits.MemoryMb) * l.ExecutorConfig.Multiplier(ExecutorConfigMemoryMultiplier))),
+	}
+}
+
+// Multiplier reads a positive numeric multiplier, defaulting to 1
+func (ec ExecutorConfig) Multiplier(key string) float64 {
+	var m float64
+	switch v := ec[key].(type) {
+	case float64:
+		m = v
+	case int:
+		m = float64(v)
+	}
+	if m <= 0 {
+		return 1
+	}
+	return m
+}
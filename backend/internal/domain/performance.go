@@ -0,0 +1,78 @@
+package domain
+
+import "math"
+
+// Keys in Language.ExecutorConfig for what a run costs before the submitted code does
+// anything, e.g. {"baseline_runtime_ms": 20, "baseline_memory_kb": 9000} for starting
+// the Python interpreter and its harness. Usage the executor measured for a process
+// is reported net of them, so a submission's runtime and memory are its own. Every
+// driver runs each test in a process of its own to measure it. Left unset, every
+// submission in a language carries the same startup cost, which shifts its numbers
+// but not its ranking.
+const (
+	ExecutorConfigBaselineRuntimeMs = "baseline_runtime_ms"
+	ExecutorConfigBaselineMemoryKB  = "baseline_memory_kb"
+)
+
+// Baseline reads a non-negative numeric baseline, defaulting to 0
+func (ec ExecutorConfig) Baseline(key string) int {
+	var b float64
+	switch v := ec[key].(type) {
+	case float64:
+		b = v
+	case int:
+		b = float64(v)
+	}
+	if b <= 0 {
+		return 0
+	}
+	return int(b)
+}
+
+// NormaliseUsage takes the language's baselines off the CPU time and peak memory the
+// executor measured for a run, never going below zero
+func (l *Language) NormaliseUsage(runtimeMs, memoryKB int) (int, int) {
+	if l == nil {
+		return runtimeMs, memoryKB
+	}
+	runtimeMs = max(0, runtimeMs-l.ExecutorConfig.Baseline(ExecutorConfigBaselineRuntimeMs))
+	memoryKB = max(0, memoryKB-l.ExecutorConfig.Baseline(ExecutorConfigBaselineMemoryKB))
+	return runtimeMs, memoryKB
+}
+
+// IsRanked reports whether the submission takes part in performance rankings: an
+// accepted submission of a user, not a run or an admin's test
+func (s *Submission) IsRanked() bool {
+	return s.Status == SubmissionStatusAccepted && !s.IsRunOnly && !s.IsAdminSubmission && !s.IsValidationSubmission
+}
+
+// PerformanceCounts are how a ranked submission compares with the ranked submissions
+// in its language to the same problem
+type PerformanceCounts struct {
+	Total   int64 // ranked submissions, this one included
+	Slower  int64 // those with a higher runtime
+	Heavier int64 // those with a higher peak memory
+}
+
+// PerformanceRanking places an accepted submission among the accepted submissions
+// in its language to the same problem: "faster than 87% of Python submissions"
+type PerformanceRanking struct {
+	RuntimePercentile float64 `json:"runtime_percentile"` // share of the others that ran slower
+	MemoryPercentile  float64 `json:"memory_percentile"`  // share of the others that used more memory
+	Submissions       int64   `json:"submissions"`        // how many it was ranked among, itself included
+}
+
+// Rank turns the counts into percentiles of the other submissions, to one decimal
+// place. A submission without any others to compare with beats them all.
+func (c PerformanceCounts) Rank() *PerformanceRanking {
+	ranking := &PerformanceRanking{RuntimePercentile: 100, MemoryPercentile: 100, Submissions: c.Total}
+	if others := c.Total - 1; others > 0 {
+		ranking.RuntimePercentile = percentOf(c.Slower, others)
+		ranking.MemoryPercentile = percentOf(c.Heavier, others)
+	}
+	return ranking
+}
+
+func percentOf(n, total int64) float64 {
+	return math.Round(float64(n)*1000/float64(total)) / 10
+}
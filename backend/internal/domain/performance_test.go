@@ -0,0 +1,35 @@
+package domain
+
+import "testing"
+
+func TestNormaliseUsage(t *testing.T) {
+	python := &Language{ExecutorConfig: ExecutorConfig{
+		ExecutorConfigBaselineRuntimeMs: 20.0,
+		ExecutorConfigBaselineMemoryKB:  9000.0,
+	}}
+
+	if runtime, memory := python.NormaliseUsage(65, 12500); runtime != 45 || memory != 3500 {
+		t.Errorf("Expected 45 ms and 3500 KB net of the baselines, got %d ms and %d KB", runtime, memory)
+	}
+	if runtime, memory := python.NormaliseUsage(12, 8000); runtime != 0 || memory != 0 {
+		t.Errorf("Expected usage under the baselines to floor at zero, got %d ms and %d KB", runtime, memory)
+	}
+	if runtime, memory := (&Language{}).NormaliseUsage(65, 12500); runtime != 65 || memory != 12500 {
+		t.Errorf("Expected usage unchanged without baselines, got %d ms and %d KB", runtime, memory)
+	}
+}
+
+func TestPerformanceCountsRank(t *testing.T) {
+	ranking := PerformanceCounts{Total: 9, Slower: 7, Heavier: 2}.Rank()
+	if ranking.RuntimePercentile != 87.5 || ranking.MemoryPercentile != 25 || ranking.Submissions != 9 {
+		t.Errorf("Expected faster than 87.5%% and lighter than 25%% of 8 others, got %+v", ranking)
+	}
+
+	if ranking := (PerformanceCounts{Total: 4, Slower: 1}).Rank(); ranking.RuntimePercentile != 33.3 {
+		t.Errorf("Expected percentiles rounded to one decimal place, got %v", ranking.RuntimePercentile)
+	}
+
+	if ranking := (PerformanceCounts{Total: 1}).Rank(); ranking.RuntimePercentile != 100 || ranking.MemoryPercentile != 100 {
+		t.Errorf("Expected the only submission to beat them all, got %+v", ranking)
+	}
+}
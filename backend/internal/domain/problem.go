@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/datatypes"
@@ -31,6 +34,10 @@ type Problem struct {
 	Tags             []Tag      `json:"tags,omitempty" gorm:"many2many:problem_tags"`
 	Categories       []Category `json:"categories,omitempty" gorm:"many2many:problem_categories"`
 
+	// JudgeMode is how submissions are run, see JudgeModeFunction, JudgeModeDesign,
+	// JudgeModeStdio and JudgeModeInteractive
+	JudgeMode string `json:"judge_mode" gorm:"size:20;default:function"`
+
 	// Standardized Fields (Formerly V2)
 	FunctionName            *string         `json:"function_name,omitempty" gorm:"size:255"`
 	ReturnType              *string         `json:"return_type,omitempty" gorm:"size:100"`
@@ -41,6 +48,33 @@ type Problem struct {
 	ExpectedSpaceComplexity *string         `json:"expected_space_complexity,omitempty" gorm:"size:50"`
 	HasReferenceSolution    bool            `json:"has_reference_solution" gorm:"default:false"`
 
+	// Design is the DesignSchema of a design problem, in place of the function above
+	Design *datatypes.JSON `json:"design,omitempty" gorm:"type:jsonb"`
+
+	// Checker (special judge) that decides answers when ValidationType is CUSTOM
+	CheckerLanguage *string `json:"checker_language,omitempty" gorm:"size:50"` // language slug
+	CheckerCode     *string `json:"checker_code,omitempty" gorm:"type:text"`
+
+	// Interactor that converses with submissions of interactive problems and decides
+	// their verdict, and how many lines a submission may send it (0 is no limit)
+	InteractorLanguage *string `json:"interactor_language,omitempty" gorm:"size:50"` // language slug
+	InteractorCode     *string `json:"interactor_code,omitempty" gorm:"type:text"`
+	QueryLimit         int     `json:"query_limit" gorm:"default:0"`
+
+	// Generator that produces test inputs from a seed and a size, see
+	// services/generator
+	GeneratorLanguage *string `json:"generator_language,omitempty" gorm:"size:50"` // language slug
+	GeneratorCode     *string `json:"generator_code,omitempty" gorm:"type:text"`
+
+	// Validator that accepts or rejects test inputs, on top of the constraints
+	// declared on the parameters, see services/inputvalidator
+	ValidatorLanguage *string `json:"validator_language,omitempty" gorm:"size:50"` // language slug
+	ValidatorCode     *string `json:"validator_code,omitempty" gorm:"type:text"`
+
+	// Subtasks score submissions by the groups of tests they pass, see Subtask;
+	// without any a submission scores all or nothing
+	Subtasks Subtasks `json:"subtasks,omitempty" gorm:"type:jsonb"`
+
 	// New relationships
 	TestCases          []TestCase                 `json:"test_cases,omitempty" gorm:"foreignKey:ProblemID"`
 	Boilerplates       []ProblemBoilerplate       `json:"boilerplates,omitempty" gorm:"foreignKey:ProblemID"`
@@ -49,3 +83,140 @@ type Problem struct {
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
+
+// Judge modes. A function problem has a signature and its submissions are wrapped
+// in a generated harness; a design problem is judged the same way, but submissions
+// implement a class whose methods each test calls in sequence. A stdio problem takes whole programs that read the test's
+// input from stdin and write the answer to stdout, and its test cases are raw text.
+// An interactive problem takes whole programs too, but they talk to the problem's
+// interactor instead, which alone sees the test case.
+const (
+	JudgeModeFunction    = "function"
+	JudgeModeDesign      = "design"
+	JudgeModeStdio       = "stdio"
+	JudgeModeInteractive = "interactive"
+)
+
+// IsValidJudgeMode reports whether mode is one of the judge modes
+func IsValidJudgeMode(mode string) bool {
+	switch mode {
+	case JudgeModeFunction, JudgeModeDesign, JudgeModeStdio, JudgeModeInteractive:
+		return true
+	}
+	return false
+}
+
+// ValidationTypeCustom leaves judging answers to the problem's checker program
+const ValidationTypeCustom = "CUSTOM"
+
+// Validation types of stdio problems: TOKENS compares whitespace-separated tokens,
+// LINES compares line by line ignoring trailing whitespace
+const (
+	ValidationTypeTokens = "TOKENS"
+	ValidationTypeLines  = "LINES"
+)
+
+// IsDesign reports whether submissions implement a class rather than a function
+func (p *Problem) IsDesign() bool {
+	return p.JudgeMode == JudgeModeDesign
+}
+
+// IsStdio reports whether submissions are whole programs judged on their output
+func (p *Problem) IsStdio() bool {
+	return p.JudgeMode == JudgeModeStdio
+}
+
+// IsInteractive reports whether submissions are whole programs judged by an
+// interactor
+func (p *Problem) IsInteractive() bool {
+	return p.JudgeMode == JudgeModeInteractive
+}
+
+// IsWholeProgram reports whether submissions are complete programs rather than a
+// function run in a harness, so the problem has no signature or boilerplates and
+// its test cases are raw text
+func (p *Problem) IsWholeProgram() bool {
+	return p.IsStdio() || p.IsInteractive()
+}
+
+// HasInteractor reports whether the problem's interactor is set
+func (p *Problem) HasInteractor() bool {
+	return p.InteractorLanguage != nil && *p.InteractorLanguage != "" &&
+		p.InteractorCode != nil && *p.InteractorCode != ""
+}
+
+// HasGenerator reports whether the problem's test generator is set
+func (p *Problem) HasGenerator() bool {
+	return p.GeneratorLanguage != nil && *p.GeneratorLanguage != "" &&
+		p.GeneratorCode != nil && *p.GeneratorCode != ""
+}
+
+// HasValidator reports whether the problem's input validator is set
+func (p *Problem) HasValidator() bool {
+	return p.ValidatorLanguage != nil && *p.ValidatorLanguage != "" &&
+		p.ValidatorCode != nil && *p.ValidatorCode != ""
+}
+
+// UsesChecker reports whether answers are judged by a checker program
+func (p *Problem) UsesChecker() bool {
+	return p.ValidationType == ValidationTypeCustom &&
+		p.CheckerLanguage != nil && *p.CheckerLanguage != "" &&
+		p.CheckerCode != nil && *p.CheckerCode != ""
+}
+
+// Schema is the signature submissions are generated against: the function, or the
+// class of a design problem
+func (p *Problem) Schema() (ProblemSchema, error) {
+	if p.IsDesign() {
+		if p.Design == nil {
+			return ProblemSchema{}, errors.New("problem design not defined")
+		}
+		var design DesignSchema
+		if err := json.Unmarshal(*p.Design, &design); err != nil {
+			return ProblemSchema{}, fmt.Errorf("failed to parse problem design: %w", err)
+		}
+		return ProblemSchema{Design: &design}, nil
+	}
+
+	if p.FunctionName == nil || p.ReturnType == nil || p.Parameters == nil {
+		return ProblemSchema{}, errors.New("problem signature not defined")
+	}
+	var params []SchemaParameter
+	if err := json.Unmarshal(*p.Parameters, &params); err != nil {
+		return ProblemSchema{}, fmt.Errorf("failed to parse problem parameters: %w", err)
+	}
+	return ProblemSchema{
+		FunctionName: *p.FunctionName,
+		ReturnType:   GenericType(*p.ReturnType),
+		Parameters:   params,
+	}, nil
+}
+
+// EncodeTestValue is how a test case's input or expected output from a request is
+// stored: as the text itself for whole-program problems (nil being no text), as
+// JSON otherwise
+func (p *Problem) EncodeTestValue(v interface{}) (string, error) {
+	if p.IsWholeProgram() {
+		if v == nil {
+			return "", nil
+		}
+		text, ok := v.(string)
+		if !ok {
+			return "", errors.New("test cases of stdio and interactive problems must be text")
+		}
+		return text, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Sanitize hides what only admins and the judge may see
+func (p *Problem) Sanitize() {
+	p.CheckerCode = nil
+	p.InteractorCode = nil
+	p.GeneratorCode = nil
+	p.ValidatorCode = nil
+}
@@ -12,6 +12,7 @@ type ProblemBoilerplate struct {
 	LanguageID          int            `json:"language_id" gorm:"not null;index"`
 	StubCode            string         `json:"stub_code" gorm:"type:text;not null"`              // User-facing starter code
 	TestHarnessTemplate datatypes.JSON `json:"test_harness_template" gorm:"type:jsonb;not null"` // Wrapper with {USER_CODE} placeholder
+	HarnessVersion      string         `json:"harness_version" gorm:"size:50"`                   // Template the harness was rendered from, e.g. "python.v1"
 	CreatedAt           time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt           time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// MaxRejudgeSubmissions caps how many submissions one rejudge may queue
+const MaxRejudgeSubmissions = 10000
+
+// ErrSubmissionsBeingJudged is returned when a rejudge picks submissions that are
+// queued or being judged already
+var ErrSubmissionsBeingJudged = errors.New("submissions are already being judged")
+
+// RejudgeFilter picks the submissions a rejudge judges again. Every field set
+// narrows the selection; submissions made in [From, To) match the date range.
+type RejudgeFilter struct {
+	SubmissionID *int       `json:"submission_id,omitempty"`
+	ProblemID    *int       `json:"problem_id,omitempty" gorm:"index"`
+	LanguageID   *int       `json:"language_id,omitempty"`
+	From         *time.Time `json:"from,omitempty" gorm:"column:created_from"`
+	To           *time.Time `json:"to,omitempty" gorm:"column:created_to"`
+}
+
+// IsEmpty reports whether the filter would match every submission
+func (f *RejudgeFilter) IsEmpty() bool {
+	return f.SubmissionID == nil && f.ProblemID == nil && f.LanguageID == nil && f.From == nil && f.To == nil
+}
+
+// Rejudge is an admin's request to judge a set of submissions again, typically
+// after fixing a problem's tests, with its progress
+type Rejudge struct {
+	ID            int `json:"id" gorm:"primaryKey"`
+	RejudgeFilter `gorm:"embedded"`
+	Total         int        `json:"total"`
+	Judged        int        `json:"judged" gorm:"default:0"`
+	Changed       int        `json:"changed" gorm:"default:0"` // submissions whose verdict or score changed
+	CreatedBy     int        `json:"created_by" gorm:"not null;index"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// IsFinished reports whether every submission of the rejudge has been judged again
+func (r *Rejudge) IsFinished() bool {
+	return r.FinishedAt != nil
+}
+
+// RejudgeResult is a submission's verdict before and after a rejudge. A
+// submission's results, oldest first, are its verdict history.
+type RejudgeResult struct {
+	ID                 int              `json:"id" gorm:"primaryKey"`
+	RejudgeID          int              `json:"rejudge_id" gorm:"not null;uniqueIndex:idx_rejudge_submission"`
+	SubmissionID       int              `json:"submission_id" gorm:"not null;uniqueIndex:idx_rejudge_submission;index"`
+	UserID             int              `json:"user_id" gorm:"not null"`
+	ProblemID          int              `json:"problem_id" gorm:"not null"`
+	OldStatus          SubmissionStatus `json:"old_status" gorm:"type:varchar(50)"`
+	OldScore           int              `json:"old_score"`
+	OldPassedTestCases int              `json:"old_passed_test_cases"`
+	NewStatus          SubmissionStatus `json:"new_status,omitempty" gorm:"type:varchar(50)"` // empty until judged
+	NewScore           int              `json:"new_score"`
+	NewPassedTestCases int              `json:"new_passed_test_cases"`
+	JudgedAt           *time.Time       `json:"judged_at,omitempty"`
+	CreatedAt          time.Time        `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// NewRejudgeResult records a submission's verdict before it is judged again
+func NewRejudgeResult(s *Submission) RejudgeResult {
+	return RejudgeResult{
+		SubmissionID:       s.ID,
+		UserID:             s.UserID,
+		ProblemID:          s.ProblemID,
+		OldStatus:          s.Status,
+		OldScore:           s.Score,
+		OldPassedTestCases: s.PassedTestCases,
+	}
+}
+
+// Changed reports whether judging again changed the submission's verdict or score
+func (r *RejudgeResult) Changed() bool {
+	return r.JudgedAt != nil && (r.NewStatus != r.OldStatus || r.NewScore != r.OldScore)
+}
+
+// CountsTowardsStats reports whether a submission with status counts in the
+// problem's acceptance and the user's attempts: it was judged, and wasn't a
+// compilation or internal error
+func CountsTowardsStats(status SubmissionStatus) bool {
+	return isJudged(status) && countsAsAttempt(status)
+}
+
+// RecountUserProblemStats rebuilds a user's stats on a problem from all their
+// submissions to it, as needed once a rejudge changed some of their verdicts
+func RecountUserProblemStats(userID, problemID int, submissions []Submission) *UserProblemStats {
+	stats := &UserProblemStats{
+		UserID:    userID,
+		ProblemID: problemID,
+		Status:    "unsolved",
+		UpdatedAt: time.Now(),
+	}
+
+	sorted := make([]*Submission, 0, len(submissions))
+	for i := range submissions {
+		s := &submissions[i]
+		if s.IsRunOnly || s.IsValidationSubmission || !CountsTowardsStats(s.Status) {
+			continue
+		}
+		sorted = append(sorted, s)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	for _, s := range sorted {
+		stats.Attempts++
+		accepted := s.Status == SubmissionStatusAccepted
+		if accepted && stats.FirstSolvedAt == nil {
+			solvedAt := s.CreatedAt
+			stats.FirstSolvedAt = &solvedAt
+		}
+		// Like the incremental stats, the best submission is the latest one scoring
+		// at least as much as any before it
+		if (accepted || s.Score > 0) && s.Score >= stats.BestScore {
+			id := s.ID
+			stats.BestSubmissionID = &id
+			stats.BestScore = s.Score
+		}
+	}
+
+	switch {
+	case stats.FirstSolvedAt != nil:
+		stats.Status = "solved"
+	case stats.Attempts > 0:
+		stats.Status = "attempted"
+	}
+	return stats
+}
+
+// RejudgeRepository defines the interface for rejudge persistence
+type RejudgeRepository interface {
+	// Create saves the rejudge with a result per submission, and sets those
+	// submissions pending in the rejudge
+	Create(rejudge *Rejudge, results []RejudgeResult) error
+	GetByID(id int) (*Rejudge, error)
+	List(limit, offset int) ([]Rejudge, int64, error)
+	ListResults(rejudgeID int, changedOnly bool) ([]RejudgeResult, error)
+	ListBySubmission(submissionID int) ([]RejudgeResult, error)
+	// RecordResult stores the new verdict of a submission judged again in the
+	// rejudge and counts it towards the rejudge's progress
+	RecordResult(rejudgeID int, submission *Submission) error
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecountUserProblemStats(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	submissions := []Submission{
+		{ID: 4, Status: SubmissionStatusAccepted, Score: 100, CreatedAt: at(30)},
+		{ID: 1, Status: SubmissionStatusWrongAnswer, CreatedAt: at(0)},
+		{ID: 2, Status: SubmissionStatusCompilationError, CreatedAt: at(10)},
+		{ID: 3, Status: SubmissionStatusAccepted, Score: 100, CreatedAt: at(20)},
+		{ID: 5, Status: SubmissionStatusAccepted, IsRunOnly: true, CreatedAt: at(40)},
+		{ID: 6, Status: SubmissionStatusPending, CreatedAt: at(50)},
+	}
+
+	stats := RecountUserProblemStats(7, 8, submissions)
+	if stats.UserID != 7 || stats.ProblemID != 8 {
+		t.Fatalf("Expected stats of user 7 on problem 8, got %+v", stats)
+	}
+	if stats.Status != "solved" || stats.Attempts != 3 {
+		t.Errorf("Expected solved in 3 attempts, got %s in %d", stats.Status, stats.Attempts)
+	}
+	if stats.FirstSolvedAt == nil || !stats.FirstSolvedAt.Equal(at(20)) {
+		t.Errorf("Expected first solved at %v, got %v", at(20), stats.FirstSolvedAt)
+	}
+	if stats.BestSubmissionID == nil || *stats.BestSubmissionID != 4 || stats.BestScore != 100 {
+		t.Errorf("Expected the latest full score as the best submission, got %v scoring %d", stats.BestSubmissionID, stats.BestScore)
+	}
+}
+
+func TestRecountUserProblemStatsNoLongerSolved(t *testing.T) {
+	submissions := []Submission{
+		{ID: 1, Status: SubmissionStatusWrongAnswer, Score: 30},
+		{ID: 2, Status: SubmissionStatusTimeLimitExceeded},
+	}
+
+	stats := RecountUserProblemStats(1, 1, submissions)
+	if stats.Status != "attempted" || stats.Attempts != 2 || stats.FirstSolvedAt != nil {
+		t.Errorf("Expected attempted twice and never solved, got %+v", stats)
+	}
+	if stats.BestSubmissionID == nil || *stats.BestSubmissionID != 1 || stats.BestScore != 30 {
+		t.Errorf("Expected the partial score as the best submission, got %v scoring %d", stats.BestSubmissionID, stats.BestScore)
+	}
+
+	if stats := RecountUserProblemStats(1, 1, nil); stats.Status != "unsolved" || stats.Attempts != 0 {
+		t.Errorf("Expected unsolved without submissions, got %+v", stats)
+	}
+}
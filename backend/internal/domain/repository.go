@@ -65,6 +65,8 @@ type ProblemRepository interface {
 	UpdateCurrentStep(id int, newCurrentStep int) error
 	UpdateStats(id int, acceptanceRate float64, totalSubmissions, totalAccepted int) error
 	IncrementStats(id int, isAccepted bool) error
+	// RecountStats recomputes the problem's acceptance from its submissions
+	RecountStats(id int) error
 	UpdateStatus(id int, status string) error
 	UpdateVisibility(id int, visibility string) error
 	CountByStatus(status string) (int, error)
@@ -139,6 +141,10 @@ type SubmissionRepository interface {
 	CountByUser(userID int) (int64, error)
 	CountByUserProblem(userID int, problemID int) (int64, error)
 
+	// Contests
+	ListByContest(contestID int) ([]Submission, error)
+	ListByContestUser(contestID int, userID int, limit, offset int) ([]Submission, error)
+
 	// Stats
 	CountTotal() (int64, error)
 	CountPending() (int64, error)
@@ -155,10 +161,28 @@ type SubmissionRepository interface {
 	GetSolvedDistribution(userID int) ([]DifficultyStat, error)
 	GetSubmissionHeatmap(userID int) ([]HeatmapEntry, error)
 	GetCurrentStreak(userID int) (int, error)
+	// GetPerformanceCounts compares a ranked submission's runtime and memory with those
+	// of the ranked submissions in its language to the same problem
+	GetPerformanceCounts(submission *Submission) (*PerformanceCounts, error)
 
 	// Queue monitoring
 	GetOldestPending(limit int) ([]Submission, error)
 	CountPendingBefore(createdAt time.Time) (int64, error)
+	GetStuckBefore(queuedAt time.Time, limit int) ([]Submission, error)
+	// SetStatusIfUnjudged sets the status of a submission still pending or processing,
+	// reporting false when it has been judged in the meantime
+	SetStatusIfUnjudged(id int, status SubmissionStatus, errorMessage string) (bool, error)
+	// ResetForReplay sets a dead-lettered submission back to pending, unless a worker
+	// gave it a verdict other than an internal error in the meantime
+	ResetForReplay(id int) (bool, error)
+
+	// Rejudges
+	ListForRejudge(filter RejudgeFilter, limit int) ([]Submission, error)
+	ListVerdictsByUserProblem(userID int, problemID int) ([]Submission, error)
+	// What achievements are awarded for, counted again once a rejudge changed verdicts
+	CountByUserStatus(userID int, status SubmissionStatus) (int64, error)
+	CountFirstAttemptSolves(userID int) (int64, error)
+	CountSolvesAfterAttempts(userID int, attempts int) (int64, error)
 }
 
 // UserProblemStatsRepository interface
@@ -168,6 +192,8 @@ type UserProblemStatsRepository interface {
 	Get(userID, problemID int) (*UserProblemStats, error)
 	GetStatuses(userID int, problemIDs []int) (map[int]string, error)
 	Upsert(stats *UserProblemStats) error
+	// Replace overwrites the stats, as recounted after a rejudge
+	Replace(stats *UserProblemStats) error
 }
 
 // ProblemLanguageRepository interface
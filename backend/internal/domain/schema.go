@@ -1,24 +1,85 @@
 package domain
 
+// GenericType is a schema type in the grammar described on TypeExpr
 type GenericType string
 
 const (
-	TypeInteger      GenericType = "integer"
-	TypeString       GenericType = "string"
-	TypeBoolean      GenericType = "boolean"
-	TypeIntegerArray GenericType = "integer_array"
-	TypeStringArray  GenericType = "string_array"
-	// Optional: Custom types could also be represented here or handled separately
+	TypeInteger       GenericType = "integer"
+	TypeString        GenericType = "string"
+	TypeBoolean       GenericType = "boolean"
+	TypeIntegerArray  GenericType = "integer_array"
+	TypeStringArray   GenericType = "string_array"
+	TypeDouble        GenericType = "double"
+	TypeDoubleArray   GenericType = "double_array"
+	TypeLong          GenericType = "long"
+	TypeChar          GenericType = "char"
+	TypeIntegerMatrix GenericType = "integer_matrix"
+	TypeStringMatrix  GenericType = "string_matrix"
+	// Anything else is composed, e.g. "list<list<int>>", "map<string,int>" or "int?"
+
+	// TypeVoid is the return type of a design method that answers nothing; it is no
+	// type of the grammar and valid nowhere else
+	TypeVoid GenericType = "void"
 )
 
+// IsPrimitive reports whether the type is built in rather than a custom type such as
+// TreeNode
+func (t GenericType) IsPrimitive() bool {
+	parsed, err := t.Parse()
+	return err == nil && parsed.Kind != KindCustom
+}
+
+// IsFloating reports whether values of the type are compared with a tolerance
+func (t GenericType) IsFloating() bool {
+	parsed, err := t.Parse()
+	return err == nil && parsed.Contains(KindDouble)
+}
+
 type SchemaParameter struct {
-	Name     string      `json:"name"`
-	Type     GenericType `json:"type"`
-	IsCustom bool        `json:"is_custom"`
+	Name        string            `json:"name"`
+	Type        GenericType       `json:"type"`
+	IsCustom    bool              `json:"is_custom"`
+	Constraints *ParamConstraints `json:"constraints,omitempty"` // Checked on every test case
 }
 
 type ProblemSchema struct {
 	FunctionName string            `json:"function_name"`
 	Parameters   []SchemaParameter `json:"parameters"`
 	ReturnType   GenericType       `json:"return_type"`
+
+	// Design is the class of a design problem, which has no function of its own
+	Design *DesignSchema `json:"design,omitempty"`
+}
+
+// DesignSchema is a class the user implements. A test case calls its constructor
+// and then its methods in sequence, LeetCode style: the input is the operation names
+// and the argument list of each, [["LRUCache","put","get"],[[2],[1,1],[1]]], and the
+// expected output what each call returns, null for the constructor and void methods:
+// [null,null,1].
+type DesignSchema struct {
+	ClassName   string            `json:"class_name"`
+	Constructor []SchemaParameter `json:"constructor"`
+	Methods     []MethodSchema    `json:"methods"`
+}
+
+// MethodSchema is a method of a design class
+type MethodSchema struct {
+	Name       string            `json:"name"`
+	Parameters []SchemaParameter `json:"parameters"`
+	ReturnType GenericType       `json:"return_type"` // TypeVoid, or empty, when it returns nothing
+}
+
+// IsVoid reports whether the method returns nothing
+func (m MethodSchema) IsVoid() bool {
+	return m.ReturnType == "" || m.ReturnType == TypeVoid
+}
+
+// Method finds a method of the class by name
+func (d *DesignSchema) Method(name string) (MethodSchema, bool) {
+	for _, m := range d.Methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return MethodSchema{}, false
 }
@@ -3,6 +3,7 @@ package domain
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/datatypes"
@@ -17,21 +18,55 @@ const (
 	SubmissionStatusWrongAnswer         SubmissionStatus = "Wrong Answer"
 	SubmissionStatusTimeLimitExceeded   SubmissionStatus = "Time Limit Exceeded"
 	SubmissionStatusMemoryLimitExceeded SubmissionStatus = "Memory Limit Exceeded"
+	SubmissionStatusOutputLimitExceeded SubmissionStatus = "Output Limit Exceeded"
 	SubmissionStatusRuntimeError        SubmissionStatus = "Runtime Error"
 	SubmissionStatusCompilationError    SubmissionStatus = "Compilation Error"
 	SubmissionStatusInternalError       SubmissionStatus = "Internal Error"
 )
 
+// Per-test statuses reported by the harnesses in TestCaseResult.Status
+const (
+	TestStatusPassed         = "passed"
+	TestStatusFailed         = "failed"
+	TestStatusTimeout        = "timeout"
+	TestStatusRuntimeError   = "runtime_error"
+	TestStatusMemoryExceeded = "memory_exceeded"
+	TestStatusOutputExceeded = "output_exceeded"
+)
+
 type TestCaseResult struct {
 	TestID         int    `json:"test_id"`
 	Input          string `json:"input"`
 	ExpectedOutput string `json:"expected_output"`
 	ActualOutput   string `json:"actual_output"`
-	Status         string `json:"status"` // "passed", "failed", "timeout", "runtime_error"
+	Status         string `json:"status"` // one of the TestStatus* constants
 	TimeMS         int    `json:"time_ms"`
 	MemoryKB       int    `json:"memory_kb"`
 	IsSample       bool   `json:"is_sample"`
 	Error          string `json:"error,omitempty"`
+	Stdout         string `json:"stdout,omitempty"` // what the user's code printed while running this test
+	Subtask        string `json:"subtask,omitempty"`
+}
+
+// Verdict is the submission status a test that didn't pass stands for, with the
+// message explaining it
+func (tr *TestCaseResult) Verdict(problem *Problem) (SubmissionStatus, string) {
+	switch tr.Status {
+	case TestStatusTimeout:
+		return SubmissionStatusTimeLimitExceeded, ""
+	case TestStatusMemoryExceeded:
+		return SubmissionStatusMemoryLimitExceeded, fmt.Sprintf("Memory limit exceeded on test %d", tr.TestID)
+	case TestStatusOutputExceeded:
+		return SubmissionStatusOutputLimitExceeded, fmt.Sprintf("Output limit exceeded on test %d", tr.TestID)
+	case TestStatusRuntimeError:
+		return SubmissionStatusRuntimeError, tr.Error
+	default:
+		// A checker or interactor explains why it rejected the answer
+		if (problem.UsesChecker() || problem.IsInteractive()) && tr.Error != "" {
+			return SubmissionStatusWrongAnswer, fmt.Sprintf("Failed on test %d: %s", tr.TestID, tr.Error)
+		}
+		return SubmissionStatusWrongAnswer, fmt.Sprintf("Failed on test %d", tr.TestID)
+	}
 }
 
 // Slice of TestCaseResult
@@ -59,11 +94,11 @@ func (t *TestCaseResults) Scan(value interface{}) error {
 type Submission struct {
 	ID                int              `json:"id" gorm:"primaryKey"`
 	UserID            int              `json:"user_id" gorm:"not null"`
-	ProblemID         int              `json:"problem_id" gorm:"not null"`
-	LanguageID        int              `json:"language_id" gorm:"not null"`
+	ProblemID         int              `json:"problem_id" gorm:"not null;index:idx_submission_ranking,priority:1"` // ranking counts filter on problem, language and status
+	LanguageID        int              `json:"language_id" gorm:"not null;index:idx_submission_ranking,priority:2"`
 	Code              string           `json:"code,omitempty" gorm:"type:text;not null"`
 	FunctionCode      string           `json:"function_code" gorm:"type:text;default:''"`
-	Status            SubmissionStatus `json:"status" gorm:"type:varchar(50);default:'Pending'"`
+	Status            SubmissionStatus `json:"status" gorm:"type:varchar(50);default:'Pending';index:idx_submission_ranking,priority:3"`
 	ErrorMessage      string           `json:"error_message,omitempty" gorm:"type:text"` // For compile/runtime errors
 	ExecutionMetadata datatypes.JSON   `json:"execution_metadata,omitempty" gorm:"type:jsonb"`
 	Runtime           int              `json:"runtime" gorm:"default:0"` // in milliseconds
@@ -75,6 +110,11 @@ type Submission struct {
 	// Detailed results
 	TestCaseResults TestCaseResults `json:"test_case_results" gorm:"type:jsonb"`
 
+	// Score out of the problem's MaxScore, with how each subtask went when the
+	// problem has subtasks
+	Score          int            `json:"score" gorm:"default:0"`
+	SubtaskResults SubtaskResults `json:"subtask_results,omitempty" gorm:"type:jsonb"`
+
 	// Queue metadata
 	QueuedAt    *time.Time `json:"queued_at,omitempty" gorm:"index"`    // When job was enqueued
 	ProcessedAt *time.Time `json:"processed_at,omitempty" gorm:"index"` // When job was processed
@@ -85,6 +125,17 @@ type Submission struct {
 	IsRunOnly              bool `json:"is_run_only" gorm:"default:false"`              // Distinguishes temporary "Run" executions
 	SubmittedBy            *int `json:"submitted_by,omitempty" gorm:"index"`           // Admin user ID if admin submission
 
+	// Contest the submission was made in, if any
+	ContestID *int `json:"contest_id,omitempty" gorm:"index"`
+
+	// Rejudge the submission is queued in, cleared once it has been judged again
+	RejudgeID *int `json:"rejudge_id,omitempty" gorm:"index"`
+
+	// Judgement the current verdict comes from. While a worker judges the submission,
+	// Judgement holds the attempt in progress until it is saved with the verdict.
+	JudgementID *int                 `json:"judgement_id,omitempty" gorm:"index"`
+	Judgement   *SubmissionJudgement `json:"judgement,omitempty" gorm:"foreignKey:JudgementID;references:ID"`
+
 	// Associations
 	User     *User     `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
 	Admin    *User     `json:"admin,omitempty" gorm:"foreignKey:SubmittedBy;references:ID;constraint:OnDelete:SET NULL"`
@@ -98,8 +149,12 @@ func (s *Submission) Sanitize() {
 			s.TestCaseResults[i].Input = ""
 			s.TestCaseResults[i].ExpectedOutput = ""
 			s.TestCaseResults[i].ActualOutput = ""
+			s.TestCaseResults[i].Stdout = ""
 		}
 	}
+	if s.Problem != nil {
+		s.Problem.Sanitize()
+	}
 }
 
 type SubmissionStats struct {
@@ -124,6 +179,7 @@ func (r *RunCodeResult) Sanitize() {
 			r.Results[i].Input = ""
 			r.Results[i].ExpectedOutput = ""
 			r.Results[i].ActualOutput = ""
+			r.Results[i].Stdout = ""
 		}
 	}
 }
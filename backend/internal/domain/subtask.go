@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Subtask is a group of a problem's test cases worth Points, scored only when every
+// test of the group passes and every subtask it depends on was passed too. Test
+// cases name their subtask in TestCase.Subtask; those that don't belong to one still
+// decide the verdict but are worth nothing.
+type Subtask struct {
+	Name      string   `json:"name"`
+	Points    int      `json:"points"`
+	DependsOn []string `json:"depends_on,omitempty"` // names of earlier subtasks
+}
+
+// Subtasks is a problem's subtasks in order, stored as JSON
+type Subtasks []Subtask
+
+func (s Subtasks) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *Subtasks) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// Validate checks the subtasks have unique names and no negative points, and that
+// each depends only on subtasks before it, so that there are no cycles
+func (s Subtasks) Validate() error {
+	seen := make(map[string]bool, len(s))
+	for i, st := range s {
+		name := strings.TrimSpace(st.Name)
+		switch {
+		case name == "" || len(name) > 50:
+			return fmt.Errorf("subtask %d: name must be 1-50 characters", i+1)
+		case name != st.Name:
+			return fmt.Errorf("subtask %d: name must not start or end with spaces", i+1)
+		case seen[name]:
+			return fmt.Errorf("subtask %d: name %s is already used", i+1, name)
+		case st.Points < 0:
+			return fmt.Errorf("subtask %s: points must not be negative", name)
+		}
+		for _, dep := range st.DependsOn {
+			if !seen[dep] {
+				return fmt.Errorf("subtask %s: depends on %q, which is not an earlier subtask", name, dep)
+			}
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// Has reports whether a subtask is called name
+func (s Subtasks) Has(name string) bool {
+	for i := range s {
+		if s[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Statuses of a subtask in SubtaskResult.Status
+const (
+	SubtaskStatusPassed  = "passed"
+	SubtaskStatusFailed  = "failed"  // a test of the subtask didn't pass
+	SubtaskStatusSkipped = "skipped" // its tests passed, but a subtask it depends on didn't
+)
+
+// SubtaskResult is how a submission did on a subtask
+type SubtaskResult struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Score  int    `json:"score"`
+	Passed int    `json:"passed"`
+	Total  int    `json:"total"`
+	Status string `json:"status"` // one of the SubtaskStatus* constants
+}
+
+// SubtaskResults is stored as JSON with the submission
+type SubtaskResults []SubtaskResult
+
+func (s SubtaskResults) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *SubtaskResults) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// DefaultMaxScore is what a problem without subtasks is worth
+const DefaultMaxScore = 100
+
+// HasSubtasks reports whether the problem is scored by subtasks rather than all or
+// nothing
+func (p *Problem) HasSubtasks() bool {
+	return len(p.Subtasks) > 0
+}
+
+// MaxScore is the score of a submission passing every test: the points of all the
+// subtasks, or DefaultMaxScore for a problem without any
+func (p *Problem) MaxScore() int {
+	if !p.HasSubtasks() {
+		return DefaultMaxScore
+	}
+	total := 0
+	for _, st := range p.Subtasks {
+		total += st.Points
+	}
+	return total
+}
+
+// Score scores a judged submission from its status and test results. Without
+// subtasks it is all or nothing; with them each subtask whose tests all passed, and
+// whose dependencies were passed, adds its points. A subtask without any test
+// results, like every subtask of a program that didn't compile, is failed.
+func (p *Problem) Score(status SubmissionStatus, results []TestCaseResult) (int, SubtaskResults) {
+	if !p.HasSubtasks() {
+		if status == SubmissionStatusAccepted {
+			return p.MaxScore(), nil
+		}
+		return 0, nil
+	}
+
+	index := make(map[string]int, len(p.Subtasks))
+	scored := make(SubtaskResults, len(p.Subtasks))
+	for i, st := range p.Subtasks {
+		index[st.Name] = i
+		scored[i] = SubtaskResult{Name: st.Name, Points: st.Points}
+	}
+	for i := range results {
+		j, ok := index[results[i].Subtask]
+		if !ok {
+			continue
+		}
+		scored[j].Total++
+		if results[i].Status == TestStatusPassed {
+			scored[j].Passed++
+		}
+	}
+
+	score := 0
+	for i, st := range p.Subtasks {
+		res := &scored[i]
+		if res.Total == 0 || res.Passed < res.Total {
+			res.Status = SubtaskStatusFailed
+			continue
+		}
+		res.Status = SubtaskStatusPassed
+		for _, dep := range st.DependsOn {
+			// Dependencies come earlier, so theirs are settled already
+			if j, ok := index[dep]; !ok || j >= i || scored[j].Status != SubtaskStatusPassed {
+				res.Status = SubtaskStatusSkipped
+				break
+			}
+		}
+		if res.Status == SubtaskStatusPassed {
+			res.Score = st.Points
+			score += st.Points
+		}
+	}
+	return score, scored
+}
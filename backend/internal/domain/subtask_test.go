@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func subtaskResults(subtask string, statuses ...string) []TestCaseResult {
+	rows := make([]TestCaseResult, len(statuses))
+	for i, status := range statuses {
+		rows[i] = TestCaseResult{Subtask: subtask, Status: status}
+	}
+	return rows
+}
+
+func TestProblemScore(t *testing.T) {
+	problem := &Problem{Subtasks: Subtasks{
+		{Name: "small", Points: 20},
+		{Name: "medium", Points: 30, DependsOn: []string{"small"}},
+		{Name: "large", Points: 50, DependsOn: []string{"medium"}},
+		{Name: "edge", Points: 10},
+	}}
+	if got := problem.MaxScore(); got != 110 {
+		t.Fatalf("Expected a max score of 110, got %d", got)
+	}
+
+	tests := []struct {
+		name     string
+		status   SubmissionStatus
+		results  [][]TestCaseResult
+		score    int
+		statuses []string
+	}{
+		{
+			name:   "all passed",
+			status: SubmissionStatusAccepted,
+			results: [][]TestCaseResult{
+				subtaskResults("small", TestStatusPassed, TestStatusPassed),
+				subtaskResults("medium", TestStatusPassed),
+				subtaskResults("large", TestStatusPassed),
+				subtaskResults("edge", TestStatusPassed),
+			},
+			score:    110,
+			statuses: []string{SubtaskStatusPassed, SubtaskStatusPassed, SubtaskStatusPassed, SubtaskStatusPassed},
+		},
+		{
+			name:   "dependency failed",
+			status: SubmissionStatusWrongAnswer,
+			results: [][]TestCaseResult{
+				subtaskResults("small", TestStatusPassed, TestStatusFailed),
+				subtaskResults("medium", TestStatusPassed),
+				subtaskResults("large", TestStatusPassed),
+				subtaskResults("edge", TestStatusPassed),
+			},
+			score:    10,
+			statuses: []string{SubtaskStatusFailed, SubtaskStatusSkipped, SubtaskStatusSkipped, SubtaskStatusPassed},
+		},
+		{
+			name:   "last subtask too slow",
+			status: SubmissionStatusTimeLimitExceeded,
+			results: [][]TestCaseResult{
+				subtaskResults("small", TestStatusPassed),
+				subtaskResults("medium", TestStatusPassed),
+				subtaskResults("large", TestStatusPassed, TestStatusTimeout),
+				subtaskResults("", TestStatusPassed),
+			},
+			score:    50,
+			statuses: []string{SubtaskStatusPassed, SubtaskStatusPassed, SubtaskStatusFailed, SubtaskStatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []TestCaseResult
+			for _, rows := range tt.results {
+				results = append(results, rows...)
+			}
+			score, subtasks := problem.Score(tt.status, results)
+			if score != tt.score {
+				t.Errorf("Expected a score of %d, got %d", tt.score, score)
+			}
+			for i, want := range tt.statuses {
+				if subtasks[i].Status != want {
+					t.Errorf("Subtask %s: expected %s, got %+v", subtasks[i].Name, want, subtasks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProblemScoreWithoutSubtasks(t *testing.T) {
+	problem := &Problem{}
+	if score, subtasks := problem.Score(SubmissionStatusAccepted, nil); score != DefaultMaxScore || subtasks != nil {
+		t.Errorf("Expected an accepted submission to score %d, got %d", DefaultMaxScore, score)
+	}
+	results := subtaskResults("", TestStatusPassed, TestStatusFailed)
+	if score, _ := problem.Score(SubmissionStatusWrongAnswer, results); score != 0 {
+		t.Errorf("Expected a rejected submission to score 0, got %d", score)
+	}
+}
+
+func TestSubtasksValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		subtasks Subtasks
+		err      string
+	}{
+		{"valid", Subtasks{{Name: "a", Points: 40}, {Name: "b", Points: 60, DependsOn: []string{"a"}}}, ""},
+		{"no name", Subtasks{{Points: 40}}, "name must be 1-50 characters"},
+		{"duplicate", Subtasks{{Name: "a"}, {Name: "a"}}, "name a is already used"},
+		{"negative", Subtasks{{Name: "a", Points: -1}}, "points must not be negative"},
+		{"later dependency", Subtasks{{Name: "a", DependsOn: []string{"b"}}, {Name: "b"}}, `depends on "b"`},
+		{"own dependency", Subtasks{{Name: "a", DependsOn: []string{"a"}}}, `depends on "a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.subtasks.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
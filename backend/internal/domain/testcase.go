@@ -18,15 +18,51 @@ type TestCase struct {
 	ValidationConfig ValidationConfig `json:"validation_config" gorm:"type:jsonb;serializer:json"`
 	OrderIndex       int              `json:"order_index" gorm:"default:0"`
 
+	// Subtask is the name of the problem's subtask the test belongs to, if any
+	Subtask string `json:"subtask,omitempty" gorm:"size:50;default:''"`
+
+	// Set when a value is too large for the database and lives in the test-data store
+	// instead, under the SHA-256 of its content; its column is then empty
+	InputKey          *string `json:"input_key,omitempty" gorm:"size:64"`
+	ExpectedOutputKey *string `json:"expected_output_key,omitempty" gorm:"size:64"`
+
 	// V2 additions
 	ExpectedOutputs *datatypes.JSON `json:"expected_outputs,omitempty" gorm:"type:jsonb"`
 	InputSize       *int            `json:"input_size,omitempty"`
 	TimeLimitMs     *int            `json:"time_limit_ms,omitempty"`
 	MemoryLimitMb   *int            `json:"memory_limit_mb,omitempty"`
 
+	// Provenance: where the test came from, see the TestSource constants. Generated
+	// tests keep the generator's seed and size, and the reference solution that
+	// computed their expected output.
+	Source              string `json:"source" gorm:"size:20;default:manual"`
+	GeneratorSeed       *int64 `json:"generator_seed,omitempty"`
+	GeneratorSize       *int   `json:"generator_size,omitempty"`
+	ReferenceLanguageID *int   `json:"reference_language_id,omitempty"`
+
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
 
+// StoredKeys returns the test-data store keys of the test's values kept out of the
+// database
+func (tc *TestCase) StoredKeys() []string {
+	var keys []string
+	if tc.InputKey != nil {
+		keys = append(keys, *tc.InputKey)
+	}
+	if tc.ExpectedOutputKey != nil {
+		keys = append(keys, *tc.ExpectedOutputKey)
+	}
+	return keys
+}
+
+// Sources of test cases
+const (
+	TestSourceManual    = "manual"    // written by an admin
+	TestSourceGenerated = "generated" // produced by the problem's generator
+	TestSourceStress    = "stress"    // an input a stress test found a divergence on
+)
+
 // ValidationConfig is a custom type for JSONB handling
 type ValidationConfig map[string]interface{}
 
@@ -0,0 +1,355 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TypeKind is the shape of a parsed schema type
+type TypeKind int
+
+const (
+	KindInt TypeKind = iota
+	KindLong
+	KindDouble
+	KindBool
+	KindChar
+	KindString
+	KindList
+	KindMap
+	KindCustom
+)
+
+// TypeExpr is a parsed schema type. Types compose with the grammar
+//
+//	type   = base [ "?" ]
+//	base   = "int" | "long" | "double" | "bool" | "char" | "string"
+//	       | "list<" type ">" | "map<" type "," type ">" | "optional<" type ">"
+//	       | alias | CustomTypeName
+//
+// where the aliases are the original flat names such as integer_array and
+// integer_matrix, and "?" or optional<...> makes a value nullable.
+type TypeExpr struct {
+	Kind     TypeKind
+	Elem     *TypeExpr // list elements and map values
+	Key      *TypeExpr // map keys
+	Nullable bool
+	Name     string // custom types
+}
+
+// typeAliases maps the flat type names, old and new, to their grammar form
+var typeAliases = map[string]string{
+	"integer":        "int",
+	"boolean":        "bool",
+	"integer_array":  "list<int>",
+	"string_array":   "list<string>",
+	"double_array":   "list<double>",
+	"long_array":     "list<long>",
+	"boolean_array":  "list<bool>",
+	"char_array":     "list<char>",
+	"integer_matrix": "list<list<int>>",
+	"string_matrix":  "list<list<string>>",
+	"char_matrix":    "list<list<char>>",
+	"double_matrix":  "list<list<double>>",
+}
+
+var scalarKinds = map[string]TypeKind{
+	"int":    KindInt,
+	"long":   KindLong,
+	"double": KindDouble,
+	"bool":   KindBool,
+	"char":   KindChar,
+	"string": KindString,
+}
+
+var scalarNames = [...]string{KindInt: "int", KindLong: "long", KindDouble: "double", KindBool: "bool", KindChar: "char", KindString: "string"}
+
+// parsedTypes caches ParseType, since every generator maps the same few types
+var parsedTypes sync.Map
+
+// ParseType parses a schema type. The result is shared and must not be modified.
+func ParseType(s string) (*TypeExpr, error) {
+	if cached, ok := parsedTypes.Load(s); ok {
+		return cached.(*TypeExpr), nil
+	}
+
+	p := &typeParser{src: s}
+	t, err := p.parseType()
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %w", s, err)
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, fmt.Errorf("invalid type %q: unexpected %q", s, p.src[p.pos:])
+	}
+	if t.Kind == KindCustom && t.Nullable {
+		return nil, fmt.Errorf("invalid type %q: custom types are already nullable", s)
+	}
+
+	parsedTypes.Store(s, t)
+	return t, nil
+}
+
+// Parse parses the type with ParseType
+func (t GenericType) Parse() (*TypeExpr, error) {
+	return ParseType(string(t))
+}
+
+type typeParser struct {
+	src string
+	pos int
+}
+
+func (p *typeParser) skipSpace() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *typeParser) ident() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := rune(p.src[p.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *typeParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != c {
+		return fmt.Errorf("expected %q at position %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *typeParser) parseType() (*TypeExpr, error) {
+	t, err := p.parseBase()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) && p.src[p.pos] == '?' {
+		p.pos++
+		if t.Nullable {
+			return nil, fmt.Errorf("type is already nullable")
+		}
+		t = t.withNullable()
+	}
+	return t, nil
+}
+
+func (p *typeParser) parseBase() (*TypeExpr, error) {
+	name := p.ident()
+	if name == "" {
+		return nil, fmt.Errorf("expected a type at position %d", p.pos)
+	}
+
+	if alias, ok := typeAliases[name]; ok {
+		return ParseType(alias)
+	}
+	if kind, ok := scalarKinds[name]; ok {
+		return &TypeExpr{Kind: kind}, nil
+	}
+
+	switch name {
+	case "list", "optional":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		if elem.Kind == KindCustom {
+			return nil, fmt.Errorf("custom type %s can only be used on its own", elem.Name)
+		}
+		if name == "optional" {
+			if elem.Nullable {
+				return nil, fmt.Errorf("type is already nullable")
+			}
+			return elem.withNullable(), nil
+		}
+		return &TypeExpr{Kind: KindList, Elem: elem}, nil
+
+	case "map":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		key, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		val, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		if key.Nullable || (key.Kind != KindInt && key.Kind != KindLong && key.Kind != KindChar && key.Kind != KindString) {
+			return nil, fmt.Errorf("map keys must be int, long, char or string, not %s", key)
+		}
+		if val.Kind == KindCustom {
+			return nil, fmt.Errorf("custom type %s can only be used on its own", val.Name)
+		}
+		return &TypeExpr{Kind: KindMap, Key: key, Elem: val}, nil
+	}
+
+	if first, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(first) {
+		return nil, fmt.Errorf("invalid type name %q", name)
+	}
+	return &TypeExpr{Kind: KindCustom, Name: name}, nil
+}
+
+func (t *TypeExpr) withNullable() *TypeExpr {
+	c := *t
+	c.Nullable = true
+	return &c
+}
+
+// NonNull is the type without its nullability
+func (t *TypeExpr) NonNull() *TypeExpr {
+	if !t.Nullable {
+		return t
+	}
+	c := *t
+	c.Nullable = false
+	return &c
+}
+
+// IsScalar reports whether the type is a single number, boolean, char or string
+func (t *TypeExpr) IsScalar() bool {
+	return t.Kind < KindList
+}
+
+// IsNumeric reports whether values of the type are JSON numbers
+func (t *TypeExpr) IsNumeric() bool {
+	return t.Kind == KindInt || t.Kind == KindLong || t.Kind == KindDouble
+}
+
+// Depth is how many lists are nested in the type, 0 for anything else
+func (t *TypeExpr) Depth() int {
+	if t.Kind != KindList {
+		return 0
+	}
+	return 1 + t.Elem.Depth()
+}
+
+// Contains reports whether the type or any type inside it has the given kind
+func (t *TypeExpr) Contains(kind TypeKind) bool {
+	if t.Kind == kind {
+		return true
+	}
+	return (t.Elem != nil && t.Elem.Contains(kind)) || (t.Key != nil && t.Key.Contains(kind))
+}
+
+// String is the canonical form of the type in the grammar
+func (t *TypeExpr) String() string {
+	var s string
+	switch t.Kind {
+	case KindList:
+		s = "list<" + t.Elem.String() + ">"
+	case KindMap:
+		s = "map<" + t.Key.String() + "," + t.Elem.String() + ">"
+	case KindCustom:
+		s = t.Name
+	default:
+		s = scalarNames[t.Kind]
+	}
+	if t.Nullable {
+		s += "?"
+	}
+	return s
+}
+
+// Check reports whether v, as decoded by encoding/json, is a value of the type.
+// Custom types are serialized in their own format and always pass.
+func (t *TypeExpr) Check(v interface{}) error {
+	if v == nil {
+		if t.Nullable || t.Kind == KindCustom {
+			return nil
+		}
+		return fmt.Errorf("null is not a %s", t)
+	}
+
+	switch t.Kind {
+	case KindInt, KindLong:
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%v is not an integer", v)
+		}
+		if t.Kind == KindInt && (n < math.MinInt32 || n > math.MaxInt32) {
+			return fmt.Errorf("%v does not fit in an int, use long", v)
+		}
+	case KindDouble:
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%v is not a number", v)
+		}
+	case KindBool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%v is not a boolean", v)
+		}
+	case KindChar:
+		if s, ok := v.(string); !ok || utf8.RuneCountInString(s) != 1 {
+			return fmt.Errorf("%v is not a single character", v)
+		}
+	case KindString:
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+	case KindList:
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%v is not a list", v)
+		}
+		for i, item := range items {
+			if err := t.Elem.Check(item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case KindMap:
+		entries, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%v is not an object", v)
+		}
+		for key, val := range entries {
+			if err := t.checkKey(key); err != nil {
+				return err
+			}
+			if err := t.Elem.Check(val); err != nil {
+				return fmt.Errorf("[%q]: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkKey checks a JSON object key, which is always a string, against the map's
+// key type
+func (t *TypeExpr) checkKey(key string) error {
+	switch t.Key.Kind {
+	case KindInt, KindLong:
+		if _, err := strconv.ParseInt(key, 10, 64); err != nil {
+			return fmt.Errorf("key %q is not an integer", key)
+		}
+	case KindChar:
+		if utf8.RuneCountInString(key) != 1 {
+			return fmt.Errorf("key %q is not a single character", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package domain
+
+import "testing"
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"integer", "int"},
+		{"integer_array", "list<int>"},
+		{"integer_matrix", "list<list<int>>"},
+		{"string_matrix", "list<list<string>>"},
+		{"list< list<long> >", "list<list<long>>"},
+		{"map<string, list<int?>>", "map<string,list<int?>>"},
+		{"optional<char>", "char?"},
+		{"list<double>?", "list<double>?"},
+		{"TreeNode", "TreeNode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseType(tt.input)
+			if err != nil {
+				t.Fatalf("ParseType failed: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseTypeErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"list<int",
+		"list<int>>",
+		"map<int>",
+		"map<double,int>",
+		"map<int?,int>",
+		"list<TreeNode>",
+		"TreeNode?",
+		"int??",
+		"optional<int?>",
+		"2d",
+	} {
+		if _, err := ParseType(input); err == nil {
+			t.Errorf("Expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestTypeExprCheck(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value interface{}
+		ok    bool
+	}{
+		{"int", 3.0, true},
+		{"int", 3.5, false},
+		{"int", 3e10, false},
+		{"long", 3e10, true},
+		{"char", "a", true},
+		{"char", "ab", false},
+		{"int?", nil, true},
+		{"int", nil, false},
+		{"integer_matrix", []interface{}{[]interface{}{1.0}, []interface{}{}}, true},
+		{"integer_matrix", []interface{}{1.0}, false},
+		{"map<int,bool>", map[string]interface{}{"12": true}, true},
+		{"map<int,bool>", map[string]interface{}{"x": true}, false},
+		{"TreeNode", []interface{}{1.0, nil, 2.0}, true},
+	}
+
+	for _, tt := range tests {
+		typ, err := ParseType(tt.typ)
+		if err != nil {
+			t.Fatalf("ParseType(%q) failed: %v", tt.typ, err)
+		}
+		if err := typ.Check(tt.value); (err == nil) != tt.ok {
+			t.Errorf("Check(%s, %v) = %v, expected ok=%v", tt.typ, tt.value, err, tt.ok)
+		}
+	}
+}
@@ -28,3 +28,8 @@ type User struct {
 	// V2 addition
 	IsBot bool `json:"is_bot" gorm:"default:false"`
 }
+
+// LevelForXP is the level a user with the given XP is at: one more every 100 XP
+func LevelForXP(xp int) int {
+	return 1 + xp/100
+}
@@ -10,6 +10,7 @@ type UserProblemStats struct {
 	Attempts         int        `json:"attempts" gorm:"default:0"`
 	FirstSolvedAt    *time.Time `json:"first_solved_at,omitempty"`
 	BestSubmissionID *int       `json:"best_submission_id,omitempty"`
+	BestScore        int        `json:"best_score" gorm:"default:0"`
 	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/domain/dto"
+)
+
+// ValidateContestRequest validates contest creation and update requests
+func ValidateContestRequest(req *dto.CreateContestRequest) map[string]string {
+	errors := make(map[string]string)
+
+	if strings.TrimSpace(req.Title) == "" {
+		errors["title"] = "title is required"
+	} else if len(req.Title) > 255 {
+		errors["title"] = "title must be max 255 characters"
+	}
+
+	if req.StartTime.IsZero() {
+		errors["start_time"] = "start_time is required"
+	}
+	if req.EndTime.IsZero() {
+		errors["end_time"] = "end_time is required"
+	} else if !req.EndTime.After(req.StartTime) {
+		errors["end_time"] = "end_time must be after start_time"
+	}
+
+	if req.Scoring != "" && req.Scoring != domain.ContestScoringICPC && req.Scoring != domain.ContestScoringIOI {
+		errors["scoring"] = "scoring must be icpc or ioi"
+	}
+	if req.Visibility != "" && req.Visibility != domain.ContestVisibilityPublic && req.Visibility != domain.ContestVisibilityPrivate {
+		errors["visibility"] = "visibility must be public or private"
+	}
+	if req.PenaltyMinutes != nil && *req.PenaltyMinutes < 0 {
+		errors["penalty_minutes"] = "penalty_minutes must not be negative"
+	}
+	if req.FreezeMinutes < 0 {
+		errors["freeze_minutes"] = "freeze_minutes must not be negative"
+	} else if req.EndTime.After(req.StartTime) && float64(req.FreezeMinutes) >= req.EndTime.Sub(req.StartTime).Minutes() {
+		errors["freeze_minutes"] = "freeze_minutes must be shorter than the contest"
+	}
+
+	if len(req.Problems) == 0 {
+		errors["problems"] = "at least one problem is required"
+	}
+	problems := make(map[int]bool)
+	labels := make(map[string]bool)
+	for i, p := range req.Problems {
+		label := strings.TrimSpace(p.Label)
+		switch {
+		case p.ProblemID <= 0:
+			errors["problems"] = fmt.Sprintf("problem %d: problem_id is required", i+1)
+		case problems[p.ProblemID]:
+			errors["problems"] = fmt.Sprintf("problem %d: problem %d is already in the contest", i+1, p.ProblemID)
+		case label == "" || len(label) > 10:
+			errors["problems"] = fmt.Sprintf("problem %d: label must be 1-10 characters", i+1)
+		case labels[label]:
+			errors["problems"] = fmt.Sprintf("problem %d: label %s is already used", i+1, label)
+		case p.Points < 0:
+			errors["problems"] = fmt.Sprintf("problem %d: points must not be negative", i+1)
+		}
+		problems[p.ProblemID] = true
+		labels[label] = true
+	}
+
+	return errors
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"strings"
 
+	"github.com/prabalesh/loco/backend/internal/domain"
 	"github.com/prabalesh/loco/backend/internal/domain/dto"
 )
 
@@ -36,6 +37,8 @@ func ValidateCreateLanguageRequest(req *dto.CreateLanguageRequest) map[string]st
 		errors["default_template"] = "default_template must be max 5000 characters"
 	}
 
+	validateExecutorConfig(req.ExecutorConfig, errors)
+
 	return errors
 }
 
@@ -93,5 +96,30 @@ func ValidateUpdateLanguageRequest(req *dto.UpdateLanguageRequest) map[string]st
 		errors["default_template"] = "default_template must be max 5000 characters"
 	}
 
+	validateExecutorConfig(req.ExecutorConfig, errors)
+
 	return errors
 }
+
+// validateExecutorConfig checks the limit multipliers and the usage baselines, the
+// only executor_config keys the judge interprets
+func validateExecutorConfig(cfg map[string]interface{}, errors map[string]string) {
+	for _, key := range []string{domain.ExecutorConfigTimeMultiplier, domain.ExecutorConfigMemoryMultiplier} {
+		v, ok := cfg[key]
+		if !ok {
+			continue
+		}
+		if m, isNumber := v.(float64); !isNumber || m <= 0 || m > 20 {
+			errors["executor_config."+key] = key + " must be a number between 0 and 20"
+		}
+	}
+	for _, key := range []string{domain.ExecutorConfigBaselineRuntimeMs, domain.ExecutorConfigBaselineMemoryKB} {
+		v, ok := cfg[key]
+		if !ok {
+			continue
+		}
+		if b, isNumber := v.(float64); !isNumber || b < 0 {
+			errors["executor_config."+key] = key + " must be a number of at least 0"
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/prabalesh/loco/backend/pkg/config"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// ErrNotFound means nothing is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps immutable blobs, each under the SHA-256 of its content, so storing the
+// same data twice keeps one copy
+type Store interface {
+	// Put stores data and returns its key
+	Put(ctx context.Context, data []byte) (string, error)
+	// Open streams the blob stored under key; the caller closes it
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Key is the key data is stored under: its SHA-256 in lowercase hex
+func Key(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidKey reports whether key could have come from Key, which keeps keys from
+// naming anything outside a store
+func ValidKey(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range key {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Get reads a whole blob
+func Get(ctx context.Context, s Store, key string) ([]byte, error) {
+	r, err := s.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// New builds the store selected by config
+func New(cfg *config.Config) (Store, error) {
+	c := cfg.TestData
+	switch c.Driver {
+	case "", DriverLocal:
+		return NewLocalStore(c.Dir)
+	case DriverS3:
+		if c.S3Endpoint == "" || c.S3Bucket == "" {
+			return nil, errors.New("the s3 test data driver needs an endpoint and a bucket")
+		}
+		return NewS3Store(c.S3Endpoint, c.S3Bucket, c.S3Region, c.S3AccessKey, c.S3SecretKey), nil
+	default:
+		return nil, fmt.Errorf("unknown test data driver %q", c.Driver)
+	}
+}
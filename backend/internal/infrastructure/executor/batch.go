@@ -0,0 +1,60 @@
+package executor
+
+import "context"
+
+// BatchRequest runs one program once per run, each in a process of its own so that
+// the time and memory of every run are measured apart from the others. Stdin,
+// StdinKeys and the limits of Request are ignored in favour of those of each run.
+type BatchRequest struct {
+	Request
+	Runs []Run
+}
+
+// Run is the input and the limits of one run of a batch
+type Run struct {
+	Stdin     string
+	StdinKeys []string // test-data store keys of values in Stdin, logged in its place
+
+	// Limits for the run; zero means the driver defaults
+	TimeLimitMs   int
+	MemoryLimitMb int
+}
+
+// request is the single run of the batch's program on run
+func (r *BatchRequest) request(run Run) *Request {
+	req := r.Request
+	req.Stdin = run.Stdin
+	req.StdinKeys = run.StdinKeys
+	req.TimeLimitMs = run.TimeLimitMs
+	req.MemoryLimitMb = run.MemoryLimitMb
+	return &req
+}
+
+// BatchExecutor is implemented by executors that do better with a batch than one
+// Execute per run, by compiling the program once for all the runs or logging them
+// as one execution
+type BatchExecutor interface {
+	ExecuteBatch(ctx context.Context, req *BatchRequest) ([]*Result, error)
+}
+
+// ExecuteEach runs every run of req and returns their results in order, through
+// ExecuteBatch when exec implements it and one Execute per run otherwise. A program
+// that doesn't compile comes back as a single result with CompileFailed set.
+func ExecuteEach(ctx context.Context, exec Executor, req *BatchRequest) ([]*Result, error) {
+	if batch, ok := exec.(BatchExecutor); ok {
+		return batch.ExecuteBatch(ctx, req)
+	}
+
+	results := make([]*Result, 0, len(req.Runs))
+	for _, run := range req.Runs {
+		res, err := exec.Execute(ctx, req.request(run))
+		if err != nil {
+			return nil, err
+		}
+		if res.CompileFailed {
+			return []*Result{res}, nil
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/pkg/config"
+	"go.uber.org/zap"
+)
+
+const (
+	DriverPiston = "piston"
+	DriverLocal  = "local"
+)
+
+// Request describes a single program run. Language is our language slug; drivers map
+// it to whatever their runtime calls it.
+type Request struct {
+	ProblemID    int
+	SubmissionID *int
+	Language     string
+	Version      string // empty means the driver's default version
+	Code         string
+	Stdin        string
+	StdinKeys    []string // test-data store keys of values in Stdin, logged in its place
+
+	// Limits for the run stage; zero means the driver defaults
+	TimeLimitMs   int
+	MemoryLimitMb int
+}
+
+// LoggedStdin is what logs keep of the stdin: the keys of the stored test data in it
+// rather than the data, or the stdin itself cut to limit bytes
+func (r *Request) LoggedStdin(limit int) string {
+	if len(r.StdinKeys) > 0 {
+		return fmt.Sprintf("[test data %s]", strings.Join(r.StdinKeys, ", "))
+	}
+	return TruncateOutput(r.Stdin, limit)
+}
+
+// Result is the outcome of a run. A failed compilation is reported as a result with
+// CompileFailed set, and a run stopped by a limit or a signal as one with
+// Termination set, not as errors; errors are reserved for the backend itself being
+// unavailable.
+type Result struct {
+	Output        string
+	Error         string
+	ExitCode      int
+	Signal        string
+	Termination   Termination
+	Runtime       int // milliseconds of CPU time
+	Memory        int // kilobytes
+	CompileFailed bool
+}
+
+// Executor runs untrusted code in some sandbox
+type Executor interface {
+	Execute(ctx context.Context, req *Request) (*Result, error)
+}
+
+// New builds the executor selected by config
+func New(cfg *config.Config, executionRepo domain.PistonExecutionRepository, logger *zap.Logger) (Executor, error) {
+	switch cfg.Executor.Driver {
+	case "", DriverPiston:
+		return NewPistonExecutor(cfg.Executor.PistonURL, cfg.Executor.MaxOutputBytes, executionRepo, logger), nil
+	case DriverLocal:
+		return NewLocalExecutor(cfg.Executor.SandboxDir, cfg.Executor.SandboxNamespaces, cfg.Executor.MaxOutputBytes, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown executor driver %q", cfg.Executor.Driver)
+	}
+}
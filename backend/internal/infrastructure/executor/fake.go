@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeExecutor is an in-memory executor for tests. It records every request and
+// answers with Handler, or with an empty successful result when Handler is nil.
+// Interactive runs are answered by InteractiveHandler and unsupported without one.
+type FakeExecutor struct {
+	Handler            func(req *Request) (*Result, error)
+	InteractiveHandler func(req *InteractiveRequest) (*InteractiveResult, error)
+
+	mu                  sync.Mutex
+	requests            []Request
+	interactiveRequests []InteractiveRequest
+}
+
+func NewFakeExecutor(handler func(req *Request) (*Result, error)) *FakeExecutor {
+	return &FakeExecutor{Handler: handler}
+}
+
+func (f *FakeExecutor) Execute(ctx context.Context, req *Request) (*Result, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, *req)
+	f.mu.Unlock()
+
+	if f.Handler == nil {
+		return &Result{}, nil
+	}
+	return f.Handler(req)
+}
+
+// Requests returns a copy of every request executed so far
+func (f *FakeExecutor) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
+
+func (f *FakeExecutor) ExecuteInteractive(ctx context.Context, req *InteractiveRequest) (*InteractiveResult, error) {
+	f.mu.Lock()
+	f.interactiveRequests = append(f.interactiveRequests, *req)
+	f.mu.Unlock()
+
+	if f.InteractiveHandler == nil {
+		return nil, ErrInteractiveUnsupported
+	}
+	return f.InteractiveHandler(req)
+}
+
+// InteractiveRequests returns a copy of every interactive request executed so far
+func (f *FakeExecutor) InteractiveRequests() []InteractiveRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]InteractiveRequest(nil), f.interactiveRequests...)
+}
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+// Files the interactor finds in its working directory
+const (
+	InteractorInputFile  = "input.txt"  // the test's input
+	InteractorAnswerFile = "answer.txt" // the test's expected output, if any
+)
+
+// ErrInteractiveUnsupported means the executor can't wire two programs together,
+// so interactive problems can't be judged with it
+var ErrInteractiveUnsupported = errors.New("executor does not support interactive runs")
+
+// InteractiveRequest runs a solution alongside an interactor: whatever either prints
+// on stdout is the other's stdin. Stdin of both requests is ignored.
+type InteractiveRequest struct {
+	Solution   Request
+	Interactor Request
+	Input      string // written to InteractorInputFile
+	Answer     string // written to InteractorAnswerFile
+
+	// QueryLimit caps the lines the solution may send; zero means no cap
+	QueryLimit int
+}
+
+// InteractiveResult is the outcome of an interactive run. Solution.Output is what
+// the solution sent the interactor and Interactor.Error what the interactor wrote to
+// stderr. Interactor is nil when the solution didn't compile.
+type InteractiveResult struct {
+	Solution           *Result
+	Interactor         *Result
+	Queries            int
+	QueryLimitExceeded bool
+}
+
+// InteractiveExecutor is implemented by executors that can run interactive problems
+type InteractiveExecutor interface {
+	ExecuteInteractive(ctx context.Context, req *InteractiveRequest) (*InteractiveResult, error)
+}
+
+// queryWriter passes what the solution prints on to the interactor, counting lines
+// as queries and keeping a copy in transcript. Going over the limit calls onExceed
+// once and stops forwarding. Writes after the interactor has gone are swallowed so
+// the solution isn't blocked on a pipe nobody reads.
+type queryWriter struct {
+	to         io.Writer
+	transcript *cappedBuffer
+	limit      int
+	queries    int
+	exceeded   bool
+	gone       bool
+	onExceed   func()
+}
+
+func newQueryWriter(to io.Writer, transcript *cappedBuffer, limit int, onExceed func()) *queryWriter {
+	return &queryWriter{to: to, transcript: transcript, limit: limit, onExceed: onExceed}
+}
+
+func (q *queryWriter) Write(p []byte) (int, error) {
+	q.transcript.Write(p)
+	if q.exceeded || q.gone {
+		return len(p), nil
+	}
+
+	q.queries += bytes.Count(p, []byte{'\n'})
+	if q.limit > 0 && q.queries > q.limit {
+		q.exceeded = true
+		if q.onExceed != nil {
+			q.onExceed()
+		}
+		return len(p), nil
+	}
+	if _, err := q.to.Write(p); err != nil {
+		q.gone = true
+	}
+	return len(p), nil
+}
@@ -0,0 +1,259 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// localExecutor compiles and runs code as child processes of the worker. Every run
+// gets a throwaway working directory, rlimits for CPU, address space and file size,
+// its own process group so a timeout kills everything it spawned and, on Linux
+// when enabled, fresh user/pid/net/ipc/uts namespaces. A run writing more than
+// maxOutputBytes to stdout or stderr is killed as soon as it crosses the cap.
+type localExecutor struct {
+	workDir        string
+	namespaces     bool
+	maxOutputBytes int
+	logger         *zap.Logger
+}
+
+// NewLocalExecutor returns an executor that runs code on this machine. workDir is
+// where per-run directories are created (the OS temp dir when empty) and
+// maxOutputBytes caps each output stream (unlimited when zero).
+func NewLocalExecutor(workDir string, namespaces bool, maxOutputBytes int, logger *zap.Logger) Executor {
+	return &localExecutor{
+		workDir:        workDir,
+		namespaces:     namespaces,
+		maxOutputBytes: maxOutputBytes,
+		logger:         logger,
+	}
+}
+
+func (e *localExecutor) cacheRoot() string {
+	if e.workDir != "" {
+		return e.workDir
+	}
+	return os.TempDir()
+}
+
+type processLimits struct {
+	timeout     time.Duration
+	memoryBytes int64    // 0 disables the address-space limit
+	env         []string // extra environment, e.g. heap caps for managed runtimes
+}
+
+type processResult struct {
+	stdout         string
+	stderr         string
+	exitCode       int
+	signal         string
+	timedOut       bool
+	outputExceeded bool
+	cpuMs          int
+	memoryKB       int
+}
+
+func (e *localExecutor) Execute(ctx context.Context, req *Request) (*Result, error) {
+	dir, spec, failed, err := e.prepare(ctx, req)
+	if dir != "" {
+		defer os.RemoveAll(dir)
+	}
+	if err != nil || failed != nil {
+		return failed, err
+	}
+
+	limits, memoryBytes := runProcessLimits(req, spec)
+	ran, err := e.run(ctx, dir, spec.Run, req.Stdin, limits)
+	if err != nil {
+		return nil, err
+	}
+	return runResult(ran, memoryBytes), nil
+}
+
+// ExecuteBatch compiles the program once and runs it in a fresh process for every
+// run, so each run's CPU time and peak memory come from its own rusage
+func (e *localExecutor) ExecuteBatch(ctx context.Context, req *BatchRequest) ([]*Result, error) {
+	dir, spec, failed, err := e.prepare(ctx, &req.Request)
+	if dir != "" {
+		defer os.RemoveAll(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if failed != nil {
+		return []*Result{failed}, nil
+	}
+
+	results := make([]*Result, len(req.Runs))
+	for i, run := range req.Runs {
+		limits, memoryBytes := runProcessLimits(req.request(run), spec)
+		ran, err := e.run(ctx, dir, spec.Run, run.Stdin, limits)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = runResult(ran, memoryBytes)
+	}
+	return results, nil
+}
+
+// prepare writes the source into a fresh directory and compiles it there. A failed
+// compilation comes back as a result. The caller removes dir once it is set, even
+// when there is an error.
+func (e *localExecutor) prepare(ctx context.Context, req *Request) (dir string, spec runtimeSpec, failed *Result, err error) {
+	spec, err = lookupRuntime(req.Language)
+	if err != nil {
+		return "", spec, nil, err
+	}
+
+	dir, err = os.MkdirTemp(e.workDir, "loco-run-")
+	if err != nil {
+		return "", spec, nil, fmt.Errorf("failed to create sandbox dir: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, spec.FileName), []byte(req.Code), 0o644); err != nil {
+		return dir, spec, nil, fmt.Errorf("failed to write source: %w", err)
+	}
+
+	if len(spec.Compile) > 0 {
+		compiled, err := e.run(ctx, dir, spec.Compile, "", processLimits{
+			timeout: DefaultCompileTimeoutMs * time.Millisecond,
+		})
+		if err != nil {
+			return dir, spec, nil, err
+		}
+		if compiled.exitCode != 0 || compiled.signal != "" {
+			return dir, spec, &Result{
+				Output:        compiled.stdout,
+				Error:         strings.TrimSpace(compiled.stdout + "\n" + compiled.stderr),
+				ExitCode:      compiled.exitCode,
+				Signal:        compiled.signal,
+				CompileFailed: true,
+			}, nil
+		}
+	}
+	return dir, spec, nil, nil
+}
+
+// runProcessLimits resolves the limits of the run stage; the memory limit is
+// returned on its own as managed runtimes get it as a heap cap instead
+func runProcessLimits(req *Request, spec runtimeSpec) (processLimits, int64) {
+	timeoutMs, memoryBytes := runLimits(req)
+	limits := processLimits{timeout: time.Duration(timeoutMs) * time.Millisecond}
+	if !spec.ManagedMemory {
+		limits.memoryBytes = memoryBytes
+	} else if spec.HeapLimitEnv != "" {
+		limits.env = append(limits.env, fmt.Sprintf(spec.HeapLimitEnv, memoryBytes/megabyte))
+	}
+	return limits, memoryBytes
+}
+
+// runResult turns a finished run stage into a Result
+func runResult(ran *processResult, memoryBytes int64) *Result {
+	termination := TerminationOutputLimit
+	if !ran.outputExceeded {
+		termination = classifyTermination(ran.signal, ran.stderr, ran.timedOut, ran.memoryKB, memoryBytes)
+	}
+	return &Result{
+		Output:      ran.stdout,
+		Error:       describeTermination(termination, ran.signal, ran.stderr),
+		ExitCode:    ran.exitCode,
+		Signal:      ran.signal,
+		Termination: termination,
+		Runtime:     ran.cpuMs,
+		Memory:      ran.memoryKB,
+	}
+}
+
+// run starts argv inside dir under a shell that applies the rlimits and then execs
+// the real program, so the limits apply to it and everything it forks
+func (e *localExecutor) run(ctx context.Context, dir string, argv []string, stdin string, limits processLimits) (*processResult, error) {
+	runCtx, cancel := context.WithTimeout(ctx, limits.timeout)
+	defer cancel()
+
+	cmd := e.command(runCtx, dir, argv, limits)
+	cmd.Stdin = strings.NewReader(stdin)
+
+	// Crossing the output cap kills the run the same way a timeout does
+	stdout := newCappedBuffer(e.maxOutputBytes, cancel)
+	stderr := newCappedBuffer(e.maxOutputBytes, cancel)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	return e.finish(ctx, runCtx, cmd, err, argv[0], stdout, stderr)
+}
+
+// command builds the sandboxed command for argv; the caller wires up its stdio.
+// Cancelling ctx kills it.
+func (e *localExecutor) command(ctx context.Context, dir string, argv []string, limits processLimits) *exec.Cmd {
+	cpuSeconds := int(limits.timeout/time.Second) + 1
+	ulimits := fmt.Sprintf("ulimit -t %d; ulimit -f %d;", cpuSeconds, 64*1024) // 64MB of output files
+	if limits.memoryBytes > 0 {
+		ulimits += fmt.Sprintf(" ulimit -v %d;", limits.memoryBytes/1024)
+	}
+
+	args := append([]string{"-c", ulimits + ` exec "$0" "$@"`}, argv...)
+	cmd := exec.CommandContext(ctx, "/bin/sh", args...)
+	cmd.Dir = dir
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + dir,
+		// Shared so Go builds don't recompile the standard library every run; the
+		// cache is content addressed, so concurrent runs can't poison each other
+		"GOCACHE=" + filepath.Join(e.cacheRoot(), "loco-gocache"),
+		"LANG=C.UTF-8",
+	}
+	cmd.Env = append(cmd.Env, limits.env...)
+	configureSandbox(cmd, e.namespaces)
+	return cmd
+}
+
+// finish collects what a command that has been waited for did. runCtx is the
+// context the command was built with and ctx the caller's; the caller giving up is
+// an error, the run's own deadline a timeout.
+func (e *localExecutor) finish(ctx, runCtx context.Context, cmd *exec.Cmd, err error, name string, stdout, stderr *cappedBuffer) (*processResult, error) {
+	result := &processResult{
+		stdout:         stdout.String(),
+		stderr:         stderr.String(),
+		outputExceeded: stdout.exceeded || stderr.exceeded,
+	}
+
+	if cmd.ProcessState == nil {
+		// The process never started: missing toolchain or namespaces not permitted
+		return nil, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+
+	state := cmd.ProcessState
+	result.exitCode = state.ExitCode()
+	result.cpuMs = int((state.UserTime() + state.SystemTime()).Milliseconds())
+	result.memoryKB = maxRSSKB(state)
+	result.signal = exitSignal(state)
+
+	if result.outputExceeded {
+		result.signal = "SIGKILL"
+	} else if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		result.timedOut = true
+		result.signal = "SIGKILL"
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	e.logger.Debug("Local sandbox run finished",
+		zap.String("cmd", name),
+		zap.Int("exit_code", result.exitCode),
+		zap.String("signal", result.signal),
+		zap.Int("cpu_ms", result.cpuMs),
+		zap.Int("memory_kb", result.memoryKB),
+		zap.Bool("output_exceeded", result.outputExceeded),
+	)
+
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ExecuteInteractive compiles both programs in their own directories and runs them
+// connected by pipes. The solution's output goes through a queryWriter so it can be
+// counted and cut off at the query limit.
+func (e *localExecutor) ExecuteInteractive(ctx context.Context, req *InteractiveRequest) (*InteractiveResult, error) {
+	solDir, solSpec, failed, err := e.prepare(ctx, &req.Solution)
+	if solDir != "" {
+		defer os.RemoveAll(solDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if failed != nil {
+		return &InteractiveResult{Solution: failed}, nil
+	}
+
+	intDir, intSpec, failed, err := e.prepare(ctx, &req.Interactor)
+	if intDir != "" {
+		defer os.RemoveAll(intDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if failed != nil {
+		return &InteractiveResult{Solution: &Result{}, Interactor: failed}, nil
+	}
+	for name, content := range map[string]string{InteractorInputFile: req.Input, InteractorAnswerFile: req.Answer} {
+		if err := os.WriteFile(filepath.Join(intDir, name), []byte(content), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	solLimits, solMemory := runProcessLimits(&req.Solution, solSpec)
+	intLimits, intMemory := runProcessLimits(&req.Interactor, intSpec)
+
+	solutionIn, interactorOut, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+	interactorIn, solutionOut, err := os.Pipe()
+	if err != nil {
+		solutionIn.Close()
+		interactorOut.Close()
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+
+	solCtx, solCancel := context.WithTimeout(ctx, solLimits.timeout)
+	defer solCancel()
+	intCtx, intCancel := context.WithTimeout(ctx, intLimits.timeout)
+	defer intCancel()
+
+	transcript := newCappedBuffer(e.maxOutputBytes, solCancel)
+	queries := newQueryWriter(solutionOut, transcript, req.QueryLimit, solCancel)
+	solStderr := newCappedBuffer(e.maxOutputBytes, solCancel)
+	sol := e.command(solCtx, solDir, solSpec.Run, solLimits)
+	sol.Stdin = solutionIn
+	sol.Stdout = queries
+	sol.Stderr = solStderr
+
+	intStdout := newCappedBuffer(0, nil) // unused, its stdout is the pipe
+	intStderr := newCappedBuffer(e.maxOutputBytes, intCancel)
+	inter := e.command(intCtx, intDir, intSpec.Run, intLimits)
+	inter.Stdin = interactorIn
+	inter.Stdout = interactorOut
+	inter.Stderr = intStderr
+
+	intErr := inter.Start()
+	var solErr error
+	if intErr == nil {
+		solErr = sol.Start()
+	}
+	// The children hold their own ends now
+	solutionIn.Close()
+	interactorOut.Close()
+	interactorIn.Close()
+	if intErr != nil || solErr != nil {
+		solutionOut.Close()
+		if intErr == nil {
+			intCancel()
+			inter.Wait()
+			return nil, fmt.Errorf("failed to start %s: %w", solSpec.Run[0], solErr)
+		}
+		return nil, fmt.Errorf("failed to start %s: %w", intSpec.Run[0], intErr)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		intErr = inter.Wait()
+	}()
+	solErr = sol.Wait()
+	// Nothing more is coming from the solution, so the interactor sees EOF
+	solutionOut.Close()
+	wg.Wait()
+
+	solRan, err := e.finish(ctx, solCtx, sol, solErr, solSpec.Run[0], transcript, solStderr)
+	if err != nil {
+		return nil, err
+	}
+	intRan, err := e.finish(ctx, intCtx, inter, intErr, intSpec.Run[0], intStdout, intStderr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InteractiveResult{
+		Solution:           runResult(solRan, solMemory),
+		Interactor:         runResult(intRan, intMemory),
+		Queries:            queries.queries,
+		QueryLimitExceeded: queries.exceeded,
+	}
+	if result.QueryLimitExceeded {
+		// Killed by us rather than by a limit of its own
+		result.Solution.Termination = TerminationNone
+		result.Solution.Error = solRan.stderr
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// guessInteractor hides the number from input.txt and answers each guess with
+// "<", ">" or "=", accepting once it is found
+const guessInteractor = `import sys
+secret = int(open("input.txt").read())
+while True:
+    line = sys.stdin.readline()
+    if not line:
+        print("no guess", file=sys.stderr)
+        sys.exit(1)
+    guess = int(line)
+    if guess == secret:
+        print("=", flush=True)
+        sys.exit(0)
+    print("<" if secret < guess else ">", flush=True)
+`
+
+const binarySearchSolution = `lo, hi = 1, 100
+while True:
+    mid = (lo + hi) // 2
+    print(mid, flush=True)
+    answer = input()
+    if answer == "=":
+        break
+    if answer == "<":
+        hi = mid - 1
+    else:
+        lo = mid + 1
+`
+
+const linearSearchSolution = `for guess in range(1, 101):
+    print(guess, flush=True)
+    if input() == "=":
+        break
+`
+
+func TestLocalExecutorInteractive(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not installed")
+	}
+
+	e := NewLocalExecutor(t.TempDir(), false, 0, zap.NewNop()).(InteractiveExecutor)
+	res, err := e.ExecuteInteractive(context.Background(), &InteractiveRequest{
+		Solution:   Request{Language: "python", Code: binarySearchSolution, TimeLimitMs: 5000},
+		Interactor: Request{Language: "python", Code: guessInteractor, TimeLimitMs: 10000},
+		Input:      "73\n",
+		QueryLimit: 7,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteInteractive failed: %v", err)
+	}
+
+	if res.QueryLimitExceeded || res.Interactor.ExitCode != 0 || res.Solution.ExitCode != 0 {
+		t.Fatalf("Expected both programs to finish cleanly, got %+v / %+v", res.Solution, res.Interactor)
+	}
+	if res.Queries == 0 || res.Queries > 7 || !strings.HasPrefix(res.Solution.Output, "50\n") {
+		t.Errorf("Unexpected transcript after %d queries: %q", res.Queries, res.Solution.Output)
+	}
+}
+
+func TestLocalExecutorInteractiveQueryLimit(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not installed")
+	}
+
+	e := NewLocalExecutor(t.TempDir(), false, 0, zap.NewNop()).(InteractiveExecutor)
+	res, err := e.ExecuteInteractive(context.Background(), &InteractiveRequest{
+		Solution:   Request{Language: "python", Code: linearSearchSolution, TimeLimitMs: 5000},
+		Interactor: Request{Language: "python", Code: guessInteractor, TimeLimitMs: 10000},
+		Input:      "73\n",
+		QueryLimit: 7,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteInteractive failed: %v", err)
+	}
+
+	if !res.QueryLimitExceeded || res.Solution.Termination != TerminationNone {
+		t.Errorf("Expected the query limit to stop the solution, got %+v", res)
+	}
+	// Cut off, the interactor never saw the answer
+	if res.Interactor.ExitCode != 1 || !strings.Contains(res.Interactor.Error, "no guess") {
+		t.Errorf("Expected the interactor to reject, got %+v", res.Interactor)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build linux
+
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+var signalNames = map[syscall.Signal]string{
+	syscall.SIGKILL: "SIGKILL",
+	syscall.SIGSEGV: "SIGSEGV",
+	syscall.SIGABRT: "SIGABRT",
+	syscall.SIGALRM: "SIGALRM",
+	syscall.SIGXCPU: "SIGXCPU",
+	syscall.SIGXFSZ: "SIGXFSZ",
+	syscall.SIGFPE:  "SIGFPE",
+	syscall.SIGBUS:  "SIGBUS",
+	syscall.SIGTERM: "SIGTERM",
+}
+
+// configureSandbox puts the child in its own process group, kills the whole group
+// on cancellation and optionally unshares namespaces so it can't see other
+// processes or reach the network
+func configureSandbox(cmd *exec.Cmd, namespaces bool) {
+	attr := &syscall.SysProcAttr{Setpgid: true}
+	if namespaces {
+		attr.Setpgid = false // the child leads its own pid namespace instead
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET |
+			syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+	}
+	cmd.SysProcAttr = attr
+
+	cmd.Cancel = func() error {
+		if namespaces {
+			// Killing the namespace's init takes every other process in it down too
+			return cmd.Process.Kill()
+		}
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+func exitSignal(state *os.ProcessState) string {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+	if name, ok := signalNames[status.Signal()]; ok {
+		return name
+	}
+	return status.Signal().String()
+}
+
+func maxRSSKB(state *os.ProcessState) int {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		return int(usage.Maxrss) // already in kilobytes on Linux
+	}
+	return 0
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package executor
+
+import (
+	"os"
+	"os/exec"
+)
+
+// configureSandbox is a no-op outside Linux: no process groups or namespaces, so
+// only the rlimits and the timeout apply
+func configureSandbox(cmd *exec.Cmd, namespaces bool) {}
+
+func exitSignal(state *os.ProcessState) string {
+	return ""
+}
+
+func maxRSSKB(state *os.ProcessState) int {
+	return 0
+}
@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/pkg/config"
+	"go.uber.org/zap"
+)
+
+func TestLocalExecutorRunsPython(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not installed")
+	}
+
+	e := NewLocalExecutor(t.TempDir(), false, 0, zap.NewNop())
+	res, err := e.Execute(context.Background(), &Request{
+		Language: "python",
+		Code:     "import sys\nprint(sys.stdin.read().upper())",
+		Stdin:    "hello",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if strings.TrimSpace(res.Output) != "HELLO" || res.ExitCode != 0 {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+}
+
+func TestLocalExecutorReportsCompileErrors(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ not installed")
+	}
+
+	e := NewLocalExecutor(t.TempDir(), false, 0, zap.NewNop())
+	res, err := e.Execute(context.Background(), &Request{
+		Language: "c++",
+		Code:     "int main() { return undefined_symbol; }",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !res.CompileFailed || !strings.Contains(res.Error, "undefined_symbol") {
+		t.Errorf("Expected a compile failure, got %+v", res)
+	}
+}
+
+func TestLocalExecutorReportsSignals(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not installed")
+	}
+
+	e := NewLocalExecutor(t.TempDir(), false, 0, zap.NewNop())
+	res, err := e.Execute(context.Background(), &Request{
+		Language: "python",
+		Code:     "import os, signal\nos.kill(os.getpid(), signal.SIGSEGV)",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if res.Signal != "SIGSEGV" {
+		t.Skipf("signals not reported on this platform: %+v", res)
+	}
+	if !strings.Contains(res.Error, "SIGSEGV") {
+		t.Errorf("Expected the signal in the error message, got %q", res.Error)
+	}
+}
+
+func TestLocalExecutorStopsOutputFloods(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not installed")
+	}
+
+	e := NewLocalExecutor(t.TempDir(), false, 4096, zap.NewNop())
+	res, err := e.Execute(context.Background(), &Request{
+		Language: "python",
+		Code:     "while True:\n    print('spam')",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if res.Termination != TerminationOutputLimit {
+		t.Fatalf("Expected an output limit termination, got %+v", res)
+	}
+	if len(res.Output) > 4096 {
+		t.Errorf("Expected output capped at 4096 bytes, got %d", len(res.Output))
+	}
+}
+
+func TestLocalExecutorMeasuresEachRun(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not installed")
+	}
+
+	e := NewLocalExecutor(t.TempDir(), false, 0, zap.NewNop())
+	results, err := ExecuteEach(context.Background(), e, &BatchRequest{
+		Request: Request{
+			Language: "python",
+			Code:     "import sys\nn = int(sys.stdin.read())\nassert n < 1000\nprint(sum(range(n)))",
+		},
+		Runs: []Run{{Stdin: "10"}, {Stdin: "5000"}, {Stdin: "100", TimeLimitMs: 5000}},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteEach failed: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected a result per run, got %d", len(results))
+	}
+	// The second run failing doesn't touch the others
+	if strings.TrimSpace(results[0].Output) != "45" || results[1].ExitCode == 0 || strings.TrimSpace(results[2].Output) != "4950" {
+		t.Errorf("Unexpected results: %+v, %+v, %+v", results[0], results[1], results[2])
+	}
+	for _, res := range results {
+		if res.Memory <= 0 {
+			t.Skipf("memory not measured on this platform: %+v", res)
+		}
+	}
+}
+
+func TestNewRejectsUnknownDriver(t *testing.T) {
+	cfg := &config.Config{Executor: config.ExecutorConfig{Driver: "carrier-pigeon"}}
+	if _, err := New(cfg, nil, zap.NewNop()); err == nil {
+		t.Error("Expected an error for an unknown driver")
+	}
+}
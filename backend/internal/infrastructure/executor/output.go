@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxStoredOutputBytes bounds how much of a run's stdout or stderr is kept in the
+// database for debugging; the full output only lives as long as the request
+const MaxStoredOutputBytes = 64 * 1024
+
+// MaxStoredTestOutputBytes bounds what a single test case keeps of the user's prints;
+// smaller than MaxStoredOutputBytes since a submission stores one per test case
+const MaxStoredTestOutputBytes = 8 * 1024
+
+// TruncateOutput cuts s to at most limit bytes on a rune boundary and notes how much
+// was dropped. A limit of zero or less leaves s alone.
+func TruncateOutput(s string, limit int) string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n... [truncated %d bytes]", s[:cut], len(s)-cut)
+}
+
+// cappedBuffer keeps the first limit bytes written to it and calls onExceed once when
+// a write goes past them. Later writes are swallowed rather than failed, so a
+// process that ignores SIGPIPE doesn't get to report its own error before it is
+// killed.
+type cappedBuffer struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+	onExceed func()
+}
+
+func newCappedBuffer(limit int, onExceed func()) *cappedBuffer {
+	return &cappedBuffer{limit: limit, onExceed: onExceed}
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+
+	if remaining := b.limit - b.buf.Len(); len(p) > remaining {
+		b.buf.Write(p[:remaining])
+		if !b.exceeded {
+			b.exceeded = true
+			if b.onExceed != nil {
+				b.onExceed()
+			}
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	if got := TruncateOutput("short", 10); got != "short" {
+		t.Errorf("Expected short output untouched, got %q", got)
+	}
+	if got := TruncateOutput("anything", 0); got != "anything" {
+		t.Errorf("Expected no truncation without a limit, got %q", got)
+	}
+
+	got := TruncateOutput(strings.Repeat("a", 20), 8)
+	if !strings.HasPrefix(got, "aaaaaaaa\n") || !strings.Contains(got, "truncated 12 bytes") {
+		t.Errorf("Unexpected truncation: %q", got)
+	}
+
+	// Never split a multi-byte rune
+	got = TruncateOutput("ab€cd", 3)
+	if !strings.HasPrefix(got, "ab\n") {
+		t.Errorf("Expected the cut before the rune, got %q", got)
+	}
+}
+
+func TestLoggedStdin(t *testing.T) {
+	req := &Request{Stdin: strings.Repeat("1 ", 10)}
+	if got := req.LoggedStdin(4); !strings.HasPrefix(got, "1 1 \n") {
+		t.Errorf("Expected the stdin cut, got %q", got)
+	}
+
+	req.StdinKeys = []string{"ab", "cd"}
+	if got := req.LoggedStdin(4); got != "[test data ab, cd]" {
+		t.Errorf("Expected the keys in place of stored test data, got %q", got)
+	}
+}
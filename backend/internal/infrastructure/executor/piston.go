@@ -0,0 +1,354 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+const (
+	DefaultPistonURL = "http://localhost:2000/api/v2"
+	MaxRetries       = 3
+	RetryDelay       = 1 * time.Second
+)
+
+type pistonRequest struct {
+	Language           string       `json:"language"`
+	Version            string       `json:"version"`
+	Files              []pistonFile `json:"files"`
+	Stdin              string       `json:"stdin"`
+	Args               []string     `json:"args"`
+	RunTimeout         int64        `json:"run_timeout,omitempty"`
+	CompileTimeout     int64        `json:"compile_timeout,omitempty"`
+	RunMemoryLimit     int64        `json:"run_memory_limit,omitempty"`
+	CompileMemoryLimit int64        `json:"compile_memory_limit,omitempty"`
+}
+
+type pistonFile struct {
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+}
+
+type pistonResponse struct {
+	Language string       `json:"language"`
+	Version  string       `json:"version"`
+	Run      pistonStage  `json:"run"`
+	Compile  *pistonStage `json:"compile,omitempty"`
+}
+
+type pistonStage struct {
+	Stdout   string  `json:"stdout"`
+	Stderr   string  `json:"stderr"`
+	Code     int     `json:"code"`
+	Signal   string  `json:"signal"`
+	Status   string  `json:"status"` // "TO" timeout, "OL"/"EL" output limits, "SG" signal, "RE" exit code
+	Output   string  `json:"output"`
+	WallTime float64 `json:"wall_time"`
+	CpuTime  float64 `json:"cpu_time"`
+	Memory   float64 `json:"memory"`
+}
+
+// PistonError represents a non-200 answer from the Piston API
+type PistonError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *PistonError) Error() string {
+	return fmt.Sprintf("piston error %d: %s", e.StatusCode, e.Message)
+}
+
+// errResponseTooLarge means Piston answered with more than the output cap allows for,
+// which only happens when the program flooded stdout or stderr
+var errResponseTooLarge = errors.New("piston response exceeds output limit")
+
+type pistonExecutor struct {
+	client         *http.Client
+	baseURL        string
+	maxOutputBytes int
+	logger         *zap.Logger
+	executionRepo  domain.PistonExecutionRepository
+}
+
+// NewPistonExecutor returns an executor backed by a Piston HTTP API. Every call is
+// logged to executionRepo when it is non-nil, with its output truncated. Runs
+// writing more than maxOutputBytes to stdout or stderr are reported as output limit
+// terminations (unlimited when zero).
+func NewPistonExecutor(baseURL string, maxOutputBytes int, executionRepo domain.PistonExecutionRepository, logger *zap.Logger) Executor {
+	if baseURL == "" {
+		baseURL = DefaultPistonURL
+	}
+
+	return &pistonExecutor{
+		// Requests are bounded per call in Execute, since the run timeout varies
+		client: &http.Client{
+			Transport: &http.Transport{
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 20,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		maxOutputBytes: maxOutputBytes,
+		logger:         logger,
+		executionRepo:  executionRepo,
+	}
+}
+
+func (e *pistonExecutor) Execute(ctx context.Context, req *Request) (*Result, error) {
+	res, resp, err := e.execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	e.logExecution(req, resp.Version, req.LoggedStdin(MaxStoredOutputBytes), truncateResponse(resp))
+	return res, nil
+}
+
+// ExecuteBatch runs every run of the batch in a request of its own, since Piston can't
+// keep a compiled program between requests, and logs the batch as a single execution.
+// Each result has the CPU time and peak memory of its own process.
+func (e *pistonExecutor) ExecuteBatch(ctx context.Context, req *BatchRequest) ([]*Result, error) {
+	results := make([]*Result, 0, len(req.Runs))
+	responses := make([]pistonResponse, 0, len(req.Runs))
+	stdins := make([]string, 0, len(req.Runs))
+	version := req.Version
+	defer func() {
+		if len(responses) > 0 {
+			e.logExecution(&req.Request, version, strings.Join(stdins, "\n"), responses)
+		}
+	}()
+
+	for _, run := range req.Runs {
+		runReq := req.request(run)
+		res, resp, err := e.execute(ctx, runReq)
+		if err != nil {
+			return nil, err
+		}
+		version = resp.Version
+		responses = append(responses, truncateResponse(resp))
+		stdins = append(stdins, runReq.LoggedStdin(MaxStoredOutputBytes))
+		if res.CompileFailed {
+			return []*Result{res}, nil
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// execute makes the call for a run and returns its result along with Piston's answer,
+// whose version is the one that ran
+func (e *pistonExecutor) execute(ctx context.Context, req *Request) (*Result, pistonResponse, error) {
+	language, version := req.Language, req.Version
+	fileName := ""
+	spec, err := lookupRuntime(req.Language)
+	if err == nil {
+		language = spec.PistonLanguage
+		fileName = spec.FileName
+		if version == "" {
+			version = spec.PistonVersion
+		}
+	}
+
+	// Piston caps the address space, which would kill a managed runtime on startup and
+	// can't be swapped for a heap cap as it takes no environment; the peak memory of
+	// those is held to the limit once the run is over instead
+	runTimeoutMs, memoryLimit := runLimits(req)
+	runMemoryLimit := memoryLimit
+	if spec.ManagedMemory {
+		runMemoryLimit = 0
+	}
+	body, err := json.Marshal(pistonRequest{
+		Language:           language,
+		Version:            version,
+		Files:              []pistonFile{{Name: fileName, Content: req.Code}},
+		Stdin:              req.Stdin,
+		RunMemoryLimit:     runMemoryLimit,
+		CompileMemoryLimit: DefaultCompileMemLimit,
+		RunTimeout:         runTimeoutMs,
+		CompileTimeout:     DefaultCompileTimeoutMs,
+	})
+	if err != nil {
+		return nil, pistonResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	// The deadline must be slightly higher than Piston's own compile + run timeouts
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(runTimeoutMs+DefaultCompileTimeoutMs)*time.Millisecond+10*time.Second)
+	defer cancel()
+
+	var respBody []byte
+	for attempt := 1; attempt <= MaxRetries; attempt++ {
+		respBody, err = e.post(ctx, body)
+		if err == nil || errors.Is(err, errResponseTooLarge) {
+			break
+		}
+
+		// Don't retry on client errors (4xx) or when the caller gave up
+		var pistonErr *PistonError
+		if errors.As(err, &pistonErr) && pistonErr.StatusCode < 500 || ctx.Err() != nil {
+			return nil, pistonResponse{}, err
+		}
+		if attempt < MaxRetries {
+			time.Sleep(RetryDelay * time.Duration(attempt))
+		}
+	}
+
+	var pistonResp pistonResponse
+	switch {
+	case errors.Is(err, errResponseTooLarge):
+		// Not worth decoding; the run is over the cap whatever it printed
+		pistonResp.Language, pistonResp.Version = language, version
+		pistonResp.Run = pistonStage{Status: "OL", Stderr: fmt.Sprintf("output exceeded %d bytes", e.maxOutputBytes)}
+	case err != nil:
+		return nil, pistonResponse{}, fmt.Errorf("failed after %d attempts: %w", MaxRetries, err)
+	default:
+		if err := json.Unmarshal(respBody, &pistonResp); err != nil {
+			return nil, pistonResponse{}, fmt.Errorf("decode error: %w", err)
+		}
+	}
+
+	if pistonResp.Version == "" {
+		pistonResp.Version = version
+	}
+
+	// Compilation check
+	if c := pistonResp.Compile; c != nil && (c.Code != 0 || (c.Signal != "" && c.Signal != "none")) {
+		return &Result{
+			Output:        c.Stdout,
+			Error:         c.Output,
+			ExitCode:      c.Code,
+			Signal:        c.Signal,
+			CompileFailed: true,
+		}, pistonResp, nil
+	}
+
+	// Runtime check (Capture signal if process was aborted)
+	run := pistonResp.Run
+	signal := run.Signal
+	if signal == "none" {
+		signal = ""
+	}
+
+	var termination Termination
+	switch {
+	case run.Status == "TO":
+		termination = TerminationTimeLimit
+	case run.Status == "OL" || run.Status == "EL" || e.overCap(run.Stdout) || e.overCap(run.Stderr):
+		termination = TerminationOutputLimit
+	case spec.ManagedMemory && int64(run.Memory) > memoryLimit:
+		termination = TerminationMemoryLimit
+	default:
+		termination = classifyTermination(signal, run.Stderr, false, int(run.Memory)/1024, memoryLimit)
+	}
+
+	return &Result{
+		Output:      TruncateOutput(run.Stdout, e.maxOutputBytes),
+		Error:       describeTermination(termination, signal, TruncateOutput(run.Stderr, e.maxOutputBytes)),
+		ExitCode:    run.Code,
+		Signal:      signal,
+		Termination: termination,
+		Runtime:     int(run.CpuTime),
+		Memory:      int(run.Memory) / 1024,
+	}, pistonResp, nil
+}
+
+// overCap reports whether a stream is longer than the configured output cap
+func (e *pistonExecutor) overCap(stream string) bool {
+	return e.maxOutputBytes > 0 && len(stream) > e.maxOutputBytes
+}
+
+// maxResponseBytes is how much of a response body is read before giving up on it:
+// room for stdout, stderr and their combined copy at the cap, with slack for JSON
+// escaping and the envelope
+func (e *pistonExecutor) maxResponseBytes() int64 {
+	if e.maxOutputBytes <= 0 {
+		return 0
+	}
+	return int64(e.maxOutputBytes)*8 + 64*1024
+}
+
+// truncateResponse cuts the output of a call down for storage, so a flood of prints
+// doesn't end up verbatim in the database
+func truncateResponse(resp pistonResponse) pistonResponse {
+	truncateStage := func(stage *pistonStage) {
+		stage.Stdout = TruncateOutput(stage.Stdout, MaxStoredOutputBytes)
+		stage.Stderr = TruncateOutput(stage.Stderr, MaxStoredOutputBytes)
+		stage.Output = TruncateOutput(stage.Output, MaxStoredOutputBytes)
+	}
+	truncateStage(&resp.Run)
+	if resp.Compile != nil {
+		compile := *resp.Compile
+		truncateStage(&compile)
+		resp.Compile = &compile
+	}
+	return resp
+}
+
+// logExecution stores a call, or the calls of a batch, with what Piston answered
+func (e *pistonExecutor) logExecution(req *Request, version, stdin string, response interface{}) {
+	if e.executionRepo == nil {
+		return
+	}
+
+	stored, err := json.Marshal(response)
+	if err != nil {
+		e.logger.Warn("Failed to encode piston execution", zap.Error(err))
+		return
+	}
+
+	execution := &domain.PistonExecution{
+		ProblemID:    req.ProblemID,
+		SubmissionID: req.SubmissionID,
+		Language:     req.Language,
+		Version:      version,
+		Code:         req.Code,
+		Stdin:        stdin,
+		Response:     datatypes.JSON(stored),
+	}
+	if err := e.executionRepo.Create(execution); err != nil {
+		e.logger.Warn("Failed to log piston execution", zap.Error(err))
+	}
+}
+
+func (e *pistonExecutor) post(ctx context.Context, body []byte) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/execute", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("piston post error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	limit := e.maxResponseBytes()
+	if limit > 0 {
+		reader = io.LimitReader(resp.Body, limit+1)
+	}
+
+	respBody, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+	if limit > 0 && int64(len(respBody)) > limit && resp.StatusCode == http.StatusOK {
+		return nil, errResponseTooLarge
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &PistonError{StatusCode: resp.StatusCode, Message: string(respBody)}
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"go.uber.org/zap"
+)
+
+// fakeExecutionRepo records the executions logged
+type fakeExecutionRepo struct {
+	domain.PistonExecutionRepository
+	created []domain.PistonExecution
+}
+
+func (r *fakeExecutionRepo) Create(execution *domain.PistonExecution) error {
+	r.created = append(r.created, *execution)
+	return nil
+}
+
+func TestPistonExecutorSendsRunLimits(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(pistonResponse{Run: pistonStage{Stdout: "ok"}})
+	}))
+	defer server.Close()
+
+	e := NewPistonExecutor(server.URL, 0, nil, zap.NewNop())
+	res, err := e.Execute(context.Background(), &Request{
+		Language:      "python",
+		Code:          "print('ok')",
+		TimeLimitMs:   1500,
+		MemoryLimitMb: 64,
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if res.Output != "ok" {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+
+	if got, ok := sent["run_memory_limit"].(float64); !ok || int64(got) != 64*megabyte {
+		t.Errorf("Expected run_memory_limit %d, got %v", 64*megabyte, sent["run_memory_limit"])
+	}
+	if got, ok := sent["run_timeout"].(float64); !ok || int64(got) != 1500 {
+		t.Errorf("Expected run_timeout 1500, got %v", sent["run_timeout"])
+	}
+	if _, ok := sent["memory_limit"]; ok {
+		t.Errorf("Expected no memory_limit key, Piston ignores it")
+	}
+}
+
+func TestPistonExecutorHoldsManagedRuntimesToTheLimitAfterTheRun(t *testing.T) {
+	var sent map[string]interface{}
+	peak := 200 * megabyte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = nil
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(pistonResponse{Run: pistonStage{Stdout: "ok", Memory: float64(peak)}})
+	}))
+	defer server.Close()
+
+	e := NewPistonExecutor(server.URL, 0, nil, zap.NewNop())
+	req := &Request{Language: "java", Code: "class Solution {}", MemoryLimitMb: 256}
+
+	// The JVM reserves more address space than the limit, so none is capped
+	res, err := e.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if _, ok := sent["run_memory_limit"]; ok {
+		t.Errorf("Expected no run_memory_limit for a managed runtime, got %v", sent["run_memory_limit"])
+	}
+	if res.Termination != TerminationNone {
+		t.Errorf("Expected a run under the limit to pass, got %s", res.Termination)
+	}
+
+	peak = 300 * megabyte
+	res, err = e.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if res.Termination != TerminationMemoryLimit {
+		t.Errorf("Expected a peak over the limit to be a memory limit termination, got %s", res.Termination)
+	}
+}
+
+func TestPistonExecutorMeasuresEachRunOfABatch(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var sent pistonRequest
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		// Piston measures the process that ran each stdin
+		cpu := map[string]float64{"1": 40, "2": 90}[sent.Stdin]
+		_ = json.NewEncoder(w).Encode(pistonResponse{Version: "3.10.0", Run: pistonStage{Stdout: sent.Stdin, CpuTime: cpu, Memory: cpu * 1024 * 1024}})
+	}))
+	defer server.Close()
+
+	repo := &fakeExecutionRepo{}
+	e := NewPistonExecutor(server.URL, 0, repo, zap.NewNop()).(BatchExecutor)
+	results, err := e.ExecuteBatch(context.Background(), &BatchRequest{
+		Request: Request{Language: "python", Code: "print(input())"},
+		Runs:    []Run{{Stdin: "1"}, {Stdin: "2"}},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+
+	if calls != 2 || len(results) != 2 {
+		t.Fatalf("Expected a call and a result per run, got %d calls and %d results", calls, len(results))
+	}
+	if results[0].Runtime != 40 || results[0].Memory != 40*1024 || results[1].Runtime != 90 || results[1].Memory != 90*1024 {
+		t.Errorf("Expected each run's own usage, got %+v and %+v", results[0], results[1])
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("Expected the batch to be logged once, got %d", len(repo.created))
+	}
+	if logged := repo.created[0]; logged.Stdin != "1\n2" || logged.Version != "3.10.0" || !strings.HasPrefix(string(logged.Response), "[") {
+		t.Errorf("Unexpected log: %+v", logged)
+	}
+}
@@ -0,0 +1,127 @@
+package executor
+
+import "fmt"
+
+// Default limits applied to every run
+const (
+	megabyte                = 1024 * 1024
+	DefaultRunMemoryLimit   = 512 * megabyte  // 512MB
+	DefaultCompileMemLimit  = 1024 * megabyte // 1GB for complex C++ templates
+	DefaultRunTimeoutMs     = 15000           // 15s (100 test cases safe)
+	DefaultCompileTimeoutMs = 20000           // 20s
+)
+
+// runtimeSpec describes how a language is run by each driver
+type runtimeSpec struct {
+	PistonLanguage string
+	PistonVersion  string // used when the request doesn't pin a version
+	FileName       string
+	Compile        []string // nil for interpreted languages
+	Run            []string
+	// ManagedMemory marks runtimes (JVM, V8) that reserve far more address space than
+	// they use, so an address-space rlimit would kill them on startup
+	ManagedMemory bool
+	// HeapLimitEnv caps the heap of a managed runtime instead; %d is megabytes
+	HeapLimitEnv string
+}
+
+var runtimes = map[string]runtimeSpec{
+	"python": {
+		PistonLanguage: "python",
+		PistonVersion:  "3.10.0",
+		FileName:       "solution.py",
+		Run:            []string{"python3", "solution.py"},
+	},
+	"javascript": {
+		PistonLanguage: "javascript",
+		PistonVersion:  "18.15.0",
+		FileName:       "solution.js",
+		Run:            []string{"node", "solution.js"},
+		ManagedMemory:  true,
+		HeapLimitEnv:   "NODE_OPTIONS=--max-old-space-size=%d",
+	},
+	"java": {
+		PistonLanguage: "java",
+		PistonVersion:  "15.0.2",
+		FileName:       "Solution.java",
+		Compile:        []string{"javac", "Solution.java"},
+		Run:            []string{"java", "-cp", ".", "Solution"},
+		ManagedMemory:  true,
+		HeapLimitEnv:   "JAVA_TOOL_OPTIONS=-Xmx%dm",
+	},
+	"c++": {
+		PistonLanguage: "cpp",
+		PistonVersion:  "10.2.0",
+		FileName:       "solution.cpp",
+		Compile:        []string{"g++", "-O2", "-std=c++17", "-o", "solution", "solution.cpp"},
+		Run:            []string{"./solution"},
+	},
+	"c": {
+		PistonLanguage: "c",
+		PistonVersion:  "10.2.0",
+		FileName:       "solution.c",
+		Compile:        []string{"gcc", "-O2", "-o", "solution", "solution.c", "-lm"},
+		Run:            []string{"./solution"},
+	},
+	"go": {
+		PistonLanguage: "go",
+		PistonVersion:  "1.16.2",
+		FileName:       "solution.go",
+		Compile:        []string{"go", "build", "-o", "solution", "solution.go"},
+		Run:            []string{"./solution"},
+		ManagedMemory:  true,
+	},
+	"rust": {
+		PistonLanguage: "rust",
+		PistonVersion:  "1.68.2",
+		FileName:       "solution.rs",
+		Compile:        []string{"rustc", "-O", "-o", "solution", "solution.rs"},
+		Run:            []string{"./solution"},
+	},
+	"typescript": {
+		PistonLanguage: "typescript",
+		PistonVersion:  "5.0.3",
+		FileName:       "solution.ts",
+		Compile:        []string{"tsc", "--target", "es2019", "--module", "commonjs", "solution.ts"},
+		Run:            []string{"node", "solution.js"},
+		ManagedMemory:  true,
+		HeapLimitEnv:   "NODE_OPTIONS=--max-old-space-size=%d",
+	},
+	"kotlin": {
+		PistonLanguage: "kotlin",
+		PistonVersion:  "1.8.20",
+		FileName:       "solution.kt",
+		Compile:        []string{"kotlinc", "solution.kt", "-include-runtime", "-d", "solution.jar"},
+		Run:            []string{"java", "-jar", "solution.jar"},
+		ManagedMemory:  true,
+		HeapLimitEnv:   "JAVA_TOOL_OPTIONS=-Xmx%dm",
+	},
+	"csharp": {
+		PistonLanguage: "csharp",
+		PistonVersion:  "6.12.0",
+		FileName:       "Solution.cs",
+		Compile:        []string{"mcs", "-out:solution.exe", "Solution.cs"},
+		Run:            []string{"mono", "solution.exe"},
+		ManagedMemory:  true,
+	},
+}
+
+// runLimits resolves the run-stage limits of a request against the defaults
+func runLimits(req *Request) (timeoutMs int64, memoryBytes int64) {
+	timeoutMs, memoryBytes = DefaultRunTimeoutMs, DefaultRunMemoryLimit
+	if req.TimeLimitMs > 0 {
+		timeoutMs = int64(req.TimeLimitMs)
+	}
+	if req.MemoryLimitMb > 0 {
+		memoryBytes = int64(req.MemoryLimitMb) * megabyte
+	}
+	return timeoutMs, memoryBytes
+}
+
+func lookupRuntime(language string) (runtimeSpec, error) {
+	spec, ok := runtimes[language]
+	if !ok {
+		return runtimeSpec{}, fmt.Errorf("unsupported language: %s", language)
+	}
+	return spec, nil
+}
@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// Termination says why a run was stopped before it could finish on its own
+type Termination string
+
+const (
+	TerminationNone        Termination = ""
+	TerminationTimeLimit   Termination = "time_limit"
+	TerminationMemoryLimit Termination = "memory_limit"
+	TerminationSignal      Termination = "signal"
+	TerminationOutputLimit Termination = "output_limit"
+)
+
+// SubmissionStatus maps a termination to the verdict it stands for
+func (t Termination) SubmissionStatus() domain.SubmissionStatus {
+	switch t {
+	case TerminationTimeLimit:
+		return domain.SubmissionStatusTimeLimitExceeded
+	case TerminationMemoryLimit:
+		return domain.SubmissionStatusMemoryLimitExceeded
+	case TerminationOutputLimit:
+		return domain.SubmissionStatusOutputLimitExceeded
+	default:
+		return domain.SubmissionStatusRuntimeError
+	}
+}
+
+// TestStatus maps a termination to the per-test status of the tests it cut short
+func (t Termination) TestStatus() string {
+	switch t {
+	case TerminationTimeLimit:
+		return domain.TestStatusTimeout
+	case TerminationMemoryLimit:
+		return domain.TestStatusMemoryExceeded
+	case TerminationOutputLimit:
+		return domain.TestStatusOutputExceeded
+	default:
+		return domain.TestStatusRuntimeError
+	}
+}
+
+// outOfMemoryMarkers are what each runtime prints when an allocation fails under the
+// address-space or heap limit
+var outOfMemoryMarkers = []string{
+	"MemoryError",                   // Python
+	"std::bad_alloc",                // C++
+	"java.lang.OutOfMemoryError",    // Java
+	"JavaScript heap out of memory", // Node
+	"runtime: out of memory",        // Go
+	"Cannot allocate memory",        // C (and anything checking errno)
+}
+
+// classifyTermination works out why a process stopped from what the driver saw
+func classifyTermination(signal, stderr string, timedOut bool, memoryKB int, memoryLimitBytes int64) Termination {
+	signal = strings.ToUpper(signal)
+
+	if timedOut || signal == "SIGXCPU" {
+		return TerminationTimeLimit
+	}
+	if signal == "SIGXFSZ" {
+		return TerminationOutputLimit
+	}
+	for _, marker := range outOfMemoryMarkers {
+		if strings.Contains(stderr, marker) {
+			return TerminationMemoryLimit
+		}
+	}
+	// Peak RSS at the limit means the process died reaching for more
+	if signal != "" && memoryLimitBytes > 0 && int64(memoryKB)*1024 >= memoryLimitBytes*95/100 {
+		return TerminationMemoryLimit
+	}
+	if signal != "" {
+		return TerminationSignal
+	}
+	return TerminationNone
+}
+
+// describeTermination prefixes stderr with a human readable account of why the
+// process was stopped
+func describeTermination(termination Termination, signal, stderr string) string {
+	var reason string
+	switch termination {
+	case TerminationTimeLimit:
+		reason = "time limit exceeded"
+	case TerminationMemoryLimit:
+		reason = "memory limit exceeded"
+	case TerminationOutputLimit:
+		reason = "output limit exceeded"
+	case TerminationSignal:
+		reason = "killed by signal"
+	default:
+		return stderr
+	}
+
+	if signal != "" {
+		return fmt.Sprintf("Process Terminated (Signal: %s, Reason: %s)\n%s", strings.ToUpper(signal), reason, stderr)
+	}
+	return fmt.Sprintf("Process Terminated (Reason: %s)\n%s", reason, stderr)
+}
@@ -0,0 +1,34 @@
+package executor
+
+import "testing"
+
+func TestClassifyTermination(t *testing.T) {
+	const limit = 256 * megabyte
+
+	tests := []struct {
+		name     string
+		signal   string
+		stderr   string
+		timedOut bool
+		memoryKB int
+		want     Termination
+	}{
+		{name: "clean exit", want: TerminationNone},
+		{name: "non-zero exit is not a termination", stderr: "Traceback: ZeroDivisionError", want: TerminationNone},
+		{name: "wall clock timeout", signal: "SIGKILL", timedOut: true, want: TerminationTimeLimit},
+		{name: "cpu rlimit", signal: "SIGXCPU", want: TerminationTimeLimit},
+		{name: "file size rlimit", signal: "SIGXFSZ", want: TerminationOutputLimit},
+		{name: "c++ bad_alloc", signal: "SIGABRT", stderr: "terminate called after throwing an instance of 'std::bad_alloc'", want: TerminationMemoryLimit},
+		{name: "python MemoryError", stderr: "MemoryError", want: TerminationMemoryLimit},
+		{name: "killed at the memory limit", signal: "SIGKILL", memoryKB: 256 * 1024, want: TerminationMemoryLimit},
+		{name: "plain segfault", signal: "SIGSEGV", memoryKB: 1024, want: TerminationSignal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyTermination(tt.signal, tt.stderr, tt.timedOut, tt.memoryKB, limit); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package queue
+
+// Lane is a named priority lane of the submission queue. Every lane is its own Redis
+// list; workers pick which lane to serve next by weight so low-priority bulk traffic
+// can never starve interactive users.
+type Lane string
+
+const (
+	LaneRun        Lane = "run"        // interactive "Run code" against sample tests
+	LaneSubmit     Lane = "submit"     // real user submissions
+	LaneValidation Lane = "validation" // admin reference-solution validation
+	LaneBulk       Lane = "bulk"       // bulk-import validations and rejudges
+)
+
+// Lanes lists every lane from highest to lowest priority
+var Lanes = []Lane{LaneRun, LaneSubmit, LaneValidation, LaneBulk}
+
+// DefaultLaneWeights is the share of dequeues each lane gets while all lanes are busy
+var DefaultLaneWeights = map[Lane]int{
+	LaneRun:        8,
+	LaneSubmit:     4,
+	LaneValidation: 2,
+	LaneBulk:       1,
+}
+
+// LaneKey returns the Redis list backing a lane. The submit lane keeps the original
+// queue name so jobs enqueued before lanes existed are still picked up.
+func LaneKey(lane Lane) string {
+	if lane == LaneSubmit || lane == "" {
+		return SubmissionQueueName
+	}
+	return SubmissionQueueName + ":" + string(lane)
+}
+
+// IsValid reports whether lane is one of the known lanes
+func (l Lane) IsValid() bool {
+	for _, lane := range Lanes {
+		if l == lane {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,9 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/prabalesh/loco/backend/pkg/redis"
@@ -12,26 +14,56 @@ import (
 )
 
 const (
-	SubmissionQueueName  = "submission:queue"
-	AchievementQueueName = "achievement:queue"
-	QueueTimeout         = 30 // Block for 30 seconds
+	SubmissionQueueName      = "submission:queue"
+	SubmissionProcessingName = "submission:processing"
+	SubmissionLeaseName      = "submission:leases"
+	SubmissionRetryName      = "submission:retry"
+	SubmissionDeadName       = "submission:dead"
+	SubmissionWakeupName     = "submission:wakeup"
+	AchievementQueueName     = "achievement:queue"
+	QueueTimeout             = 30 // Block for 30 seconds
+
+	// MaxSubmissionAttempts is how many times a submission job is handed to a
+	// worker before it is moved to the dead-letter list
+	MaxSubmissionAttempts = 3
 )
 
+// RetryBaseBackoff is doubled for every failed attempt
+var RetryBaseBackoff = 5 * time.Second
+
+var ErrJobNotFound = errors.New("job not found")
+
 type JobQueue interface {
-	EnqueueSubmission(ctx context.Context, submissionID int) error
-	DequeueSubmission(ctx context.Context) (*SubmissionJob, error)
+	EnqueueSubmission(ctx context.Context, submissionID int, lane Lane) error
+	DequeueSubmission(ctx context.Context, lanes []Lane) (*SubmissionJob, error)
+	AckSubmission(ctx context.Context, job *SubmissionJob) error
+	NackSubmission(ctx context.Context, job *SubmissionJob, cause error) (deadLettered bool, err error)
+	RequeueStaleSubmissions(ctx context.Context, timeout time.Duration) (int, error)
+	ListDeadSubmissions(ctx context.Context, offset, limit int) ([]SubmissionJob, int64, error)
+	RemoveDeadSubmission(ctx context.Context, submissionID int) (*SubmissionJob, error)
 	EnqueueAchievement(ctx context.Context, submissionID int) error
+	EnqueueAchievementRecheck(ctx context.Context, submissionID int) error
 	DequeueAchievement(ctx context.Context) (*AchievementJob, error)
 }
 
 type SubmissionJob struct {
-	SubmissionID int       `json:"submission_id"`
-	EnqueuedAt   time.Time `json:"enqueued_at"`
+	SubmissionID int        `json:"submission_id"`
+	Lane         Lane       `json:"lane"`
+	EnqueuedAt   time.Time  `json:"enqueued_at"`
+	Attempts     int        `json:"attempts"`
+	LastError    string     `json:"last_error,omitempty"`
+	FailedAt     *time.Time `json:"failed_at,omitempty"`
+
+	// raw is the exact payload held in the processing list, needed to remove it on ack
+	raw string
 }
 
 type AchievementJob struct {
 	SubmissionID int       `json:"submission_id"`
 	EnqueuedAt   time.Time `json:"enqueued_at"`
+	// Recheck also revokes the achievements the user no longer qualifies for, after a
+	// rejudge changed the submission's verdict
+	Recheck bool `json:"recheck,omitempty"`
 }
 
 type jobQueue struct {
@@ -46,10 +78,15 @@ func NewJobQueue(redisClient *redis.RedisClient, logger *zap.Logger) JobQueue {
 	}
 }
 
-// EnqueueSubmission pushes a submission job to the Redis queue
-func (q *jobQueue) EnqueueSubmission(ctx context.Context, submissionID int) error {
+// EnqueueSubmission pushes a submission job onto the given priority lane
+func (q *jobQueue) EnqueueSubmission(ctx context.Context, submissionID int, lane Lane) error {
+	if !lane.IsValid() {
+		lane = LaneSubmit
+	}
+
 	job := SubmissionJob{
 		SubmissionID: submissionID,
+		Lane:         lane,
 		EnqueuedAt:   time.Now(),
 	}
 
@@ -59,55 +96,299 @@ func (q *jobQueue) EnqueueSubmission(ctx context.Context, submissionID int) erro
 		return fmt.Errorf("failed to marshal job: %w", err)
 	}
 
-	if err := q.redis.Client.LPush(ctx, SubmissionQueueName, jobData).Err(); err != nil {
+	if err := q.pushToLane(ctx, lane, string(jobData)); err != nil {
 		q.logger.Error("Failed to enqueue submission", zap.Error(err), zap.Int("submission_id", submissionID))
 		return fmt.Errorf("failed to enqueue submission: %w", err)
 	}
 
 	q.logger.Info("Submission enqueued successfully",
 		zap.Int("submission_id", submissionID),
-		zap.String("queue", SubmissionQueueName),
+		zap.String("queue", LaneKey(lane)),
 	)
 
 	return nil
 }
 
-// DequeueSubmission pulls a submission job from the Redis queue using BRPOP (blocking)
-func (q *jobQueue) DequeueSubmission(ctx context.Context) (*SubmissionJob, error) {
-	// BRPOP blocks until an item is available or timeout
-	result, err := q.redis.Client.BRPop(ctx, time.Duration(QueueTimeout)*time.Second, SubmissionQueueName).Result()
-	if err != nil {
+// DequeueSubmission atomically moves a submission job from the first non-empty lane,
+// in the order given, to the processing list. The job stays there until it is acked
+// or nacked, so a worker crash never loses it. When every lane is empty it blocks
+// until a job is enqueued or the timeout passes, and then returns nil so the caller
+// can pick a fresh lane order.
+func (q *jobQueue) DequeueSubmission(ctx context.Context, lanes []Lane) (*SubmissionJob, error) {
+	if err := q.promoteDueRetries(ctx); err != nil {
+		q.logger.Warn("Failed to promote retried submissions", zap.Error(err))
+	}
+
+	var raw string
+	for _, lane := range lanes {
+		item, err := q.redis.Client.LMove(ctx, LaneKey(lane), SubmissionProcessingName, "RIGHT", "LEFT").Result()
 		if err == goredis.Nil {
-			// Timeout or no items - this is expected behavior
-			return nil, nil
+			continue
+		}
+		if err != nil {
+			q.logger.Error("Failed to dequeue submission", zap.Error(err), zap.String("lane", string(lane)))
+			return nil, fmt.Errorf("failed to dequeue submission: %w", err)
 		}
-		q.logger.Error("Failed to dequeue submission", zap.Error(err))
-		return nil, fmt.Errorf("failed to dequeue submission: %w", err)
+		raw = item
+		break
 	}
 
-	if len(result) < 2 {
-		return nil, fmt.Errorf("invalid queue result format")
+	if raw == "" {
+		// Every lane is empty; wait for an enqueue to wake us up
+		if err := q.redis.Client.BRPop(ctx, time.Duration(QueueTimeout)*time.Second, SubmissionWakeupName).Err(); err != nil && err != goredis.Nil {
+			if ctx.Err() != nil {
+				return nil, nil
+			}
+			q.logger.Error("Failed to wait for submissions", zap.Error(err))
+			return nil, fmt.Errorf("failed to dequeue submission: %w", err)
+		}
+		return nil, nil
+	}
+
+	if err := q.redis.Client.HSet(ctx, SubmissionLeaseName, raw, time.Now().Unix()).Err(); err != nil {
+		q.logger.Warn("Failed to record submission lease", zap.Error(err))
 	}
 
 	var job SubmissionJob
-	if err := json.Unmarshal([]byte(result[1]), &job); err != nil {
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
 		q.logger.Error("Failed to unmarshal submission job", zap.Error(err))
+		// Nothing can ever process this payload, park it with the dead letters
+		pipe := q.redis.Client.TxPipeline()
+		pipe.LRem(ctx, SubmissionProcessingName, 1, raw)
+		pipe.HDel(ctx, SubmissionLeaseName, raw)
+		pipe.LPush(ctx, SubmissionDeadName, raw)
+		_, _ = pipe.Exec(ctx)
 		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
 	}
+	job.raw = raw
 
 	q.logger.Debug("Submission dequeued successfully",
 		zap.Int("submission_id", job.SubmissionID),
+		zap.Int("attempts", job.Attempts),
 	)
 
 	return &job, nil
 }
 
+// AckSubmission removes a finished job from the processing list
+func (q *jobQueue) AckSubmission(ctx context.Context, job *SubmissionJob) error {
+	pipe := q.redis.Client.TxPipeline()
+	pipe.LRem(ctx, SubmissionProcessingName, 1, job.raw)
+	pipe.HDel(ctx, SubmissionLeaseName, job.raw)
+	if _, err := pipe.Exec(ctx); err != nil {
+		q.logger.Error("Failed to ack submission", zap.Error(err), zap.Int("submission_id", job.SubmissionID))
+		return fmt.Errorf("failed to ack submission: %w", err)
+	}
+	return nil
+}
+
+// NackSubmission removes a failed job from the processing list and schedules it for
+// another attempt with exponential backoff. Once MaxSubmissionAttempts is reached the
+// job is moved to the dead-letter list instead and deadLettered is true.
+func (q *jobQueue) NackSubmission(ctx context.Context, job *SubmissionJob, cause error) (bool, error) {
+	now := time.Now()
+	retry := *job
+	retry.Attempts++
+	retry.FailedAt = &now
+	if cause != nil {
+		retry.LastError = cause.Error()
+	}
+
+	jobData, err := json.Marshal(retry)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	deadLettered := retry.Attempts >= MaxSubmissionAttempts
+
+	pipe := q.redis.Client.TxPipeline()
+	pipe.LRem(ctx, SubmissionProcessingName, 1, job.raw)
+	pipe.HDel(ctx, SubmissionLeaseName, job.raw)
+	if deadLettered {
+		pipe.LPush(ctx, SubmissionDeadName, jobData)
+	} else {
+		readyAt := now.Add(retryBackoff(retry.Attempts))
+		pipe.ZAdd(ctx, SubmissionRetryName, goredis.Z{Score: float64(readyAt.Unix()), Member: jobData})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		q.logger.Error("Failed to nack submission", zap.Error(err), zap.Int("submission_id", job.SubmissionID))
+		return false, fmt.Errorf("failed to nack submission: %w", err)
+	}
+
+	if deadLettered {
+		q.logger.Warn("Submission moved to dead-letter list",
+			zap.Int("submission_id", job.SubmissionID),
+			zap.Int("attempts", retry.Attempts),
+			zap.String("last_error", retry.LastError),
+		)
+	} else {
+		q.logger.Info("Submission scheduled for retry",
+			zap.Int("submission_id", job.SubmissionID),
+			zap.Int("attempts", retry.Attempts),
+		)
+	}
+
+	return deadLettered, nil
+}
+
+// RequeueStaleSubmissions nacks jobs that have sat in the processing list for longer
+// than timeout, which means the worker holding them died. It returns how many jobs
+// were recovered.
+func (q *jobQueue) RequeueStaleSubmissions(ctx context.Context, timeout time.Duration) (int, error) {
+	processing, err := q.redis.Client.LRange(ctx, SubmissionProcessingName, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read processing list: %w", err)
+	}
+	if len(processing) == 0 {
+		return 0, nil
+	}
+
+	leases, err := q.redis.Client.HGetAll(ctx, SubmissionLeaseName).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read leases: %w", err)
+	}
+
+	now := time.Now()
+	recovered := 0
+	for _, raw := range processing {
+		lease, ok := leases[raw]
+		if !ok {
+			// The worker crashed between the move and the lease write; start the clock now
+			q.redis.Client.HSetNX(ctx, SubmissionLeaseName, raw, now.Unix())
+			continue
+		}
+		dequeuedAt, _ := strconv.ParseInt(lease, 10, 64)
+		if now.Sub(time.Unix(dequeuedAt, 0)) < timeout {
+			continue
+		}
+
+		var job SubmissionJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			continue
+		}
+		job.raw = raw
+
+		if _, err := q.NackSubmission(ctx, &job, errors.New("processing lease expired")); err != nil {
+			return recovered, err
+		}
+		recovered++
+	}
+
+	return recovered, nil
+}
+
+// ListDeadSubmissions returns a page of dead-lettered submission jobs, newest first
+func (q *jobQueue) ListDeadSubmissions(ctx context.Context, offset, limit int) ([]SubmissionJob, int64, error) {
+	total, err := q.redis.Client.LLen(ctx, SubmissionDeadName).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead submissions: %w", err)
+	}
+
+	items, err := q.redis.Client.LRange(ctx, SubmissionDeadName, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list dead submissions: %w", err)
+	}
+
+	jobs := make([]SubmissionJob, 0, len(items))
+	for _, raw := range items {
+		var job SubmissionJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, total, nil
+}
+
+// RemoveDeadSubmission takes a submission's job off the dead-letter list and returns
+// it. Replaying it is up to the caller: EnqueueSubmission on the job's lane gives it a
+// fresh attempt budget.
+func (q *jobQueue) RemoveDeadSubmission(ctx context.Context, submissionID int) (*SubmissionJob, error) {
+	items, err := q.redis.Client.LRange(ctx, SubmissionDeadName, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead submissions: %w", err)
+	}
+
+	for _, raw := range items {
+		var job SubmissionJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil || job.SubmissionID != submissionID {
+			continue
+		}
+
+		removed, err := q.redis.Client.LRem(ctx, SubmissionDeadName, 1, raw).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove dead submission: %w", err)
+		}
+		if removed == 0 {
+			// Removed concurrently by someone else
+			return nil, ErrJobNotFound
+		}
+		return &job, nil
+	}
+
+	return nil, ErrJobNotFound
+}
+
+// promoteDueRetries moves retried jobs whose backoff has elapsed back onto the queue
+func (q *jobQueue) promoteDueRetries(ctx context.Context) error {
+	due, err := q.redis.Client.ZRangeByScore(ctx, SubmissionRetryName, &goredis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().Unix(), 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, raw := range due {
+		// Only the worker that wins the ZREM re-enqueues the job
+		removed, err := q.redis.Client.ZRem(ctx, SubmissionRetryName, raw).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		var job SubmissionJob
+		_ = json.Unmarshal([]byte(raw), &job)
+		if err := q.pushToLane(ctx, job.Lane, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pushToLane pushes a raw payload onto a lane and wakes up one idle worker
+func (q *jobQueue) pushToLane(ctx context.Context, lane Lane, raw string) error {
+	pipe := q.redis.Client.TxPipeline()
+	pipe.LPush(ctx, LaneKey(lane), raw)
+	pipe.LPush(ctx, SubmissionWakeupName, 1)
+	// Wakeups only need to outnumber idle workers
+	pipe.LTrim(ctx, SubmissionWakeupName, 0, 99)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func retryBackoff(attempts int) time.Duration {
+	return RetryBaseBackoff * time.Duration(1<<(attempts-1))
+}
+
 // EnqueueAchievement pushes an achievement evaluation job to the Redis queue
 func (q *jobQueue) EnqueueAchievement(ctx context.Context, submissionID int) error {
-	job := AchievementJob{
-		SubmissionID: submissionID,
-		EnqueuedAt:   time.Now(),
-	}
+	return q.enqueueAchievement(ctx, AchievementJob{SubmissionID: submissionID})
+}
+
+// EnqueueAchievementRecheck pushes a job re-evaluating the achievements of a
+// rejudged submission's user
+func (q *jobQueue) EnqueueAchievementRecheck(ctx context.Context, submissionID int) error {
+	return q.enqueueAchievement(ctx, AchievementJob{SubmissionID: submissionID, Recheck: true})
+}
+
+func (q *jobQueue) enqueueAchievement(ctx context.Context, job AchievementJob) error {
+	submissionID := job.SubmissionID
+	job.EnqueuedAt = time.Now()
 
 	jobData, err := json.Marshal(job)
 	if err != nil {
@@ -122,6 +403,7 @@ func (q *jobQueue) EnqueueAchievement(ctx context.Context, submissionID int) err
 
 	q.logger.Info("Achievement job enqueued successfully",
 		zap.Int("submission_id", submissionID),
+		zap.Bool("recheck", job.Recheck),
 		zap.String("queue", AchievementQueueName),
 	)
 
@@ -0,0 +1,166 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prabalesh/loco/backend/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestQueue(t *testing.T) (*jobQueue, *miniredis.Miniredis) {
+	t.Helper()
+
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to create miniredis: %v", err)
+	}
+	t.Cleanup(s.Close)
+
+	client := &redis.RedisClient{
+		Client: goredis.NewClient(&goredis.Options{Addr: s.Addr()}),
+		Logger: zap.NewNop(),
+	}
+
+	return &jobQueue{redis: client, logger: zap.NewNop()}, s
+}
+
+func TestSubmissionAckRemovesFromProcessing(t *testing.T) {
+	q, s := newTestQueue(t)
+	ctx := context.Background()
+
+	if err := q.EnqueueSubmission(ctx, 42, LaneSubmit); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	job, err := q.DequeueSubmission(ctx, Lanes)
+	if err != nil || job == nil {
+		t.Fatalf("Dequeue failed: job=%v err=%v", job, err)
+	}
+
+	if got, _ := s.List(SubmissionProcessingName); len(got) != 1 {
+		t.Fatalf("Expected job in processing list, got %d", len(got))
+	}
+
+	if err := q.AckSubmission(ctx, job); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+
+	if got, _ := s.List(SubmissionProcessingName); len(got) != 0 {
+		t.Errorf("Expected empty processing list after ack, got %d", len(got))
+	}
+}
+
+func TestSubmissionNackRetriesThenDeadLetters(t *testing.T) {
+	q, _ := newTestQueue(t)
+	ctx := context.Background()
+
+	// Make retries due immediately
+	oldBackoff := RetryBaseBackoff
+	RetryBaseBackoff = 0
+	defer func() { RetryBaseBackoff = oldBackoff }()
+
+	if err := q.EnqueueSubmission(ctx, 7, LaneSubmit); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	for attempt := 1; attempt <= MaxSubmissionAttempts; attempt++ {
+		job, err := q.DequeueSubmission(ctx, Lanes)
+		if err != nil || job == nil {
+			t.Fatalf("Attempt %d: dequeue failed: job=%v err=%v", attempt, job, err)
+		}
+
+		dead, err := q.NackSubmission(ctx, job, errors.New("piston down"))
+		if err != nil {
+			t.Fatalf("Attempt %d: nack failed: %v", attempt, err)
+		}
+		if dead != (attempt == MaxSubmissionAttempts) {
+			t.Fatalf("Attempt %d: unexpected dead-letter result %v", attempt, dead)
+		}
+	}
+
+	jobs, total, err := q.ListDeadSubmissions(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("ListDeadSubmissions failed: %v", err)
+	}
+	if total != 1 || len(jobs) != 1 || jobs[0].SubmissionID != 7 {
+		t.Fatalf("Expected submission 7 in dead letters, got %+v", jobs)
+	}
+	if jobs[0].LastError != "piston down" {
+		t.Errorf("Expected last error to be recorded, got %q", jobs[0].LastError)
+	}
+
+	dead, err := q.RemoveDeadSubmission(ctx, 7)
+	if err != nil || dead.SubmissionID != 7 {
+		t.Fatalf("RemoveDeadSubmission failed: %+v err=%v", dead, err)
+	}
+	if err := q.EnqueueSubmission(ctx, 7, dead.Lane); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	job, err := q.DequeueSubmission(ctx, Lanes)
+	if err != nil || job == nil || job.SubmissionID != 7 || job.Attempts != 0 {
+		t.Fatalf("Expected replayed job with fresh attempts, got %+v err=%v", job, err)
+	}
+
+	if _, err := q.RemoveDeadSubmission(ctx, 7); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected ErrJobNotFound on second removal, got %v", err)
+	}
+}
+
+func TestRequeueStaleSubmissions(t *testing.T) {
+	q, s := newTestQueue(t)
+	ctx := context.Background()
+
+	if err := q.EnqueueSubmission(ctx, 9, LaneSubmit); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	// Simulate a worker that dequeued the job and then died
+	if _, err := q.DequeueSubmission(ctx, Lanes); err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+
+	recovered, err := q.RequeueStaleSubmissions(ctx, time.Hour)
+	if err != nil || recovered != 0 {
+		t.Fatalf("Expected fresh lease to be kept, recovered=%d err=%v", recovered, err)
+	}
+
+	recovered, err = q.RequeueStaleSubmissions(ctx, -time.Second)
+	if err != nil || recovered != 1 {
+		t.Fatalf("Expected 1 recovered job, recovered=%d err=%v", recovered, err)
+	}
+
+	if got, _ := s.List(SubmissionProcessingName); len(got) != 0 {
+		t.Errorf("Expected empty processing list, got %d", len(got))
+	}
+	if got, _ := s.ZMembers(SubmissionRetryName); len(got) != 1 {
+		t.Errorf("Expected job scheduled for retry, got %d", len(got))
+	}
+}
+
+func TestDequeueFollowsLaneOrder(t *testing.T) {
+	q, _ := newTestQueue(t)
+	ctx := context.Background()
+
+	if err := q.EnqueueSubmission(ctx, 1, LaneBulk); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := q.EnqueueSubmission(ctx, 2, LaneRun); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	job, err := q.DequeueSubmission(ctx, []Lane{LaneBulk, LaneRun})
+	if err != nil || job == nil || job.SubmissionID != 1 || job.Lane != LaneBulk {
+		t.Fatalf("Expected bulk job first, got %+v err=%v", job, err)
+	}
+
+	job, err = q.DequeueSubmission(ctx, []Lane{LaneBulk, LaneRun})
+	if err != nil || job == nil || job.SubmissionID != 2 || job.Lane != LaneRun {
+		t.Fatalf("Expected run job once bulk lane is empty, got %+v err=%v", job, err)
+	}
+}
@@ -57,7 +57,7 @@ func (w *AchievementWorker) Start(ctx context.Context) {
 				continue
 			}
 
-			w.processAchievementJob(ctx, job.SubmissionID)
+			w.processAchievementJob(ctx, job)
 		}
 	}
 }
@@ -66,8 +66,9 @@ func (w *AchievementWorker) Stop() {
 	close(w.stopChan)
 }
 
-func (w *AchievementWorker) processAchievementJob(ctx context.Context, submissionID int) {
-	w.logger.Debug("Processing achievement job", zap.Int("submission_id", submissionID))
+func (w *AchievementWorker) processAchievementJob(ctx context.Context, job *queue.AchievementJob) {
+	submissionID := job.SubmissionID
+	w.logger.Debug("Processing achievement job", zap.Int("submission_id", submissionID), zap.Bool("recheck", job.Recheck))
 
 	// 1. Fetch submission
 	submission, err := w.submissionRepo.GetByID(submissionID)
@@ -125,6 +126,17 @@ func (w *AchievementWorker) processAchievementJob(ctx context.Context, submissio
 		)
 	}
 
+	// A rejudge may have taken back solves the user was awarded for
+	if job.Recheck {
+		if err := w.achievementUsecase.RevokeUnmetAchievements(submission.UserID, stats); err != nil {
+			w.logger.Error("Failed to revoke achievements",
+				zap.Error(err),
+				zap.Int("submission_id", submissionID),
+				zap.Int("user_id", submission.UserID),
+			)
+		}
+	}
+
 	w.logger.Info("Achievement evaluation completed",
 		zap.Int("submission_id", submissionID),
 		zap.Int("user_id", submission.UserID),
@@ -0,0 +1,687 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/blobstore"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/codegen"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
+	"github.com/prabalesh/loco/backend/pkg/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// The fakes below embed the repository interfaces so they only implement what the
+// worker actually calls; anything else panics loudly.
+
+type fakeSubmissionRepo struct {
+	domain.SubmissionRepository
+	updated []domain.Submission
+}
+
+func (r *fakeSubmissionRepo) Update(s *domain.Submission) error {
+	r.updated = append(r.updated, *s)
+	return nil
+}
+
+func (r *fakeSubmissionRepo) last() domain.Submission {
+	return r.updated[len(r.updated)-1]
+}
+
+func (r *fakeSubmissionRepo) ListVerdictsByUserProblem(userID int, problemID int) ([]domain.Submission, error) {
+	return r.updated, nil
+}
+
+type fakeProblemRepo struct {
+	domain.ProblemRepository
+	incremented, recounted int
+}
+
+func (r *fakeProblemRepo) IncrementStats(id int, isAccepted bool) error {
+	r.incremented++
+	return nil
+}
+
+func (r *fakeProblemRepo) RecountStats(id int) error {
+	r.recounted++
+	return nil
+}
+
+type fakeTestCaseRepo struct {
+	domain.TestCaseRepository
+	testCases []domain.TestCase
+}
+
+func (r *fakeTestCaseRepo) GetByProblemID(problemID int) ([]domain.TestCase, error) {
+	return r.testCases, nil
+}
+
+func (r *fakeTestCaseRepo) GetSamples(problemID int) ([]domain.TestCase, error) {
+	var samples []domain.TestCase
+	for _, tc := range r.testCases {
+		if tc.IsSample {
+			samples = append(samples, tc)
+		}
+	}
+	return samples, nil
+}
+
+type fakeUserStatsRepo struct {
+	domain.UserProblemStatsRepository
+	replaced []domain.UserProblemStats
+}
+
+func (r *fakeUserStatsRepo) Upsert(stats *domain.UserProblemStats) error { return nil }
+
+func (r *fakeUserStatsRepo) Replace(stats *domain.UserProblemStats) error {
+	r.replaced = append(r.replaced, *stats)
+	return nil
+}
+
+type fakeRejudgeRepo struct {
+	domain.RejudgeRepository
+	recorded map[int]domain.Submission
+}
+
+func (r *fakeRejudgeRepo) RecordResult(rejudgeID int, submission *domain.Submission) error {
+	r.recorded[rejudgeID] = *submission
+	return nil
+}
+
+type fakeJudgementRepo struct {
+	domain.SubmissionJudgementRepository
+	judgements []domain.SubmissionJudgement
+}
+
+func (r *fakeJudgementRepo) Create(judgement *domain.SubmissionJudgement) error {
+	judgement.ID = len(r.judgements) + 1
+	r.judgements = append(r.judgements, *judgement)
+	return nil
+}
+
+type fakeBoilerplateService struct {
+	domain.BoilerplateService
+	version string // defaults to a harness that predates nonces
+}
+
+func (s *fakeBoilerplateService) GetTestHarnessTemplate(problemID, languageID int) (string, error) {
+	return "harness({USER_CODE})", nil
+}
+
+func (s *fakeBoilerplateService) GetHarnessVersion(problemID, languageID int) (string, error) {
+	if s.version == "" {
+		return "python.v1", nil
+	}
+	return s.version, nil
+}
+
+func (s *fakeBoilerplateService) InjectUserCodeIntoHarness(template, userCode string) string {
+	return userCode
+}
+
+// echoHarness plays the part of a generated harness: the "user code" is the value
+// returned for every test, so a test passes when that equals the expected output.
+// Like a user's debug prints, a line of noise comes before the result marker.
+func echoHarness(req *executor.Request) (*executor.Result, error) {
+	var tests []struct {
+		Input    interface{} `json:"input"`
+		Expected interface{} `json:"expected"`
+	}
+	if err := json.Unmarshal([]byte(req.Stdin), &tests); err != nil {
+		return nil, err
+	}
+
+	var answer interface{}
+	_ = json.Unmarshal([]byte(req.Code), &answer)
+
+	verdict := "ACCEPTED"
+	results := make([]map[string]interface{}, len(tests))
+	for i, tc := range tests {
+		status := "passed"
+		if !reflect.DeepEqual(tc.Expected, answer) {
+			status = "failed"
+			verdict = "WRONG_ANSWER"
+		}
+		results[i] = map[string]interface{}{"status": status, "actual": req.Code, "time_ms": 3}
+	}
+
+	out, _ := json.Marshal(map[string]interface{}{
+		"verdict":      verdict,
+		"runtime":      3,
+		"memory":       1024,
+		"test_results": results,
+	})
+	return &executor.Result{Output: "debugging {not json}\n" + codegen.HarnessResultMarker + "\n" + string(out)}, nil
+}
+
+func newTestWorker(t *testing.T, exec executor.Executor, testCases []domain.TestCase) (*Worker, *fakeSubmissionRepo) {
+	t.Helper()
+
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to create miniredis: %v", err)
+	}
+	t.Cleanup(s.Close)
+
+	// Everything longer than a byte goes to the store, once a test offloads it
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	submissions := &fakeSubmissionRepo{}
+	w := NewWorker(
+		&mockQueue{},
+		submissions,
+		&fakeProblemRepo{},
+		&fakeTestCaseRepo{testCases: testCases},
+		nil,
+		nil,
+		nil,
+		exec,
+		&fakeBoilerplateService{},
+		testdata.NewTestDataService(store, 1),
+		&fakeUserStatsRepo{},
+		&fakeRejudgeRepo{recorded: make(map[int]domain.Submission)},
+		&fakeJudgementRepo{},
+		zap.NewNop(),
+		redis.NewClient(&redis.Options{Addr: s.Addr()}),
+		&config.Config{Worker: config.WorkerConfig{MaxConcurrentSubmissions: 1, BatchSize: 2}},
+	)
+	return w, submissions
+}
+
+func makeTestCases(expected ...string) []domain.TestCase {
+	testCases := make([]domain.TestCase, len(expected))
+	for i, e := range expected {
+		testCases[i] = domain.TestCase{ID: i + 1, Input: fmt.Sprintf("[%d]", i), ExpectedOutput: e, IsSample: i == 0}
+	}
+	return testCases
+}
+
+func TestEvaluateSubmissionAccepted(t *testing.T) {
+	exec := executor.NewFakeExecutor(echoHarness)
+	w, submissions := newTestWorker(t, exec, makeTestCases("42", "42", "42"))
+
+	submission := &domain.Submission{ID: 1, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python", Version: "3.10.0"}, false)
+	if err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusAccepted {
+		t.Fatalf("Expected Accepted, got %s (%s)", got.Status, got.ErrorMessage)
+	}
+	if got.PassedTestCases != 3 || got.TotalTestCases != 3 {
+		t.Errorf("Expected 3/3 passed, got %d/%d", got.PassedTestCases, got.TotalTestCases)
+	}
+
+	// Every test runs on its own, whatever the batch
+	requests := exec.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected a run per test, got %d", len(requests))
+	}
+	if requests[0].Language != "python" || requests[0].SubmissionID == nil || *requests[0].SubmissionID != 1 {
+		t.Errorf("Unexpected executor request: %+v", requests[0])
+	}
+	// Every test gets the default 1s on top of the startup headroom
+	for _, req := range requests {
+		if strings.Count(req.Stdin, `"time_limit_ms":1000`) != 1 {
+			t.Errorf("Expected a single test per run, got %s", req.Stdin)
+		}
+		if req.TimeLimitMs != domain.DefaultTimeLimitMs+codegen.HarnessStartupMs || req.MemoryLimitMb != domain.DefaultMemoryLimitMb {
+			t.Errorf("Unexpected limits: %d ms, %d MB", req.TimeLimitMs, req.MemoryLimitMb)
+		}
+	}
+}
+
+func TestEvaluateSubmissionMeasuresEachTest(t *testing.T) {
+	// The executor's measurements of each run replace what the harness reports
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		res, err := echoHarness(req)
+		if err != nil {
+			return nil, err
+		}
+		res.Runtime, res.Memory = 30, 10000
+		if strings.Contains(req.Stdin, `"input":[1]`) {
+			res.Runtime, res.Memory = 80, 9500
+		}
+		return res, nil
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42", "42"))
+
+	python := &domain.Language{Slug: "python", ExecutorConfig: domain.ExecutorConfig{
+		domain.ExecutorConfigBaselineRuntimeMs: 20.0,
+		domain.ExecutorConfigBaselineMemoryKB:  9000.0,
+	}}
+	submission := &domain.Submission{ID: 13, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, python, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if tr := got.TestCaseResults; tr[0].TimeMS != 10 || tr[0].MemoryKB != 1000 || tr[1].TimeMS != 60 || tr[1].MemoryKB != 500 {
+		t.Errorf("Expected each test's usage net of the baselines, got %+v", tr)
+	}
+	if got.Runtime != 60 || got.Memory != 1000 {
+		t.Errorf("Expected the slowest test's runtime and the peak memory, got %d ms and %d KB", got.Runtime, got.Memory)
+	}
+}
+
+func TestEvaluateSubmissionLoadsStoredTestData(t *testing.T) {
+	testCases := makeTestCases("42", "42")
+	exec := executor.NewFakeExecutor(echoHarness)
+	w, submissions := newTestWorker(t, exec, testCases)
+	if err := w.testData.Offload(context.Background(), &testCases[1]); err != nil {
+		t.Fatal(err)
+	}
+	if testCases[1].InputKey == nil || testCases[1].ExpectedOutputKey == nil {
+		t.Fatalf("Expected the test's values to be stored, got %+v", testCases[1])
+	}
+
+	submission := &domain.Submission{ID: 7, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusAccepted {
+		t.Fatalf("Expected Accepted with the stored values, got %s (%s)", got.Status, got.ErrorMessage)
+	}
+	if got.TestCaseResults[1].Input != "" || got.TestCaseResults[1].ExpectedOutput != "" {
+		t.Errorf("Expected stored values left out of the results, got %+v", got.TestCaseResults[1])
+	}
+	req := exec.Requests()[1]
+	if want := testCases[1].StoredKeys(); !reflect.DeepEqual(req.StdinKeys, want) || !strings.Contains(req.LoggedStdin(0), want[0]) {
+		t.Errorf("Expected stdin keys %v, got %v", want, req.StdinKeys)
+	}
+}
+
+func TestEvaluateSubmissionWrongAnswer(t *testing.T) {
+	w, submissions := newTestWorker(t, executor.NewFakeExecutor(echoHarness), makeTestCases("42", "7"))
+
+	submission := &domain.Submission{ID: 2, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusWrongAnswer {
+		t.Fatalf("Expected Wrong Answer, got %s", got.Status)
+	}
+	if got.PassedTestCases != 1 {
+		t.Errorf("Expected 1 passed test, got %d", got.PassedTestCases)
+	}
+}
+
+func TestEvaluateSubmissionScoresSubtasks(t *testing.T) {
+	testCases := makeTestCases("42", "7", "42", "42", "42")
+	for i, subtask := range []string{"small", "small", "medium", "medium", "edge"} {
+		testCases[i].Subtask = subtask
+	}
+	problem := &domain.Problem{ID: 1, Subtasks: domain.Subtasks{
+		{Name: "small", Points: 20},
+		{Name: "medium", Points: 30, DependsOn: []string{"small"}},
+		{Name: "edge", Points: 50},
+	}}
+	exec := executor.NewFakeExecutor(echoHarness)
+	w, submissions := newTestWorker(t, exec, testCases)
+
+	submission := &domain.Submission{ID: 8, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, problem, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	// The failure in the first batch doesn't stop the others
+	if len(exec.Requests()) != len(testCases) {
+		t.Fatalf("Expected every test to run, got %d", len(exec.Requests()))
+	}
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusWrongAnswer || got.PassedTestCases != 4 {
+		t.Fatalf("Expected Wrong Answer with 4 tests passed, got %s with %d", got.Status, got.PassedTestCases)
+	}
+	if got.Score != 50 || len(got.SubtaskResults) != 3 {
+		t.Fatalf("Expected a score of 50 from 3 subtasks, got %d from %+v", got.Score, got.SubtaskResults)
+	}
+	if got.SubtaskResults[1].Status != domain.SubtaskStatusSkipped || got.TestCaseResults[2].Subtask != "medium" {
+		t.Errorf("Expected medium skipped for its failed dependency, got %+v", got.SubtaskResults[1])
+	}
+}
+
+func TestEvaluateSubmissionRejudged(t *testing.T) {
+	w, submissions := newTestWorker(t, executor.NewFakeExecutor(echoHarness), makeTestCases("42", "7"))
+
+	rejudgeID := 5
+	submission := &domain.Submission{ID: 3, UserID: 9, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending, RejudgeID: &rejudgeID}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusWrongAnswer || got.RejudgeID != nil {
+		t.Fatalf("Expected a Wrong Answer out of the rejudge, got %s in rejudge %v", got.Status, got.RejudgeID)
+	}
+	recorded, ok := w.rejudgeRepo.(*fakeRejudgeRepo).recorded[rejudgeID]
+	if !ok || recorded.Status != domain.SubmissionStatusWrongAnswer {
+		t.Errorf("Expected the new verdict recorded in the rejudge, got %+v", recorded)
+	}
+
+	// The old verdict was counted already, so the stats are recounted rather than
+	// counted again
+	problems := w.problemRepo.(*fakeProblemRepo)
+	if problems.incremented != 0 || problems.recounted != 1 {
+		t.Errorf("Expected the problem stats recounted once and never incremented, got %d recounts and %d increments", problems.recounted, problems.incremented)
+	}
+	replaced := w.userProblemStatsRepo.(*fakeUserStatsRepo).replaced
+	if len(replaced) != 1 || replaced[0].Status != "attempted" || replaced[0].Attempts != 1 {
+		t.Errorf("Expected the user's stats recounted as one attempt, got %+v", replaced)
+	}
+}
+
+func TestEvaluateSubmissionRecordsJudgements(t *testing.T) {
+	w, submissions := newTestWorker(t, executor.NewFakeExecutor(echoHarness), makeTestCases("42", "7"))
+
+	// Judged twice, as after a retry, so each attempt is kept
+	submission := &domain.Submission{ID: 4, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	for i := 0; i < 2; i++ {
+		if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+			t.Fatalf("evaluateSubmission returned error: %v", err)
+		}
+	}
+
+	judgements := w.judgementRepo.(*fakeJudgementRepo).judgements
+	if len(judgements) != 2 {
+		t.Fatalf("Expected a judgement per attempt, got %d", len(judgements))
+	}
+	for _, j := range judgements {
+		if j.SubmissionID != 4 || j.WorkerID != w.workerID || j.Executor != executor.DriverPiston || j.HarnessVersion != "python.v1" {
+			t.Errorf("Unexpected judgement: %+v", j)
+		}
+		if j.Status != domain.SubmissionStatusWrongAnswer || j.PassedTestCases != 1 || len(j.TestCaseResults) != 2 {
+			t.Errorf("Expected the Wrong Answer with its test results, got %s with %d results", j.Status, len(j.TestCaseResults))
+		}
+		if j.FinishedAt.Before(j.StartedAt) {
+			t.Errorf("Expected judging to finish after it started, got %v to %v", j.StartedAt, j.FinishedAt)
+		}
+	}
+	if got := submissions.last(); got.JudgementID == nil || *got.JudgementID != judgements[1].ID {
+		t.Errorf("Expected the submission to point at the latest judgement %d, got %v", judgements[1].ID, got.JudgementID)
+	}
+}
+
+func TestEvaluateSubmissionCompilationError(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{ExitCode: 1, Error: "syntax error", CompileFailed: true}, nil
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42"))
+
+	submission := &domain.Submission{ID: 3, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "c++"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	if got := submissions.last(); got.Status != domain.SubmissionStatusCompilationError {
+		t.Fatalf("Expected Compilation Error, got %s", got.Status)
+	}
+}
+
+func TestEvaluateSubmissionMemoryLimitFromHarness(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		if strings.Contains(req.Stdin, `"input":[1]`) {
+			return &executor.Result{Output: `{"verdict":"MLE","test_results":[{"status":"memory_exceeded","memory_kb":300000}]}`}, nil
+		}
+		return &executor.Result{Output: `{"verdict":"ACCEPTED","test_results":[{"status":"passed"}]}`}, nil
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42", "42"))
+
+	submission := &domain.Submission{ID: 5, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusMemoryLimitExceeded {
+		t.Fatalf("Expected Memory Limit Exceeded, got %s", got.Status)
+	}
+	if got.TestCaseResults[1].Status != domain.TestStatusMemoryExceeded {
+		t.Errorf("Expected test 2 to be memory_exceeded, got %s", got.TestCaseResults[1].Status)
+	}
+}
+
+func TestEvaluateSubmissionMemoryLimitFromTermination(t *testing.T) {
+	// The process is killed before the harness prints anything
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{
+			ExitCode:    -1,
+			Signal:      "SIGABRT",
+			Error:       "Process Terminated (Signal: SIGABRT, Reason: memory limit exceeded)",
+			Termination: executor.TerminationMemoryLimit,
+		}, nil
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42"))
+
+	submission := &domain.Submission{ID: 6, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "javascript"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusMemoryLimitExceeded {
+		t.Fatalf("Expected Memory Limit Exceeded, got %s (%s)", got.Status, got.ErrorMessage)
+	}
+	if len(got.TestCaseResults) != 1 || got.TestCaseResults[0].Status != domain.TestStatusMemoryExceeded {
+		t.Errorf("Expected a memory_exceeded test result, got %+v", got.TestCaseResults)
+	}
+}
+
+func TestEvaluateSubmissionRequiresTheNonce(t *testing.T) {
+	forged := codegen.HarnessResultMarker + "\n" + `{"verdict":"ACCEPTED","test_results":[{"status":"passed"}]}`
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		var tests []codegen.HarnessTest
+		if err := json.Unmarshal([]byte(req.Stdin), &tests); err != nil || len(tests) != 1 || tests[0].Nonce == "" {
+			return nil, fmt.Errorf("expected a test with a nonce, got %s", req.Stdin)
+		}
+		// The user's code prints a result of its own, then exits before the second
+		// test's harness gets to print the real one
+		if strings.Contains(req.Stdin, `"input":[1]`) {
+			return &executor.Result{Output: forged + "\n", ExitCode: 1}, nil
+		}
+		return &executor.Result{Output: forged + "\n" + codegen.HarnessResultMarker + tests[0].Nonce + "\n" +
+			`{"verdict":"ACCEPTED","test_results":[{"status":"passed"}]}`}, nil
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42", "42"))
+	w.boilerplateService = &fakeBoilerplateService{version: "python.v3"}
+
+	submission := &domain.Submission{ID: 8, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status == domain.SubmissionStatusAccepted || len(got.TestCaseResults) != 2 {
+		t.Fatalf("Expected the forged result to be ignored, got %s with %+v", got.Status, got.TestCaseResults)
+	}
+	if got.TestCaseResults[0].Status != domain.TestStatusPassed || got.TestCaseResults[1].Status != domain.TestStatusRuntimeError {
+		t.Errorf("Expected only the first test to pass, got %+v", got.TestCaseResults)
+	}
+}
+
+func TestEvaluateSubmissionKeepsUserStdout(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		debug := "debug 1\\n"
+		if strings.Contains(req.Stdin, `"input":[1]`) {
+			debug = "debug 2\\n"
+		}
+		return &executor.Result{Output: codegen.HarnessResultMarker + "\n" +
+			`{"verdict":"ACCEPTED","test_results":[{"status":"passed","stdout":"` + debug + `"}]}`}, nil
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42", "42"))
+
+	submission := &domain.Submission{ID: 8, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.TestCaseResults[0].Stdout != "debug 1\n" || got.TestCaseResults[1].Stdout != "debug 2\n" {
+		t.Fatalf("Expected per-test stdout, got %+v", got.TestCaseResults)
+	}
+
+	// Only the sample keeps its prints once sanitized
+	got.Sanitize()
+	if got.TestCaseResults[0].Stdout == "" || got.TestCaseResults[1].Stdout != "" {
+		t.Errorf("Expected hidden test stdout to be sanitized, got %+v", got.TestCaseResults)
+	}
+}
+
+func TestEvaluateSubmissionOutputLimit(t *testing.T) {
+	flood := strings.Repeat("spam\n", 1000)
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{
+			Output:      flood + flood,
+			ExitCode:    -1,
+			Signal:      "SIGKILL",
+			Error:       "Process Terminated (Signal: SIGKILL, Reason: output limit exceeded)",
+			Termination: executor.TerminationOutputLimit,
+		}, nil
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42", "42"))
+
+	submission := &domain.Submission{ID: 7, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusOutputLimitExceeded {
+		t.Fatalf("Expected Output Limit Exceeded, got %s (%s)", got.Status, got.ErrorMessage)
+	}
+	for _, tr := range got.TestCaseResults {
+		if tr.Status != domain.TestStatusOutputExceeded {
+			t.Errorf("Expected output_exceeded test results, got %+v", got.TestCaseResults)
+			break
+		}
+	}
+}
+
+func TestEvaluateSubmissionExecutorDownIsRetryable(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return nil, errors.New("connection refused")
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42"))
+
+	submission := &domain.Submission{ID: 4, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	err := w.evaluateSubmission(submission, &domain.Problem{ID: 1}, &domain.Language{Slug: "python"}, false)
+	if !errors.Is(err, errExecutorUnavailable) {
+		t.Fatalf("Expected errExecutorUnavailable, got %v", err)
+	}
+	if len(submissions.updated) != 0 {
+		t.Errorf("Submission must stay untouched so it can be retried, got %+v", submissions.updated)
+	}
+}
+
+// checkerProblem is a CUSTOM problem whose checker rejects answers to the input [1]
+func checkerProblem() *domain.Problem {
+	language, code := "python", "checker"
+	return &domain.Problem{ID: 1, ValidationType: domain.ValidationTypeCustom, CheckerLanguage: &language, CheckerCode: &code}
+}
+
+func rejectSecondTest(req *executor.Request) (*executor.Result, error) {
+	if req.Code != "checker" {
+		return echoHarness(req)
+	}
+	var in struct {
+		Input  json.RawMessage `json:"input"`
+		Actual json.RawMessage `json:"actual"`
+	}
+	if err := json.Unmarshal([]byte(req.Stdin), &in); err != nil {
+		return nil, err
+	}
+	if string(in.Input) == "[1]" {
+		return &executor.Result{Output: "not good enough: " + string(in.Actual) + "\n", ExitCode: 1}, nil
+	}
+	return &executor.Result{}, nil
+}
+
+func TestEvaluateSubmissionCheckerRejects(t *testing.T) {
+	exec := executor.NewFakeExecutor(rejectSecondTest)
+	w, submissions := newTestWorker(t, exec, makeTestCases("42", "42", "42"))
+
+	submission := &domain.Submission{ID: 9, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, checkerProblem(), &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusWrongAnswer {
+		t.Fatalf("Expected Wrong Answer, got %s (%s)", got.Status, got.ErrorMessage)
+	}
+	if got.ErrorMessage != "Failed on test 2: not good enough: 42" {
+		t.Errorf("Unexpected error message %q", got.ErrorMessage)
+	}
+	if tr := got.TestCaseResults[1]; tr.Status != domain.TestStatusFailed || tr.Error != "not good enough: 42" {
+		t.Errorf("Expected test 2 to be rejected by the checker, got %+v", tr)
+	}
+}
+
+func TestEvaluateSubmissionBrokenCheckerIsInternalError(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		if req.Code == "checker" {
+			return &executor.Result{ExitCode: 2, Error: "Traceback: NameError"}, nil
+		}
+		return echoHarness(req)
+	})
+	w, submissions := newTestWorker(t, exec, makeTestCases("42"))
+
+	submission := &domain.Submission{ID: 10, ProblemID: 1, LanguageID: 1, Code: "42", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, checkerProblem(), &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusInternalError || !strings.Contains(got.ErrorMessage, "NameError") {
+		t.Fatalf("Expected an Internal Error naming the checker failure, got %s (%s)", got.Status, got.ErrorMessage)
+	}
+}
+
+func TestEvaluateSubmissionStdioSkipsHarness(t *testing.T) {
+	// The program echoes its input, which is only right for the first test
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{Output: req.Stdin, Runtime: 4, Memory: 700}, nil
+	})
+	testCases := []domain.TestCase{
+		{ID: 1, Input: "hello\n", ExpectedOutput: "hello", IsSample: true},
+		{ID: 2, Input: "1 2\n", ExpectedOutput: "3"},
+	}
+	w, submissions := newTestWorker(t, exec, testCases)
+
+	problem := &domain.Problem{ID: 1, JudgeMode: domain.JudgeModeStdio, ValidationType: domain.ValidationTypeTokens}
+	submission := &domain.Submission{ID: 12, ProblemID: 1, LanguageID: 1, Code: "cat", Status: domain.SubmissionStatusPending}
+	if err := w.evaluateSubmission(submission, problem, &domain.Language{Slug: "python"}, false); err != nil {
+		t.Fatalf("evaluateSubmission returned error: %v", err)
+	}
+
+	got := submissions.last()
+	if got.Status != domain.SubmissionStatusWrongAnswer || got.ErrorMessage != "Failed on test 2" {
+		t.Fatalf("Expected Wrong Answer on test 2, got %s (%s)", got.Status, got.ErrorMessage)
+	}
+	if got.PassedTestCases != 1 || got.TotalTestCases != 2 {
+		t.Errorf("Expected 1/2 passed, got %d/%d", got.PassedTestCases, got.TotalTestCases)
+	}
+
+	// One run per test with the raw input and the user's code as is
+	requests := exec.Requests()
+	if len(requests) != 2 || requests[0].Code != "cat" || requests[0].Stdin != "hello\n" {
+		t.Errorf("Unexpected executor requests %+v", requests)
+	}
+}
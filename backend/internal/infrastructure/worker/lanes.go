@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
+)
+
+// laneScheduler decides which lane a worker serves next using smooth weighted
+// round-robin: over any window every lane gets dequeues in proportion to its
+// weight, but picks are interleaved rather than bursty.
+type laneScheduler struct {
+	mu      sync.Mutex
+	weights map[queue.Lane]int
+	current map[queue.Lane]int
+	total   int
+}
+
+func newLaneScheduler(overrides map[string]int) *laneScheduler {
+	weights := make(map[queue.Lane]int, len(queue.Lanes))
+	total := 0
+	for _, lane := range queue.Lanes {
+		weight := queue.DefaultLaneWeights[lane]
+		if w, ok := overrides[string(lane)]; ok && w > 0 {
+			weight = w
+		}
+		weights[lane] = weight
+		total += weight
+	}
+
+	return &laneScheduler{
+		weights: weights,
+		current: make(map[queue.Lane]int, len(queue.Lanes)),
+		total:   total,
+	}
+}
+
+// next returns every lane in the order they should be tried for the next dequeue:
+// the lane whose turn it is first, then the rest by priority so no dequeue is
+// wasted when the chosen lane is empty.
+func (s *laneScheduler) next() []queue.Lane {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var chosen queue.Lane
+	for _, lane := range queue.Lanes {
+		s.current[lane] += s.weights[lane]
+		if chosen == "" || s.current[lane] > s.current[chosen] {
+			chosen = lane
+		}
+	}
+	s.current[chosen] -= s.total
+
+	order := make([]queue.Lane, 0, len(queue.Lanes))
+	order = append(order, chosen)
+	for _, lane := range queue.Lanes {
+		if lane != chosen {
+			order = append(order, lane)
+		}
+	}
+	return order
+}
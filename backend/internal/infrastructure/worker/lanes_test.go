@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
+)
+
+func TestLaneSchedulerHonoursWeights(t *testing.T) {
+	s := newLaneScheduler(nil)
+
+	picks := make(map[queue.Lane]int)
+	rounds := 15 * 10 // a whole number of cycles for the default weights
+	for i := 0; i < rounds; i++ {
+		order := s.next()
+		if len(order) != len(queue.Lanes) {
+			t.Fatalf("Expected every lane in the order, got %v", order)
+		}
+		picks[order[0]]++
+	}
+
+	for _, lane := range queue.Lanes {
+		want := queue.DefaultLaneWeights[lane] * 10
+		if picks[lane] != want {
+			t.Errorf("Lane %s picked %d times, want %d", lane, picks[lane], want)
+		}
+	}
+}
+
+func TestLaneSchedulerOverrides(t *testing.T) {
+	s := newLaneScheduler(map[string]int{"bulk": 8, "run": 1, "submit": 1, "validation": 1})
+
+	bulkFirst := 0
+	for i := 0; i < 11; i++ {
+		if s.next()[0] == queue.LaneBulk {
+			bulkFirst++
+		}
+	}
+
+	if bulkFirst != 8 {
+		t.Errorf("Expected bulk lane to be picked 8 times, got %d", bulkFirst)
+	}
+}
@@ -0,0 +1,239 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
+	"github.com/prabalesh/loco/backend/pkg/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// reaperBatchSize caps how many stuck submissions are inspected per sweep
+const reaperBatchSize = 100
+
+// Reaper periodically heals submissions stuck in Pending/Processing that no queue
+// entry and no live worker accounts for, by re-enqueueing them or failing them
+// with an internal error once they've been re-enqueued too often.
+type Reaper struct {
+	queue          queue.JobQueue
+	submissionRepo domain.SubmissionRepository
+	redisClient    *redis.Client
+	logger         *zap.Logger
+	config         *config.Config
+	stopChan       chan struct{}
+}
+
+func NewReaper(
+	queue queue.JobQueue,
+	submissionRepo domain.SubmissionRepository,
+	redisClient *redis.Client,
+	logger *zap.Logger,
+	cfg *config.Config,
+) *Reaper {
+	return &Reaper{
+		queue:          queue,
+		submissionRepo: submissionRepo,
+		redisClient:    redisClient,
+		logger:         logger,
+		config:         cfg,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (r *Reaper) Start(ctx context.Context) {
+	interval := r.config.Worker.ReaperInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	r.logger.Info("Reaper started",
+		zap.Duration("interval", interval),
+		zap.Duration("stale_age", r.staleAge()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Sweep(ctx); err != nil {
+				r.logger.Error("Reaper sweep failed", zap.Error(err))
+			}
+		case <-r.stopChan:
+			r.logger.Info("Reaper stopped")
+			return
+		case <-ctx.Done():
+			r.logger.Info("Reaper context cancelled")
+			return
+		}
+	}
+}
+
+func (r *Reaper) Stop() {
+	close(r.stopChan)
+}
+
+// Sweep runs a single reaper pass
+func (r *Reaper) Sweep(ctx context.Context) error {
+	stuck, err := r.submissionRepo.GetStuckBefore(time.Now().Add(-r.staleAge()), reaperBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to fetch stuck submissions: %w", err)
+	}
+	if len(stuck) == 0 {
+		return nil
+	}
+
+	owned, err := r.ownedSubmissions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read worker ownership: %w", err)
+	}
+
+	queued, err := r.queuedSubmissions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read queue contents: %w", err)
+	}
+
+	for i := range stuck {
+		submission := &stuck[i]
+		if owned[submission.ID] || queued[submission.ID] {
+			continue
+		}
+		r.reap(ctx, submission)
+	}
+
+	return nil
+}
+
+func (r *Reaper) reap(ctx context.Context, submission *domain.Submission) {
+	reapKey := fmt.Sprintf("submission:%d:reaped", submission.ID)
+	reaps, err := r.redisClient.Incr(ctx, reapKey).Result()
+	if err != nil {
+		r.logger.Error("Failed to count reaps", zap.Error(err), zap.Int("submission_id", submission.ID))
+		return
+	}
+	_ = r.redisClient.Expire(ctx, reapKey, 24*time.Hour).Err()
+
+	// The status is set only while the submission is still unjudged, as a worker may
+	// have finished it since it was read
+	if int(reaps) > r.config.Worker.MaxReaps {
+		failed, err := r.submissionRepo.SetStatusIfUnjudged(submission.ID, domain.SubmissionStatusInternalError,
+			"Submission was lost by the judge and could not be recovered")
+		if err != nil {
+			r.logger.Error("Failed to fail orphaned submission", zap.Error(err), zap.Int("submission_id", submission.ID))
+			return
+		}
+		if failed {
+			r.logger.Warn("Failed orphaned submission",
+				zap.Int("submission_id", submission.ID),
+				zap.Int64("reaps", reaps-1),
+			)
+		}
+		return
+	}
+
+	// The worker only picks up pending submissions
+	reset, err := r.submissionRepo.SetStatusIfUnjudged(submission.ID, domain.SubmissionStatusPending, submission.ErrorMessage)
+	if err != nil {
+		r.logger.Error("Failed to reset orphaned submission", zap.Error(err), zap.Int("submission_id", submission.ID))
+		return
+	}
+	if !reset {
+		return
+	}
+
+	if err := r.queue.EnqueueSubmission(ctx, submission.ID, laneForSubmission(submission)); err != nil {
+		r.logger.Error("Failed to re-enqueue orphaned submission", zap.Error(err), zap.Int("submission_id", submission.ID))
+		return
+	}
+
+	r.logger.Warn("Re-enqueued orphaned submission",
+		zap.Int("submission_id", submission.ID),
+		zap.Int64("reaps", reaps),
+		zap.Time("created_at", submission.CreatedAt),
+	)
+}
+
+// ownedSubmissions collects the submissions held by workers with a live heartbeat
+func (r *Reaper) ownedSubmissions(ctx context.Context) (map[int]bool, error) {
+	keys, err := r.redisClient.Keys(ctx, "worker:*:heartbeat").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[int]bool)
+	for _, key := range keys {
+		workerID := strings.TrimSuffix(strings.TrimPrefix(key, "worker:"), ":heartbeat")
+		members, err := r.redisClient.SMembers(ctx, ownedSubmissionsKey(workerID)).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if id, err := strconv.Atoi(member); err == nil {
+				owned[id] = true
+			}
+		}
+	}
+
+	return owned, nil
+}
+
+// queuedSubmissions collects the submissions the queue still accounts for, whether
+// waiting, in flight, backing off or dead-lettered
+func (r *Reaper) queuedSubmissions(ctx context.Context) (map[int]bool, error) {
+	var payloads []string
+	lists := []string{queue.SubmissionProcessingName, queue.SubmissionDeadName}
+	for _, lane := range queue.Lanes {
+		lists = append(lists, queue.LaneKey(lane))
+	}
+	for _, list := range lists {
+		items, err := r.redisClient.LRange(ctx, list, 0, -1).Result()
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, items...)
+	}
+
+	retries, err := r.redisClient.ZRange(ctx, queue.SubmissionRetryName, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	payloads = append(payloads, retries...)
+
+	queued := make(map[int]bool, len(payloads))
+	for _, raw := range payloads {
+		var job queue.SubmissionJob
+		if err := json.Unmarshal([]byte(raw), &job); err == nil {
+			queued[job.SubmissionID] = true
+		}
+	}
+
+	return queued, nil
+}
+
+// laneForSubmission picks the lane a submission would originally have been enqueued on
+func laneForSubmission(submission *domain.Submission) queue.Lane {
+	switch {
+	case submission.RejudgeID != nil:
+		return queue.LaneBulk
+	case submission.IsRunOnly:
+		return queue.LaneRun
+	case submission.IsValidationSubmission:
+		return queue.LaneValidation
+	default:
+		return queue.LaneSubmit
+	}
+}
+
+func (r *Reaper) staleAge() time.Duration {
+	if r.config.Worker.StaleSubmissionAge <= 0 {
+		return 15 * time.Minute
+	}
+	return r.config.Worker.StaleSubmissionAge
+}
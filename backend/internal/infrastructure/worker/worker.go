@@ -3,14 +3,18 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/prabalesh/loco/backend/internal/domain"
-	"github.com/prabalesh/loco/backend/internal/infrastructure/piston"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
+	"github.com/prabalesh/loco/backend/internal/services/checker"
 	"github.com/prabalesh/loco/backend/internal/services/codegen"
+	"github.com/prabalesh/loco/backend/internal/services/stdio"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
 	"github.com/prabalesh/loco/backend/pkg/config"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -25,14 +29,20 @@ type Worker struct {
 	languageRepo          domain.LanguageRepository
 	problemLanguageRepo   domain.ProblemLanguageRepository
 	referenceSolutionRepo domain.ReferenceSolutionRepository
-	pistonService         piston.PistonService
-	boilerplateService    *codegen.BoilerplateService
+	executor              executor.Executor
+	checker               *checker.CheckerService
+	stdio                 *stdio.Runner
+	boilerplateService    domain.BoilerplateService
+	testData              *testdata.TestDataService
 	userProblemStatsRepo  domain.UserProblemStatsRepository
+	rejudgeRepo           domain.RejudgeRepository
+	judgementRepo         domain.SubmissionJudgementRepository
 	logger                *zap.Logger
 	stopChan              chan struct{}
 	redisClient           *redis.Client
 	workerID              string
 	config                *config.Config
+	lanes                 *laneScheduler
 }
 
 func NewWorker(
@@ -43,9 +53,12 @@ func NewWorker(
 	languageRepo domain.LanguageRepository,
 	problemLanguageRepo domain.ProblemLanguageRepository,
 	referenceSolutionRepo domain.ReferenceSolutionRepository,
-	pistonService piston.PistonService,
-	boilerplateService *codegen.BoilerplateService,
+	executor executor.Executor,
+	boilerplateService domain.BoilerplateService,
+	testData *testdata.TestDataService,
 	userProblemStatsRepo domain.UserProblemStatsRepository,
+	rejudgeRepo domain.RejudgeRepository,
+	judgementRepo domain.SubmissionJudgementRepository,
 	logger *zap.Logger,
 	redisClient *redis.Client,
 	cfg *config.Config,
@@ -58,14 +71,20 @@ func NewWorker(
 		languageRepo:          languageRepo,
 		problemLanguageRepo:   problemLanguageRepo,
 		referenceSolutionRepo: referenceSolutionRepo,
-		pistonService:         pistonService,
+		executor:              executor,
+		checker:               checker.NewCheckerService(executor),
+		stdio:                 stdio.NewRunner(executor),
 		boilerplateService:    boilerplateService,
+		testData:              testData,
 		userProblemStatsRepo:  userProblemStatsRepo,
+		rejudgeRepo:           rejudgeRepo,
+		judgementRepo:         judgementRepo,
 		logger:                logger,
 		stopChan:              make(chan struct{}),
 		redisClient:           redisClient,
 		workerID:              generateWorkerID(),
 		config:                cfg,
+		lanes:                 newLaneScheduler(cfg.Worker.LaneWeights),
 	}
 }
 
@@ -77,6 +96,9 @@ func (w *Worker) Start(ctx context.Context) {
 	// Start heartbeat goroutine
 	go w.startHeartbeat(ctx)
 
+	// Start recovery of jobs abandoned by crashed workers
+	go w.startRecovery(ctx)
+
 	// Semaphore to limit concurrent submissions
 	sem := make(chan struct{}, w.config.Worker.MaxConcurrentSubmissions)
 
@@ -91,8 +113,8 @@ func (w *Worker) Start(ctx context.Context) {
 			w.stopHeartbeat()
 			return
 		default:
-			// Dequeue a job (blocking call)
-			job, err := w.queue.DequeueSubmission(ctx)
+			// Dequeue a job (blocking call), trying lanes in weighted fair order
+			job, err := w.queue.DequeueSubmission(ctx, w.lanes.next())
 			if err != nil {
 				w.logger.Error("Failed to dequeue job", zap.Error(err))
 				time.Sleep(1 * time.Second) // Back off on error
@@ -108,10 +130,10 @@ func (w *Worker) Start(ctx context.Context) {
 			sem <- struct{}{}
 
 			// Process the job in a new goroutine
-			go func(submissionID int) {
+			go func(job *queue.SubmissionJob) {
 				defer func() { <-sem }()
-				w.processSubmission(ctx, submissionID)
-			}(job.SubmissionID)
+				w.handleJob(ctx, job)
+			}(job)
 		}
 	}
 }
@@ -127,7 +149,7 @@ var HeartbeatInterval = 10 * time.Second
 func (w *Worker) startHeartbeat(ctx context.Context) {
 	ticker := time.NewTicker(HeartbeatInterval)
 	defer ticker.Stop()
-	key := "worker:" + w.workerID + ":heartbeat"
+	key := heartbeatKey(w.workerID)
 	for {
 		select {
 		case <-ticker.C:
@@ -135,6 +157,8 @@ func (w *Worker) startHeartbeat(ctx context.Context) {
 			if err := w.redisClient.Set(ctx, key, "alive", 2*HeartbeatInterval).Err(); err != nil {
 				w.logger.Error("Failed to set heartbeat", zap.Error(err))
 			}
+			// Submissions owned by this worker live exactly as long as its heartbeat
+			_ = w.redisClient.Expire(ctx, ownedSubmissionsKey(w.workerID), 2*HeartbeatInterval).Err()
 		case <-w.stopChan:
 			return
 		case <-ctx.Done():
@@ -144,16 +168,96 @@ func (w *Worker) startHeartbeat(ctx context.Context) {
 }
 
 func (w *Worker) stopHeartbeat() {
-	key := "worker:" + w.workerID + ":heartbeat"
+	key := heartbeatKey(w.workerID)
 	_ = w.redisClient.Del(context.Background(), key)
 }
 
+func heartbeatKey(workerID string) string {
+	return "worker:" + workerID + ":heartbeat"
+}
+
+// ownedSubmissionsKey holds the IDs of the submissions a worker is currently judging
+func ownedSubmissionsKey(workerID string) string {
+	return "worker:" + workerID + ":submissions"
+}
+
 func generateWorkerID() string {
 	return fmt.Sprintf("%d", time.Now().UnixNano())
 }
 
-// processSubmission processes a single submission
-func (w *Worker) processSubmission(ctx context.Context, submissionID int) {
+// recovery of abandoned jobs
+var RecoveryInterval = 1 * time.Minute
+
+func (w *Worker) startRecovery(ctx context.Context) {
+	timeout := w.config.Worker.ProcessingTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Minute
+	}
+
+	ticker := time.NewTicker(RecoveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			recovered, err := w.queue.RequeueStaleSubmissions(ctx, timeout)
+			if err != nil {
+				w.logger.Error("Failed to requeue stale submissions", zap.Error(err))
+			} else if recovered > 0 {
+				w.logger.Warn("Requeued submissions abandoned by other workers", zap.Int("count", recovered))
+			}
+		case <-w.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleJob processes a dequeued job and acks or nacks it. A job that keeps failing is
+// dead-lettered and its submission is marked as an internal error so it doesn't sit in
+// Pending forever.
+func (w *Worker) handleJob(ctx context.Context, job *queue.SubmissionJob) {
+	// Use a fresh context so a shutdown mid-evaluation doesn't leave the job un-acked
+	ackCtx := context.Background()
+
+	// Record ownership so the reaper leaves this submission alone while we're alive
+	ownedKey := ownedSubmissionsKey(w.workerID)
+	_ = w.redisClient.SAdd(ackCtx, ownedKey, job.SubmissionID).Err()
+	_ = w.redisClient.Expire(ackCtx, ownedKey, 2*HeartbeatInterval).Err()
+	defer w.redisClient.SRem(ackCtx, ownedKey, job.SubmissionID)
+
+	procErr := w.processSubmission(ctx, job.SubmissionID)
+	if procErr == nil {
+		if err := w.queue.AckSubmission(ackCtx, job); err != nil {
+			w.logger.Error("Failed to ack submission job", zap.Error(err), zap.Int("submission_id", job.SubmissionID))
+		}
+		return
+	}
+
+	w.logger.Warn("Submission processing failed",
+		zap.Error(procErr),
+		zap.Int("submission_id", job.SubmissionID),
+		zap.Int("attempts", job.Attempts+1),
+	)
+
+	deadLettered, err := w.queue.NackSubmission(ackCtx, job, procErr)
+	if err != nil {
+		w.logger.Error("Failed to nack submission job", zap.Error(err), zap.Int("submission_id", job.SubmissionID))
+		return
+	}
+
+	if deadLettered {
+		submission, err := w.submissionRepo.GetByID(job.SubmissionID)
+		if err == nil && submission.Status == domain.SubmissionStatusPending {
+			w.updateSubmissionError(submission, domain.SubmissionStatusInternalError,
+				fmt.Sprintf("Judging failed after %d attempts", job.Attempts+1))
+		}
+	}
+}
+
+// processSubmission processes a single submission. A returned error means the
+// submission could not be judged and should be retried.
+func (w *Worker) processSubmission(ctx context.Context, submissionID int) error {
 	w.logger.Info("Processing submission",
 		zap.Int("submission_id", submissionID),
 	)
@@ -165,7 +269,7 @@ func (w *Worker) processSubmission(ctx context.Context, submissionID int) {
 			zap.Error(err),
 			zap.Int("submission_id", submissionID),
 		)
-		return
+		return fmt.Errorf("failed to fetch submission: %w", err)
 	}
 
 	// Check if already processed
@@ -174,8 +278,9 @@ func (w *Worker) processSubmission(ctx context.Context, submissionID int) {
 			zap.Int("submission_id", submissionID),
 			zap.String("status", string(submission.Status)),
 		)
-		return
+		return nil
 	}
+	w.judgement(submission)
 
 	// Fetch problem and language
 	problem, err := w.problemRepo.GetByID(submission.ProblemID)
@@ -184,8 +289,7 @@ func (w *Worker) processSubmission(ctx context.Context, submissionID int) {
 			zap.Error(err),
 			zap.Int("submission_id", submissionID),
 		)
-		w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, "Problem not found")
-		return
+		return w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, "Problem not found")
 	}
 
 	language, err := w.languageRepo.GetByID(submission.LanguageID)
@@ -194,16 +298,20 @@ func (w *Worker) processSubmission(ctx context.Context, submissionID int) {
 			zap.Error(err),
 			zap.Int("submission_id", submissionID),
 		)
-		w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, "Language not found")
-		return
+		return w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, "Language not found")
 	}
 
 	// Evaluate submission (always run all test cases for submissions)
-	w.evaluateSubmission(submission, problem, language, false)
+	return w.evaluateSubmission(submission, problem, language, false)
 }
 
-// evaluateSubmission executes the submission against test cases in parallel batches of 8
-func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *domain.Problem, language *domain.Language, runOnlyPublicTests bool) {
+// errExecutorUnavailable marks failures of the execution backend itself, as opposed
+// to failures of the submitted program
+var errExecutorUnavailable = errors.New("executor unavailable")
+
+// evaluateSubmission executes the submission against test cases in parallel batches.
+// It only returns an error for transient failures that are worth retrying.
+func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *domain.Problem, language *domain.Language, runOnlyPublicTests bool) error {
 	var testCases []domain.TestCase
 	var err error
 
@@ -226,8 +334,7 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 		}
 		if err != nil {
 			w.logger.Error("Failed to fetch test cases", zap.Error(err), zap.Int("submission_id", submission.ID))
-			w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, "Failed to fetch test cases")
-			return
+			return fmt.Errorf("failed to fetch test cases: %w", err)
 		}
 
 		// Cache test cases for 5 minutes
@@ -240,12 +347,29 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 
 	submission.TotalTestCases = len(testCases)
 
+	// Stdio and interactive problems run the submitted program as it is, without a harness
+	if problem.IsWholeProgram() {
+		loaded, err := w.testData.Load(context.Background(), testCases)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errExecutorUnavailable, err)
+		}
+		return w.evaluateStdioSubmission(submission, problem, language, loaded)
+	}
+
 	// 1. Get test harness template
 	harnessTemplate, err := w.boilerplateService.GetTestHarnessTemplate(submission.ProblemID, submission.LanguageID)
 	if err != nil {
 		w.logger.Error("Failed to get harness template", zap.Error(err), zap.Int("submission_id", submission.ID))
-		w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, "Harness template not found")
-		return
+		return w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, "Harness template not found")
+	}
+
+	// The harness prints the nonce with its result, unless it was stored before it could
+	var nonce string
+	if version, err := w.boilerplateService.GetHarnessVersion(submission.ProblemID, submission.LanguageID); err == nil {
+		w.judgement(submission).HarnessVersion = version
+		if codegen.HarnessTakesNonce(version) {
+			nonce = codegen.NewHarnessNonce()
+		}
 	}
 
 	// 2. Inject user code
@@ -265,16 +389,15 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 		batches = append(batches, testCases[i:end])
 	}
 
-	// 4. Parallel execution with errgroup
+	// 4. Parallel execution with errgroup. Within a batch every test runs in a process
+	// of its own, so the executor measures the time and memory of each test apart from
+	// the others.
 	g, gCtx := errgroup.WithContext(context.Background())
 	batchResults := make([]struct {
-		TestResults []domain.TestCaseResult
-		Verdict     string
-		Memory      int
-		Runtime     int
-		Error       string
-		ExitCode    int
-		Output      string
+		TestResults   []domain.TestCaseResult
+		Error         string
+		CompileFailed bool
+		CheckerError  string
 	}, len(batches))
 
 	for i, batch := range batches {
@@ -287,82 +410,81 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 			default:
 			}
 
-			// Prepare batch JSON
-			type TestInput struct {
-				Input    interface{} `json:"input"`
-				Expected interface{} `json:"expected"`
+			// Large values are read from the test-data store only now, batch by batch;
+			// the store is part of the execution backend as far as retries go
+			loaded, err := w.testData.Load(gCtx, batch)
+			if err != nil {
+				return fmt.Errorf("batch %d: %w: %v", i, errExecutorUnavailable, err)
 			}
-			inputs := make([]TestInput, len(batch))
-			for j, tc := range batch {
-				var inputData interface{}
-				_ = json.Unmarshal([]byte(tc.Input), &inputData)
-				var expectedData interface{}
-				_ = json.Unmarshal([]byte(tc.ExpectedOutput), &expectedData)
-				inputs[j] = TestInput{Input: inputData, Expected: expectedData}
+
+			// Prepare the harness input of every test
+			req := &executor.BatchRequest{
+				Request: executor.Request{
+					ProblemID:    problem.ID,
+					SubmissionID: &submission.ID,
+					Language:     language.Slug,
+					Version:      language.Version,
+					Code:         fullCode,
+				},
+				Runs: make([]executor.Run, len(loaded)),
+			}
+			for j := range loaded {
+				testInputJSON, limits, err := codegen.BuildHarnessInput(loaded[j:j+1], problem, language, nonce)
+				if err != nil {
+					return fmt.Errorf("failed to encode test %d: %w", i*batchSize+j+1, err)
+				}
+				req.Runs[j] = executor.Run{
+					Stdin:         testInputJSON,
+					StdinKeys:     testdata.Keys(batch[j : j+1]),
+					TimeLimitMs:   limits.TimeMs,
+					MemoryLimitMb: limits.MemoryMb,
+				}
 			}
-			testInputJSON, _ := json.Marshal(inputs)
 
-			// Execute batch on Piston
-			fmt.Printf("\n--- Piston Execution Request (Worker Batch %d) ---\n", i)
-			fmt.Printf("Stdin (batch size %d): %s\n", len(batch), string(testInputJSON))
+			// Execute batch
+			fmt.Printf("\n--- Execution Request (Worker Batch %d) ---\n", i)
+			fmt.Printf("Runs: %d\n", len(req.Runs))
 			fmt.Printf("Language: %s, Version: %s\n", language.Slug, language.Version)
-			fmt.Printf("Memory Limit: %d MB\n", problem.MemoryLimit)
 			fmt.Printf("--------------------------------------------------\n\n")
 
-			res, err := w.pistonService.Execute(problem.ID, &submission.ID, language.Slug, language.Version, fullCode, string(testInputJSON))
-
+			results, err := executor.ExecuteEach(context.Background(), w.executor, req)
 			if err != nil {
-				return fmt.Errorf("batch %d failed: %w", i, err)
+				return fmt.Errorf("batch %d failed: %w: %v", i, errExecutorUnavailable, err)
 			}
-
-			// Store raw output for fallback/compilation check
-			batchResults[i].Output = res.Output
-			batchResults[i].Error = res.Error
-			batchResults[i].ExitCode = res.ExitCode
-
-			// Parse detailed results
-			type HarnessTestResult struct {
-				domain.TestCaseResult
-				Passed *bool  `json:"passed"`
-				Actual string `json:"actual"`
+			if len(results) > 0 && results[0].CompileFailed {
+				batchResults[i].CompileFailed = true
+				batchResults[i].Error = executor.TruncateOutput(results[0].Error, executor.MaxStoredOutputBytes)
+				return fmt.Errorf("compilation error in batch %d", i)
 			}
-			var resultObj struct {
-				TestResults []HarnessTestResult `json:"test_results"`
-				Verdict     string              `json:"verdict"`
-				Memory      int                 `json:"memory"`
-				Runtime     int                 `json:"runtime"`
+
+			// The harness reports the verdict of each test, the executor what it cost
+			batchResults[i].TestResults = make([]domain.TestCaseResult, len(results))
+			for j, res := range results {
+				row := harnessTestResult(res, nonce)
+				row.TestID = i*batchSize + j + 1
+				row.TimeMS, row.MemoryKB = language.NormaliseUsage(res.Runtime, res.Memory)
+				batchResults[i].TestResults[j] = row
 			}
 
-			if err := json.Unmarshal([]byte(res.Output), &resultObj); err == nil && len(resultObj.TestResults) > 0 {
-				batchResults[i].TestResults = make([]domain.TestCaseResult, len(resultObj.TestResults))
-				for j, tr := range resultObj.TestResults {
-					row := tr.TestCaseResult
-					if row.Status == "" && tr.Passed != nil {
-						if *tr.Passed {
-							row.Status = "passed"
-						} else {
-							row.Status = "failed"
-						}
+			// The harness passes every answer of a CUSTOM problem; the checker judges them
+			if problem.UsesChecker() {
+				if err := w.checker.CheckResults(context.Background(), problem, loaded, batchResults[i].TestResults); err != nil {
+					if !errors.Is(err, checker.ErrCheckerFailed) {
+						return fmt.Errorf("batch %d checker failed: %w: %v", i, errExecutorUnavailable, err)
 					}
-					if row.ActualOutput == "" && tr.Actual != "" {
-						row.ActualOutput = tr.Actual
-					}
-					batchResults[i].TestResults[j] = row
+					batchResults[i].CheckerError = err.Error()
+					return fmt.Errorf("batch %d: %w", i, err)
 				}
-				batchResults[i].Verdict = resultObj.Verdict
-				batchResults[i].Memory = resultObj.Memory
-				batchResults[i].Runtime = resultObj.Runtime
+			}
 
-				// Short-circuit: if this batch had a failure, signal to stop other batches
-				if batchResults[i].Verdict != "ACCEPTED" && batchResults[i].Verdict != "" {
-					return fmt.Errorf("short-circuit: batch %d failed with %s", i, batchResults[i].Verdict)
-				}
-			} else {
-				// Compilation or terminal runtime error
-				if res.ExitCode != 0 && res.Error != "" && !strings.Contains(res.Output, "verdict") {
-					return fmt.Errorf("compilation error in batch %d", i)
+			// Short-circuit: if a test of this batch failed, signal to stop other batches.
+			// Subtasks are scored on every test, so all of them have to run.
+			if !problem.HasSubtasks() {
+				for _, row := range batchResults[i].TestResults {
+					if row.Status != domain.TestStatusPassed {
+						return fmt.Errorf("short-circuit: batch %d failed on test %d with %s", i, row.TestID, row.Status)
+					}
 				}
-				return fmt.Errorf("unexpected output in batch %d", i)
 			}
 
 			return nil
@@ -371,7 +493,10 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 
 	// Wait for all batches or first failure
 	if err := g.Wait(); err != nil {
-		w.logger.Warn("Batch execution finished with error (short-circuit or Piston error)", zap.Error(err))
+		if errors.Is(err, errExecutorUnavailable) {
+			return err
+		}
+		w.logger.Warn("Batch execution finished with error (short-circuit)", zap.Error(err))
 	}
 
 	// 5. Aggregate results from all batches
@@ -379,22 +504,15 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 	finalStatus := domain.SubmissionStatusAccepted
 	passCount := 0
 	errorMessage := ""
-	maxMemory := 0
-	maxRuntime := 0
 
 	for i, res := range batchResults {
-		// If a batch didn't run or returned a critical error (compilation)
-		if res.ExitCode != 0 && res.Error != "" && !strings.Contains(res.Output, "verdict") {
-			w.updateSubmissionError(submission, domain.SubmissionStatusCompilationError, res.Error)
-			return
+		// If a batch returned a critical error (compilation)
+		if res.CompileFailed {
+			return w.updateSubmissionError(submission, domain.SubmissionStatusCompilationError, res.Error)
 		}
-
-		if len(res.TestResults) == 0 && res.Output != "" {
-			// Terminal error in batch execution (crashed before emitting JSON)
-			if finalStatus == domain.SubmissionStatusAccepted {
-				finalStatus = domain.SubmissionStatusRuntimeError
-				errorMessage = res.Error + "\n" + res.Output
-			}
+		// A broken checker is the problem's fault, so don't charge it to the submission
+		if res.CheckerError != "" {
+			return w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, res.CheckerError)
 		}
 
 		for j := range res.TestResults {
@@ -406,31 +524,16 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 				tr.IsSample = testCases[globalIdx].IsSample
 				tr.Input = testCases[globalIdx].Input
 				tr.ExpectedOutput = testCases[globalIdx].ExpectedOutput
+				tr.Subtask = testCases[globalIdx].Subtask
 			}
 
-			if tr.Status == "passed" {
+			if tr.Status == domain.TestStatusPassed {
 				passCount++
 			} else if finalStatus == domain.SubmissionStatusAccepted {
-				switch tr.Status {
-				case "timeout":
-					finalStatus = domain.SubmissionStatusTimeLimitExceeded
-				case "runtime_error":
-					finalStatus = domain.SubmissionStatusRuntimeError
-					errorMessage = tr.Error
-				default:
-					finalStatus = domain.SubmissionStatusWrongAnswer
-					errorMessage = fmt.Sprintf("Failed on test %d", tr.TestID)
-				}
+				finalStatus, errorMessage = tr.Verdict(problem)
 			}
 			finalTestResults = append(finalTestResults, tr)
 		}
-
-		if res.Memory > maxMemory {
-			maxMemory = res.Memory
-		}
-		if res.Runtime > maxRuntime {
-			maxRuntime = res.Runtime
-		}
 	}
 
 	// 6. Handle cases where not all batches executed (short-circuit)
@@ -440,32 +543,139 @@ func (w *Worker) evaluateSubmission(submission *domain.Submission, problem *doma
 		errorMessage = "Execution short-circuited unexpectedly"
 	}
 
-	// Metrics fallback
-	if maxRuntime == 0 && finalStatus == domain.SubmissionStatusAccepted {
-		maxRuntime = 1
+	submission.Runtime, submission.Memory = peakUsage(finalTestResults)
+
+	// 7. Update database and stats
+	if err := w.finishSubmission(submission, problem, finalStatus, errorMessage, finalTestResults, passCount); err != nil {
+		return err
+	}
+
+	w.logger.Info("Submission processed via parallel batches",
+		zap.Int("submission_id", submission.ID),
+		zap.Int("batches", len(batches)),
+		zap.Int("passed", passCount),
+		zap.String("status", string(finalStatus)),
+	)
+
+	return nil
+}
+
+// harnessTestResult reads the result of the single test a harness run was given. A
+// run stopped by a limit or a signal, or one that crashed before reporting, fails
+// the test with the reason.
+func harnessTestResult(res *executor.Result, nonce string) domain.TestCaseResult {
+	type HarnessTestResult struct {
+		domain.TestCaseResult
+		Passed *bool  `json:"passed"`
+		Actual string `json:"actual"`
+	}
+	var resultObj struct {
+		TestResults []HarnessTestResult `json:"test_results"`
+	}
+
+	// Whatever the user's code printed comes before the harness result
+	_, result := codegen.SplitHarnessOutput(res.Output, nonce)
+	if err := json.Unmarshal([]byte(result), &resultObj); err == nil && len(resultObj.TestResults) > 0 {
+		tr := resultObj.TestResults[0]
+		row := tr.TestCaseResult
+		if row.Status == "" && tr.Passed != nil {
+			if *tr.Passed {
+				row.Status = domain.TestStatusPassed
+			} else {
+				row.Status = domain.TestStatusFailed
+			}
+		}
+		if row.ActualOutput == "" && tr.Actual != "" {
+			row.ActualOutput = tr.Actual
+		}
+		row.Stdout = executor.TruncateOutput(row.Stdout, executor.MaxStoredTestOutputBytes)
+		return row
+	}
+
+	if res.Termination != executor.TerminationNone {
+		return domain.TestCaseResult{
+			Status: res.Termination.TestStatus(),
+			Error:  executor.TruncateOutput(res.Error, executor.MaxStoredOutputBytes),
+		}
+	}
+	return domain.TestCaseResult{
+		Status: domain.TestStatusRuntimeError,
+		Error:  executor.TruncateOutput(strings.TrimSpace(res.Error+"\n"+res.Output), executor.MaxStoredOutputBytes),
 	}
+}
 
-	submission.Memory = maxMemory
-	submission.Runtime = maxRuntime
+// peakUsage is the runtime of the slowest test and the memory of the hungriest one
+func peakUsage(results []domain.TestCaseResult) (runtimeMs, memoryKB int) {
+	for i := range results {
+		runtimeMs = max(runtimeMs, results[i].TimeMS)
+		memoryKB = max(memoryKB, results[i].MemoryKB)
+	}
+	return runtimeMs, memoryKB
+}
+
+// finishSubmission scores a judged submission, stores its results and updates the
+// stats or the validation status it counts towards. A rejudged submission was
+// counted already, so updateSubmissionResult recounts its stats instead.
+func (w *Worker) finishSubmission(submission *domain.Submission, problem *domain.Problem, status domain.SubmissionStatus, errorMessage string, results []domain.TestCaseResult, passCount int) error {
 	submission.PassedTestCases = passCount
-	submission.TestCaseResults = finalTestResults
-	submission.ExecutionMetadata, _ = json.Marshal(finalTestResults)
+	submission.TestCaseResults = results
+	submission.Score, submission.SubtaskResults = problem.Score(status, results)
+	submission.ExecutionMetadata, _ = json.Marshal(results)
 
-	// 7. Update database and stats
-	w.updateSubmissionResult(submission, finalStatus, errorMessage)
+	rejudged := submission.RejudgeID != nil
+	if err := w.updateSubmissionResult(submission, status, errorMessage); err != nil {
+		return err
+	}
 
-	if submission.IsValidationSubmission {
-		w.updateValidationStatus(submission, finalStatus, errorMessage, passCount, len(testCases))
-	} else {
-		w.updateProblemAndUserStats(submission, finalStatus)
+	switch {
+	case submission.IsValidationSubmission:
+		w.updateValidationStatus(submission, status, errorMessage, passCount, submission.TotalTestCases)
+	case !rejudged:
+		w.updateProblemAndUserStats(submission, status)
 	}
+	return nil
+}
 
-	w.logger.Info("Submission processed via parallel batches",
+// evaluateStdioSubmission judges a whole program on each test's raw input. Like
+// evaluateSubmission it only returns an error for failures worth retrying.
+func (w *Worker) evaluateStdioSubmission(submission *domain.Submission, problem *domain.Problem, language *domain.Language, testCases []domain.TestCase) error {
+	res, err := w.stdio.Run(context.Background(), problem, stdio.Program{
+		SubmissionID: &submission.ID,
+		LanguageSlug: language.Slug,
+		Version:      language.Version,
+		Code:         submission.Code,
+		Language:     language,
+	}, testCases)
+	if err != nil {
+		// A broken checker or interactor is the problem's fault, so don't charge it to
+		// the submission; neither is retrying going to help an executor that can't run
+		// interactive problems
+		if errors.Is(err, checker.ErrCheckerFailed) || errors.Is(err, executor.ErrInteractiveUnsupported) {
+			return w.updateSubmissionError(submission, domain.SubmissionStatusInternalError, err.Error())
+		}
+		return fmt.Errorf("%w: %v", errExecutorUnavailable, err)
+	}
+	if res.CompileFailed {
+		return w.updateSubmissionError(submission, domain.SubmissionStatusCompilationError, res.CompileError)
+	}
+
+	finalStatus, errorMessage, passCount := res.Verdict(problem, len(testCases))
+	for i := range res.TestResults {
+		tr := &res.TestResults[i]
+		tr.TimeMS, tr.MemoryKB = language.NormaliseUsage(tr.TimeMS, tr.MemoryKB)
+	}
+	submission.Runtime, submission.Memory = peakUsage(res.TestResults)
+
+	if err := w.finishSubmission(submission, problem, finalStatus, errorMessage, res.TestResults, passCount); err != nil {
+		return err
+	}
+
+	w.logger.Info("Stdio submission processed",
 		zap.Int("submission_id", submission.ID),
-		zap.Int("batches", len(batches)),
 		zap.Int("passed", passCount),
 		zap.String("status", string(finalStatus)),
 	)
+	return nil
 }
 
 func (w *Worker) updateValidationStatus(submission *domain.Submission, status domain.SubmissionStatus, errorMsg string, passCount, totalCount int) {
@@ -530,12 +740,15 @@ func (w *Worker) updateProblemAndUserStats(submission *domain.Submission, finalS
 		ProblemID: submission.ProblemID,
 		Attempts:  1,
 		Status:    "attempted",
+		BestScore: submission.Score,
 		UpdatedAt: now,
 	}
 
 	if isAccepted {
 		stats.Status = "solved"
 		stats.FirstSolvedAt = &now
+	}
+	if isAccepted || submission.Score > 0 {
 		stats.BestSubmissionID = &submission.ID
 	}
 
@@ -557,7 +770,7 @@ func (w *Worker) updateProblemAndUserStats(submission *domain.Submission, finalS
 	}
 }
 
-func (w *Worker) updateSubmissionResult(submission *domain.Submission, status domain.SubmissionStatus, errorMsg string) {
+func (w *Worker) updateSubmissionResult(submission *domain.Submission, status domain.SubmissionStatus, errorMsg string) error {
 	submission.Status = status
 	submission.ErrorMessage = errorMsg
 
@@ -587,14 +800,109 @@ func (w *Worker) updateSubmissionResult(submission *domain.Submission, status do
 	}
 	fmt.Println("========================================")
 
+	if err := w.recordJudgement(submission); err != nil {
+		return err
+	}
+
+	rejudgeID := submission.RejudgeID
+	submission.RejudgeID = nil
 	if err := w.submissionRepo.Update(submission); err != nil {
+		submission.RejudgeID = rejudgeID
 		w.logger.Error("Failed to update submission status",
 			zap.Error(err),
 			zap.Int("submission_id", submission.ID),
 		)
+		return fmt.Errorf("failed to update submission: %w", err)
+	}
+
+	if rejudgeID != nil {
+		w.finishRejudge(*rejudgeID, submission)
+	}
+	return nil
+}
+
+// judgement returns the attempt at judging the submission in progress, starting one
+// if there is none yet
+func (w *Worker) judgement(submission *domain.Submission) *domain.SubmissionJudgement {
+	if submission.Judgement == nil || submission.Judgement.ID != 0 {
+		driver := w.config.Executor.Driver
+		if driver == "" {
+			driver = executor.DriverPiston
+		}
+		submission.Judgement = &domain.SubmissionJudgement{
+			WorkerID:  w.workerID,
+			Executor:  driver,
+			QueuedAt:  submission.QueuedAt,
+			StartedAt: time.Now(),
+		}
+	}
+	return submission.Judgement
+}
+
+// recordJudgement saves the finished attempt at judging the submission and makes
+// it the submission's current judgement
+func (w *Worker) recordJudgement(submission *domain.Submission) error {
+	judgement := w.judgement(submission)
+	judgement.RejudgeID = submission.RejudgeID
+	judgement.Finish(submission, time.Now())
+	if err := w.judgementRepo.Create(judgement); err != nil {
+		w.logger.Error("Failed to record judgement",
+			zap.Error(err),
+			zap.Int("submission_id", submission.ID),
+		)
+		return fmt.Errorf("failed to record judgement: %w", err)
+	}
+	submission.JudgementID = &judgement.ID
+	return nil
+}
+
+// finishRejudge records the new verdict of a rejudged submission and, as the old one
+// was counted already, recounts the problem's acceptance and the user's stats on the
+// problem from scratch and has their achievements checked again
+func (w *Worker) finishRejudge(rejudgeID int, submission *domain.Submission) {
+	if err := w.rejudgeRepo.RecordResult(rejudgeID, submission); err != nil {
+		w.logger.Error("Failed to record rejudge result",
+			zap.Error(err),
+			zap.Int("rejudge_id", rejudgeID),
+			zap.Int("submission_id", submission.ID),
+		)
+	}
+
+	if err := w.problemRepo.RecountStats(submission.ProblemID); err != nil {
+		w.logger.Error("Failed to recount problem stats",
+			zap.Error(err),
+			zap.Int("problem_id", submission.ProblemID),
+		)
+	}
+
+	verdicts, err := w.submissionRepo.ListVerdictsByUserProblem(submission.UserID, submission.ProblemID)
+	if err != nil {
+		w.logger.Error("Failed to list submissions for user stats",
+			zap.Error(err),
+			zap.Int("user_id", submission.UserID),
+			zap.Int("problem_id", submission.ProblemID),
+		)
+	} else {
+		stats := domain.RecountUserProblemStats(submission.UserID, submission.ProblemID, verdicts)
+		if err := w.userProblemStatsRepo.Replace(stats); err != nil {
+			w.logger.Error("Failed to replace user problem stats",
+				zap.Error(err),
+				zap.Int("user_id", submission.UserID),
+				zap.Int("problem_id", submission.ProblemID),
+			)
+		}
+	}
+
+	if err := w.queue.EnqueueAchievementRecheck(context.Background(), submission.ID); err != nil {
+		w.logger.Error("Failed to enqueue achievement recheck",
+			zap.Error(err),
+			zap.Int("submission_id", submission.ID),
+		)
 	}
 }
 
-func (w *Worker) updateSubmissionError(submission *domain.Submission, status domain.SubmissionStatus, errorMsg string) {
-	w.updateSubmissionResult(submission, status, errorMsg)
+func (w *Worker) updateSubmissionError(submission *domain.Submission, status domain.SubmissionStatus, errorMsg string) error {
+	submission.Score = 0
+	submission.SubtaskResults = nil
+	return w.updateSubmissionResult(submission, status, errorMsg)
 }
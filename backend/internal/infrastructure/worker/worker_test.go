@@ -14,19 +14,43 @@ import (
 
 type mockQueue struct{}
 
-func (m *mockQueue) EnqueueSubmission(ctx context.Context, submissionID int) error {
+func (m *mockQueue) EnqueueSubmission(ctx context.Context, submissionID int, lane queue.Lane) error {
 	return nil
 }
 
-func (m *mockQueue) DequeueSubmission(ctx context.Context) (*queue.SubmissionJob, error) {
+func (m *mockQueue) DequeueSubmission(ctx context.Context, lanes []queue.Lane) (*queue.SubmissionJob, error) {
 	<-ctx.Done()
 	return nil, nil
 }
 
+func (m *mockQueue) AckSubmission(ctx context.Context, job *queue.SubmissionJob) error {
+	return nil
+}
+
+func (m *mockQueue) NackSubmission(ctx context.Context, job *queue.SubmissionJob, cause error) (bool, error) {
+	return false, nil
+}
+
+func (m *mockQueue) RequeueStaleSubmissions(ctx context.Context, timeout time.Duration) (int, error) {
+	return 0, nil
+}
+
+func (m *mockQueue) ListDeadSubmissions(ctx context.Context, offset, limit int) ([]queue.SubmissionJob, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *mockQueue) RemoveDeadSubmission(ctx context.Context, submissionID int) (*queue.SubmissionJob, error) {
+	return nil, queue.ErrJobNotFound
+}
+
 func (m *mockQueue) EnqueueAchievement(ctx context.Context, submissionID int) error {
 	return nil
 }
 
+func (m *mockQueue) EnqueueAchievementRecheck(ctx context.Context, submissionID int) error {
+	return nil
+}
+
 func (m *mockQueue) DequeueAchievement(ctx context.Context) (*queue.AchievementJob, error) {
 	<-ctx.Done()
 	return nil, nil
@@ -64,7 +88,10 @@ func TestWorkerHeartbeat(t *testing.T) {
 		nil,          // referenceSolutionRepo
 		nil,          // pistonService
 		nil,          // boilerplateService
+		nil,          // testData
 		nil,          // userStatsRepo
+		nil,          // rejudgeRepo
+		nil,          // judgementRepo
 		logger,
 		rdb,
 		&config.Config{
@@ -6,6 +6,7 @@ import (
 
 	"github.com/prabalesh/loco/backend/internal/domain"
 	"github.com/prabalesh/loco/backend/pkg/database"
+	"gorm.io/gorm"
 )
 
 type achievementRepository struct {
@@ -67,6 +68,40 @@ func (r *achievementRepository) HasUnlocked(userID int, achievementID int) (bool
 	return count > 0, nil
 }
 
+// Revoke deletes the unlock and takes its XP off the user in the same transaction, so
+// an achievement unlocked or revoked meanwhile can't be counted twice or lost
+func (r *achievementRepository) Revoke(userID int, achievement *domain.Achievement) (bool, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	revoked := false
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND achievement_id = ?", userID, achievement.ID).
+			Delete(&domain.UserAchievement{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to revoke achievement: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		var xp int
+		if err := tx.Raw("UPDATE users SET xp = GREATEST(xp - ?, 0) WHERE id = ? RETURNING xp", achievement.XPReward, userID).
+			Scan(&xp).Error; err != nil {
+			return fmt.Errorf("failed to take back XP: %w", err)
+		}
+		if err := tx.Model(&domain.User{}).Where("id = ?", userID).Update("level", domain.LevelForXP(xp)).Error; err != nil {
+			return fmt.Errorf("failed to update level: %w", err)
+		}
+		revoked = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
 func (r *achievementRepository) Create(achievement *domain.Achievement) error {
 	return r.db.DB.Create(achievement).Error
 }
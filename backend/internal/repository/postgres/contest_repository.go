@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/pkg/database"
+	"gorm.io/gorm"
+)
+
+type contestRepository struct {
+	db *database.Database
+}
+
+func NewContestRepository(db *database.Database) domain.ContestRepository {
+	return &contestRepository{db: db}
+}
+
+// withProblems loads a contest's problems in their order, with the problem titles
+// and the subtasks they are scored by
+func withProblems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Problems", func(db *gorm.DB) *gorm.DB {
+		return db.Order(`"order" asc, label asc`)
+	}).Preload("Problems.Problem", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "title", "slug", "difficulty", "subtasks")
+	})
+}
+
+func (r *contestRepository) Create(contest *domain.Contest) error {
+	ctx, cancel := database.WithMediumTimeout()
+	defer cancel()
+
+	if err := r.db.DB.WithContext(ctx).Create(contest).Error; err != nil {
+		return fmt.Errorf("failed to create contest: %w", err)
+	}
+	return nil
+}
+
+func (r *contestRepository) Update(contest *domain.Contest) error {
+	ctx, cancel := database.WithMediumTimeout()
+	defer cancel()
+
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Problems", "CreatedAt").Save(contest).Error; err != nil {
+			return fmt.Errorf("failed to update contest: %w", err)
+		}
+		if err := tx.Where("contest_id = ?", contest.ID).Delete(&domain.ContestProblem{}).Error; err != nil {
+			return fmt.Errorf("failed to clear contest problems: %w", err)
+		}
+		if len(contest.Problems) == 0 {
+			return nil
+		}
+		for i := range contest.Problems {
+			contest.Problems[i].ID = 0
+			contest.Problems[i].ContestID = contest.ID
+		}
+		if err := tx.Omit("Problem").Create(&contest.Problems).Error; err != nil {
+			return fmt.Errorf("failed to save contest problems: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *contestRepository) Delete(id int) error {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("contest_id = ?", id).Delete(&domain.ContestProblem{}).Error; err != nil {
+			return fmt.Errorf("failed to delete contest problems: %w", err)
+		}
+		if err := tx.Where("contest_id = ?", id).Delete(&domain.ContestRegistration{}).Error; err != nil {
+			return fmt.Errorf("failed to delete contest registrations: %w", err)
+		}
+		if err := tx.Delete(&domain.Contest{}, id).Error; err != nil {
+			return fmt.Errorf("failed to delete contest: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *contestRepository) GetByID(id int) (*domain.Contest, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var contest domain.Contest
+	if err := withProblems(r.db.DB.WithContext(ctx)).First(&contest, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get contest: %w", err)
+	}
+	return &contest, nil
+}
+
+func (r *contestRepository) GetBySlug(slug string) (*domain.Contest, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var contest domain.Contest
+	if err := withProblems(r.db.DB.WithContext(ctx)).Where("slug = ?", slug).First(&contest).Error; err != nil {
+		return nil, fmt.Errorf("failed to get contest by slug: %w", err)
+	}
+	return &contest, nil
+}
+
+func (r *contestRepository) SlugExists(slug string, excludeID int) (bool, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var count int64
+	query := r.db.DB.WithContext(ctx).Model(&domain.Contest{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check slug: %w", err)
+	}
+	return count > 0, nil
+}
+
+// List returns the contests with the given visibility, or all of them if it is
+// empty, latest first
+func (r *contestRepository) List(visibility string) ([]domain.Contest, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	query := r.db.DB.WithContext(ctx).Order("start_time desc")
+	if visibility != "" {
+		query = query.Where("visibility = ?", visibility)
+	}
+	var contests []domain.Contest
+	if err := query.Find(&contests).Error; err != nil {
+		return nil, fmt.Errorf("failed to list contests: %w", err)
+	}
+	return contests, nil
+}
+
+// ListForUser returns the public contests and the private ones userID is
+// registered to, latest first
+func (r *contestRepository) ListForUser(userID int) ([]domain.Contest, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var contests []domain.Contest
+	err := r.db.DB.WithContext(ctx).
+		Where("visibility = ? OR id IN (?)", domain.ContestVisibilityPublic,
+			r.db.DB.Model(&domain.ContestRegistration{}).Select("contest_id").Where("user_id = ?", userID)).
+		Order("start_time desc").
+		Find(&contests).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contests: %w", err)
+	}
+	return contests, nil
+}
+
+func (r *contestRepository) SetUnfrozen(id int, unfrozen bool) error {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	if err := r.db.DB.WithContext(ctx).Model(&domain.Contest{}).Where("id = ?", id).Update("unfrozen", unfrozen).Error; err != nil {
+		return fmt.Errorf("failed to update contest: %w", err)
+	}
+	return nil
+}
+
+func (r *contestRepository) Register(registration *domain.ContestRegistration) error {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	if err := r.db.DB.WithContext(ctx).Create(registration).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("already registered: %w", err)
+		}
+		return fmt.Errorf("failed to register: %w", err)
+	}
+	return nil
+}
+
+func (r *contestRepository) Unregister(contestID, userID int) error {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	if err := r.db.DB.WithContext(ctx).Where("contest_id = ? AND user_id = ?", contestID, userID).Delete(&domain.ContestRegistration{}).Error; err != nil {
+		return fmt.Errorf("failed to unregister: %w", err)
+	}
+	return nil
+}
+
+func (r *contestRepository) IsRegistered(contestID, userID int) (bool, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var count int64
+	err := r.db.DB.WithContext(ctx).Model(&domain.ContestRegistration{}).
+		Where("contest_id = ? AND user_id = ?", contestID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check registration: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *contestRepository) ListRegistrations(contestID int) ([]domain.ContestRegistration, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var registrations []domain.ContestRegistration
+	err := r.db.DB.WithContext(ctx).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "email")
+		}).
+		Where("contest_id = ?", contestID).
+		Order("registered_at asc").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registrations: %w", err)
+	}
+	return registrations, nil
+}
@@ -426,6 +426,37 @@ func (r *problemRepository) IncrementStats(id int, isAccepted bool) error {
 	})
 }
 
+// RecountStats recomputes the problem's submission counts and acceptance rate from
+// its judged submissions, leaving out compilation and internal errors as the
+// incremental stats do
+func (r *problemRepository) RecountStats(id int) error {
+	ctx, cancel := database.WithMediumTimeout()
+	defer cancel()
+
+	query := `
+		UPDATE problems
+		SET total_submissions = counts.total,
+			total_accepted = counts.accepted,
+			acceptance_rate = COALESCE(CAST(counts.accepted AS FLOAT) / CAST(NULLIF(counts.total, 0) AS FLOAT) * 100, 0)
+		FROM (
+			SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE status = ?) AS accepted
+			FROM submissions
+			WHERE problem_id = ? AND is_run_only = false AND is_validation_submission = false AND status NOT IN ?
+		) AS counts
+		WHERE problems.id = ?
+	`
+	notCounted := []domain.SubmissionStatus{
+		domain.SubmissionStatusPending,
+		domain.SubmissionStatusProcessing,
+		domain.SubmissionStatusCompilationError,
+		domain.SubmissionStatusInternalError,
+	}
+	if err := r.db.DB.WithContext(ctx).Exec(query, domain.SubmissionStatusAccepted, id, notCounted, id).Error; err != nil {
+		return fmt.Errorf("failed to recount problem stats: %w", err)
+	}
+	return nil
+}
+
 func (r *problemRepository) UpdateStatus(id int, status string) error {
 	ctx, cancel := database.WithShortTimeout()
 	defer cancel()
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/pkg/database"
+	"gorm.io/gorm"
+)
+
+type rejudgeRepository struct {
+	db *database.Database
+}
+
+func NewRejudgeRepository(db *database.Database) domain.RejudgeRepository {
+	return &rejudgeRepository{db: db}
+}
+
+func (r *rejudgeRepository) Create(rejudge *domain.Rejudge, results []domain.RejudgeResult) error {
+	ctx, cancel := database.WithLongTimeout()
+	defer cancel()
+
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rejudge.Total = len(results)
+		if err := tx.Create(rejudge).Error; err != nil {
+			return fmt.Errorf("failed to create rejudge: %w", err)
+		}
+		if len(results) == 0 {
+			return nil
+		}
+
+		ids := make([]int, len(results))
+		for i := range results {
+			results[i].RejudgeID = rejudge.ID
+			ids[i] = results[i].SubmissionID
+		}
+		if err := tx.CreateInBatches(results, 500).Error; err != nil {
+			return fmt.Errorf("failed to create rejudge results: %w", err)
+		}
+
+		// Only the submissions still judged are taken, so a submission another rejudge
+		// queued since they were listed isn't judged twice
+		res := tx.Model(&domain.Submission{}).
+			Where("id IN ? AND status NOT IN ?", ids,
+				[]domain.SubmissionStatus{domain.SubmissionStatusPending, domain.SubmissionStatusProcessing}).
+			Updates(map[string]interface{}{
+				"status":     domain.SubmissionStatusPending,
+				"rejudge_id": rejudge.ID,
+				"queued_at":  time.Now(),
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to queue submissions: %w", res.Error)
+		}
+		if int(res.RowsAffected) != len(ids) {
+			return fmt.Errorf("%d of %d: %w", len(ids)-int(res.RowsAffected), len(ids), domain.ErrSubmissionsBeingJudged)
+		}
+		return nil
+	})
+}
+
+func (r *rejudgeRepository) GetByID(id int) (*domain.Rejudge, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var rejudge domain.Rejudge
+	if err := r.db.DB.WithContext(ctx).First(&rejudge, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get rejudge: %w", err)
+	}
+	return &rejudge, nil
+}
+
+// List returns the rejudges, latest first
+func (r *rejudgeRepository) List(limit, offset int) ([]domain.Rejudge, int64, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var total int64
+	if err := r.db.DB.WithContext(ctx).Model(&domain.Rejudge{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count rejudges: %w", err)
+	}
+
+	var rejudges []domain.Rejudge
+	err := r.db.DB.WithContext(ctx).
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&rejudges).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list rejudges: %w", err)
+	}
+	return rejudges, total, nil
+}
+
+// ListResults returns the rejudge's results by submission, only those whose verdict
+// or score changed if changedOnly is set
+func (r *rejudgeRepository) ListResults(rejudgeID int, changedOnly bool) ([]domain.RejudgeResult, error) {
+	ctx, cancel := database.WithMediumTimeout()
+	defer cancel()
+
+	query := r.db.DB.WithContext(ctx).Where("rejudge_id = ?", rejudgeID)
+	if changedOnly {
+		query = query.Where("judged_at IS NOT NULL AND (new_status <> old_status OR new_score <> old_score)")
+	}
+	var results []domain.RejudgeResult
+	if err := query.Order("submission_id asc").Find(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to list rejudge results: %w", err)
+	}
+	return results, nil
+}
+
+// ListBySubmission returns a submission's rejudge results, oldest first
+func (r *rejudgeRepository) ListBySubmission(submissionID int) ([]domain.RejudgeResult, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var results []domain.RejudgeResult
+	err := r.db.DB.WithContext(ctx).
+		Where("submission_id = ?", submissionID).
+		Order("created_at asc").
+		Find(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list verdict history: %w", err)
+	}
+	return results, nil
+}
+
+func (r *rejudgeRepository) RecordResult(rejudgeID int, submission *domain.Submission) error {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	now := time.Now()
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var result domain.RejudgeResult
+		err := tx.Where("rejudge_id = ? AND submission_id = ?", rejudgeID, submission.ID).First(&result).Error
+		if err != nil {
+			return fmt.Errorf("failed to get rejudge result: %w", err)
+		}
+		// A retried job may have recorded it already
+		if result.JudgedAt != nil {
+			return nil
+		}
+
+		result.NewStatus = submission.Status
+		result.NewScore = submission.Score
+		result.NewPassedTestCases = submission.PassedTestCases
+		result.JudgedAt = &now
+		if err := tx.Save(&result).Error; err != nil {
+			return fmt.Errorf("failed to save rejudge result: %w", err)
+		}
+
+		changed := 0
+		if result.Changed() {
+			changed = 1
+		}
+		err = tx.Model(&domain.Rejudge{}).Where("id = ?", rejudgeID).Updates(map[string]interface{}{
+			"judged":      gorm.Expr("judged + 1"),
+			"changed":     gorm.Expr("changed + ?", changed),
+			"finished_at": gorm.Expr("CASE WHEN judged + 1 >= total THEN ?::timestamptz ELSE finished_at END", now),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update rejudge progress: %w", err)
+		}
+		return nil
+	})
+}
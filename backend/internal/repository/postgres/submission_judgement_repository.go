@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/pkg/database"
+)
+
+type submissionJudgementRepository struct {
+	db *database.Database
+}
+
+func NewSubmissionJudgementRepository(db *database.Database) domain.SubmissionJudgementRepository {
+	return &submissionJudgementRepository{db: db}
+}
+
+func (r *submissionJudgementRepository) Create(judgement *domain.SubmissionJudgement) error {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	if err := r.db.DB.WithContext(ctx).Create(judgement).Error; err != nil {
+		return fmt.Errorf("failed to create judgement: %w", err)
+	}
+	return nil
+}
+
+func (r *submissionJudgementRepository) GetByID(id int) (*domain.SubmissionJudgement, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var judgement domain.SubmissionJudgement
+	if err := r.db.DB.WithContext(ctx).First(&judgement, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get judgement: %w", err)
+	}
+	return &judgement, nil
+}
+
+func (r *submissionJudgementRepository) ListBySubmission(submissionID int) ([]domain.SubmissionJudgement, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var judgements []domain.SubmissionJudgement
+	err := r.db.DB.WithContext(ctx).
+		Omit("test_case_results").
+		Where("submission_id = ?", submissionID).
+		Order("id asc").
+		Find(&judgements).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list judgements: %w", err)
+	}
+	return judgements, nil
+}
@@ -117,6 +117,32 @@ func (r *submissionRepository) ListAll(limit, offset int) ([]domain.Submission,
 	return submissions, err
 }
 
+// ListByContest returns what the scoreboard needs of every submission made in a
+// contest, oldest first
+func (r *submissionRepository) ListByContest(contestID int) ([]domain.Submission, error) {
+	var submissions []domain.Submission
+	err := r.db.DB.Model(&domain.Submission{}).
+		Select("id", "user_id", "problem_id", "status", "passed_test_cases", "total_test_cases", "score", "is_run_only", "contest_id", "created_at").
+		Where("contest_id = ? AND is_run_only = false", contestID).
+		Order("created_at asc").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+func (r *submissionRepository) ListByContestUser(contestID int, userID int, limit, offset int) ([]domain.Submission, error) {
+	var submissions []domain.Submission
+	err := r.db.DB.Model(&domain.Submission{}).
+		Where("contest_id = ? AND user_id = ? AND is_run_only = false", contestID, userID).
+		Omit("function_code").
+		Preload("Problem").
+		Preload("Language").
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&submissions).Error
+	return submissions, err
+}
+
 func (r *submissionRepository) CountByUser(userID int) (int64, error) {
 	var count int64
 	err := r.db.DB.Model(&domain.Submission{}).Where("user_id = ?", userID).Count(&count).Error
@@ -176,6 +202,155 @@ func (r *submissionRepository) CountPendingBefore(createdAt time.Time) (int64, e
 	return count, err
 }
 
+// GetStuckBefore returns submissions still pending or processing that were queued
+// before the given time, or created before it if they never were
+func (r *submissionRepository) GetStuckBefore(queuedAt time.Time, limit int) ([]domain.Submission, error) {
+	var submissions []domain.Submission
+	err := r.db.DB.Where("status IN ? AND COALESCE(queued_at, created_at) < ?",
+		[]domain.SubmissionStatus{domain.SubmissionStatusPending, domain.SubmissionStatusProcessing}, queuedAt).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&submissions).Error
+	return submissions, err
+}
+
+// SetStatusIfUnjudged only touches the submission while it is still pending or
+// processing, so a verdict a worker wrote since it was read is never overwritten
+func (r *submissionRepository) SetStatusIfUnjudged(id int, status domain.SubmissionStatus, errorMessage string) (bool, error) {
+	return r.setStatusIfIn(id, []domain.SubmissionStatus{domain.SubmissionStatusPending, domain.SubmissionStatusProcessing}, status, errorMessage)
+}
+
+// ResetForReplay only touches a submission the judge gave up on: marked as an internal
+// error when its job was dead-lettered, or left unjudged if that failed
+func (r *submissionRepository) ResetForReplay(id int) (bool, error) {
+	return r.setStatusIfIn(id, []domain.SubmissionStatus{
+		domain.SubmissionStatusInternalError,
+		domain.SubmissionStatusPending,
+		domain.SubmissionStatusProcessing,
+	}, domain.SubmissionStatusPending, "")
+}
+
+// setStatusIfIn sets the status and error message of a submission whose status is one
+// of from, reporting whether it was
+func (r *submissionRepository) setStatusIfIn(id int, from []domain.SubmissionStatus, status domain.SubmissionStatus, errorMessage string) (bool, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	result := r.db.DB.WithContext(ctx).Model(&domain.Submission{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"error_message": errorMessage,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update submission status: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListForRejudge returns the judged submissions matching the filter, oldest first,
+// leaving out runs and validations. It returns at most limit submissions.
+func (r *submissionRepository) ListForRejudge(filter domain.RejudgeFilter, limit int) ([]domain.Submission, error) {
+	query := r.db.DB.Model(&domain.Submission{}).
+		Select("id", "user_id", "problem_id", "status", "score", "passed_test_cases").
+		Where("is_run_only = false AND is_validation_submission = false AND status NOT IN ?",
+			[]domain.SubmissionStatus{domain.SubmissionStatusPending, domain.SubmissionStatusProcessing})
+	if filter.SubmissionID != nil {
+		query = query.Where("id = ?", *filter.SubmissionID)
+	}
+	if filter.ProblemID != nil {
+		query = query.Where("problem_id = ?", *filter.ProblemID)
+	}
+	if filter.LanguageID != nil {
+		query = query.Where("language_id = ?", *filter.LanguageID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var submissions []domain.Submission
+	if err := query.Order("id ASC").Limit(limit).Find(&submissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list submissions to rejudge: %w", err)
+	}
+	return submissions, nil
+}
+
+// ListVerdictsByUserProblem returns the verdicts of all of a user's submissions to a
+// problem, without their code or results
+func (r *submissionRepository) ListVerdictsByUserProblem(userID int, problemID int) ([]domain.Submission, error) {
+	var submissions []domain.Submission
+	err := r.db.DB.Model(&domain.Submission{}).
+		Select("id", "user_id", "problem_id", "status", "score", "is_run_only", "is_validation_submission", "created_at").
+		Where("user_id = ? AND problem_id = ?", userID, problemID).
+		Order("created_at ASC").
+		Find(&submissions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submissions: %w", err)
+	}
+	return submissions, nil
+}
+
+func (r *submissionRepository) CountByUserStatus(userID int, status domain.SubmissionStatus) (int64, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var count int64
+	err := r.db.DB.WithContext(ctx).Model(&domain.Submission{}).
+		Where("user_id = ? AND status = ? AND is_admin_submission = false", userID, status).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count submissions: %w", err)
+	}
+	return count, nil
+}
+
+// CountFirstAttemptSolves counts the problems the user's first submission to was
+// accepted
+func (r *submissionRepository) CountFirstAttemptSolves(userID int) (int64, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var count int64
+	err := r.db.DB.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FROM (
+			SELECT DISTINCT ON (problem_id) status
+			FROM submissions
+			WHERE user_id = ? AND is_admin_submission = false
+			ORDER BY problem_id, created_at ASC, id ASC
+		) firsts
+		WHERE status = ?
+	`, userID, domain.SubmissionStatusAccepted).Scan(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count first attempt solves: %w", err)
+	}
+	return count, nil
+}
+
+// CountSolvesAfterAttempts counts the problems the user had an accepted submission to
+// after at least the given number of earlier submissions
+func (r *submissionRepository) CountSolvesAfterAttempts(userID int, attempts int) (int64, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var count int64
+	err := r.db.DB.WithContext(ctx).Raw(`
+		SELECT COUNT(DISTINCT problem_id) FROM (
+			SELECT problem_id, status,
+				ROW_NUMBER() OVER (PARTITION BY problem_id ORDER BY created_at ASC, id ASC) - 1 AS earlier
+			FROM submissions
+			WHERE user_id = ? AND is_admin_submission = false
+		) numbered
+		WHERE status = ? AND earlier >= ?
+	`, userID, domain.SubmissionStatusAccepted, attempts).Scan(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count solves after attempts: %w", err)
+	}
+	return count, nil
+}
+
 func (r *submissionRepository) CountCombinedStatus(status domain.SubmissionStatus) (int64, error) {
 	var count int64
 	err := r.db.DB.Model(&domain.Submission{}).Where("status = ?", status).Count(&count).Error
@@ -324,3 +499,23 @@ func (r *submissionRepository) GetCurrentStreak(userID int) (int, error) {
 
 	return streak, nil
 }
+
+func (r *submissionRepository) GetPerformanceCounts(submission *domain.Submission) (*domain.PerformanceCounts, error) {
+	ctx, cancel := database.WithShortTimeout()
+	defer cancel()
+
+	var counts domain.PerformanceCounts
+	err := r.db.DB.WithContext(ctx).Raw(`
+		SELECT COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE runtime > ?) AS slower,
+			COUNT(*) FILTER (WHERE memory > ?) AS heavier
+		FROM submissions
+		WHERE problem_id = ? AND language_id = ? AND status = ?
+			AND is_run_only = false AND is_admin_submission = false AND is_validation_submission = false
+	`, submission.Runtime, submission.Memory, submission.ProblemID, submission.LanguageID, domain.SubmissionStatusAccepted).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ranked submissions: %w", err)
+	}
+	return &counts, nil
+}
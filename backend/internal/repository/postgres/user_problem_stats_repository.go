@@ -37,6 +37,8 @@ func (r *userProblemStatsRepository) Get(userID, problemID int) (*domain.UserPro
 	return stats, nil
 }
 
+// Upsert counts an attempt at a problem. The best submission is the latest one
+// scoring at least as much as any before it.
 func (r *userProblemStatsRepository) Upsert(stats *domain.UserProblemStats) error {
 	return r.db.DB.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "user_id"}, {Name: "problem_id"}},
@@ -44,12 +46,23 @@ func (r *userProblemStatsRepository) Upsert(stats *domain.UserProblemStats) erro
 			"attempts":           gorm.Expr("user_problem_stats.attempts + ?", 1),
 			"status":             clause.Column{Table: "excluded", Name: "status"},
 			"first_solved_at":    gorm.Expr("COALESCE(user_problem_stats.first_solved_at, excluded.first_solved_at)"),
-			"best_submission_id": gorm.Expr("COALESCE(excluded.best_submission_id, user_problem_stats.best_submission_id)"),
+			"best_submission_id": gorm.Expr("CASE WHEN excluded.best_score >= user_problem_stats.best_score THEN COALESCE(excluded.best_submission_id, user_problem_stats.best_submission_id) ELSE user_problem_stats.best_submission_id END"),
+			"best_score":         gorm.Expr("GREATEST(user_problem_stats.best_score, excluded.best_score)"),
 			"updated_at":         clause.Column{Table: "excluded", Name: "updated_at"},
 		}),
 	}).Create(stats).Error
 }
 
+// Replace overwrites the stats, keeping only their creation time
+func (r *userProblemStatsRepository) Replace(stats *domain.UserProblemStats) error {
+	return r.db.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "problem_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "attempts", "first_solved_at", "best_submission_id", "best_score", "updated_at",
+		}),
+	}).Create(stats).Error
+}
+
 func (r *userProblemStatsRepository) GetStatuses(userID int, problemIDs []int) (map[int]string, error) {
 	var results []struct {
 		ProblemID int
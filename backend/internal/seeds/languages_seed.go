@@ -16,6 +16,10 @@ func SeedLanguages(db *gorm.DB) error {
 		{Name: "C++", Slug: "c++"},
 		{Name: "C", Slug: "c"},
 		{Name: "Go", Slug: "go"},
+		{Name: "Rust", Slug: "rust"},
+		{Name: "TypeScript", Slug: "typescript"},
+		{Name: "Kotlin", Slug: "kotlin"},
+		{Name: "C#", Slug: "csharp"},
 	}
 
 	for _, lang := range languages {
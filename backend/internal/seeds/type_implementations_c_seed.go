@@ -0,0 +1,225 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// cTypeImplementations are the C custom types. Deserializers read the harness's
+// JsonValue; serializers return the JSON text in a buffer the caller frees.
+func cTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// C - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+struct TreeNode {
+    int val;
+    struct TreeNode *left;
+    struct TreeNode *right;
+};
+`,
+			DeserializerCode: `
+struct TreeNode* newTreeNode(int val) {
+    struct TreeNode* node = calloc(1, sizeof(struct TreeNode));
+    node->val = val;
+    return node;
+}
+
+struct TreeNode* deserializeTreeNode(JsonValue data) {
+    if (data.count == 0 || isJsonNull(data.array[0])) {
+        return NULL;
+    }
+
+    struct TreeNode** queue = malloc(sizeof(struct TreeNode*) * data.count);
+    int head = 0, tail = 0, i = 1;
+    struct TreeNode* root = newTreeNode(atoi(data.array[0].raw));
+    queue[tail++] = root;
+
+    while (head < tail && i < data.count) {
+        struct TreeNode* node = queue[head++];
+
+        // Left child
+        if (i < data.count && !isJsonNull(data.array[i])) {
+            node->left = newTreeNode(atoi(data.array[i].raw));
+            queue[tail++] = node->left;
+        }
+        i++;
+
+        // Right child
+        if (i < data.count && !isJsonNull(data.array[i])) {
+            node->right = newTreeNode(atoi(data.array[i].raw));
+            queue[tail++] = node->right;
+        }
+        i++;
+    }
+
+    free(queue);
+    return root;
+}
+`,
+			SerializerCode: `
+char* serializeTreeNode(struct TreeNode* root) {
+    int cap = 16, head = 0, tail = 0;
+    struct TreeNode** queue = malloc(sizeof(struct TreeNode*) * cap);
+    if (root) queue[tail++] = root;
+
+    while (head < tail) {
+        struct TreeNode* node = queue[head++];
+        if (!node) continue;
+        if (tail + 2 > cap) {
+            cap *= 2;
+            queue = realloc(queue, sizeof(struct TreeNode*) * cap);
+        }
+        queue[tail++] = node->left;
+        queue[tail++] = node->right;
+    }
+
+    // Remove trailing nulls
+    while (tail > 0 && !queue[tail - 1]) tail--;
+
+    char* res = malloc(tail * 12 + 3);
+    char* p = res;
+    *p++ = '[';
+    for (int i = 0; i < tail; i++) {
+        if (i > 0) *p++ = ',';
+        if (queue[i]) p += sprintf(p, "%d", queue[i]->val);
+        else p += sprintf(p, "null");
+    }
+    strcpy(p, "]");
+
+    free(queue);
+    return res;
+}
+`,
+		},
+
+		// C - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+struct ListNode {
+    int val;
+    struct ListNode *next;
+};
+`,
+			DeserializerCode: `
+struct ListNode* deserializeListNode(JsonValue data) {
+    struct ListNode dummy = {0};
+    struct ListNode* current = &dummy;
+
+    for (int i = 0; i < data.count; i++) {
+        current->next = calloc(1, sizeof(struct ListNode));
+        current = current->next;
+        current->val = atoi(data.array[i].raw);
+    }
+
+    return dummy.next;
+}
+`,
+			SerializerCode: `
+char* serializeListNode(struct ListNode* head) {
+    int count = 0;
+    for (struct ListNode* current = head; current; current = current->next) count++;
+
+    char* res = malloc(count * 12 + 3);
+    char* p = res;
+    *p++ = '[';
+    for (struct ListNode* current = head; current; current = current->next) {
+        if (current != head) *p++ = ',';
+        p += sprintf(p, "%d", current->val);
+    }
+    strcpy(p, "]");
+
+    return res;
+}
+`,
+		},
+
+		// C - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+struct GraphNode {
+    int val;
+    int numNeighbors;
+    struct GraphNode** neighbors;
+};
+`,
+			DeserializerCode: `
+struct GraphNode* deserializeGraphNode(JsonValue data) {
+    if (data.count == 0) {
+        return NULL;
+    }
+
+    struct GraphNode** nodes = malloc(sizeof(struct GraphNode*) * data.count);
+    for (int i = 0; i < data.count; i++) {
+        nodes[i] = calloc(1, sizeof(struct GraphNode));
+        nodes[i]->val = i + 1;
+    }
+    for (int i = 0; i < data.count; i++) {
+        JsonValue neighbors = data.array[i];
+        nodes[i]->numNeighbors = neighbors.count;
+        nodes[i]->neighbors = malloc(sizeof(struct GraphNode*) * (neighbors.count + 1));
+        for (int k = 0; k < neighbors.count; k++) {
+            nodes[i]->neighbors[k] = nodes[atoi(neighbors.array[k].raw) - 1];
+        }
+    }
+
+    struct GraphNode* root = nodes[0];
+    free(nodes);
+    return root;
+}
+`,
+			SerializerCode: `
+char* serializeGraphNode(struct GraphNode* node) {
+    if (!node) {
+        char* res = malloc(3);
+        strcpy(res, "[]");
+        return res;
+    }
+
+    int cap = 16, head = 0, tail = 0, maxVal = 0;
+    size_t size = 3;
+    struct GraphNode** queue = malloc(sizeof(struct GraphNode*) * cap);
+    queue[tail++] = node;
+
+    while (head < tail) {
+        struct GraphNode* current = queue[head++];
+        if (current->val > maxVal) maxVal = current->val;
+        size += current->numNeighbors * 12;
+        for (int k = 0; k < current->numNeighbors; k++) {
+            struct GraphNode* neighbor = current->neighbors[k];
+            bool seen = false;
+            for (int q = 0; q < tail && !seen; q++) seen = queue[q] == neighbor;
+            if (seen) continue;
+            if (tail == cap) {
+                cap *= 2;
+                queue = realloc(queue, sizeof(struct GraphNode*) * cap);
+            }
+            queue[tail++] = neighbor;
+        }
+    }
+
+    char* res = malloc(size + maxVal * 3);
+    char* p = res;
+    *p++ = '[';
+    for (int v = 1; v <= maxVal; v++) {
+        struct GraphNode* n = NULL;
+        for (int q = 0; q < tail && !n; q++) if (queue[q]->val == v) n = queue[q];
+        if (v > 1) *p++ = ',';
+        *p++ = '[';
+        for (int k = 0; n && k < n->numNeighbors; k++) {
+            p += sprintf(p, k > 0 ? ",%d" : "%d", n->neighbors[k]->val);
+        }
+        *p++ = ']';
+    }
+    strcpy(p, "]");
+
+    free(queue);
+    return res;
+}
+`,
+		},
+	}
+}
@@ -0,0 +1,192 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// cppTypeImplementations are the C++ custom types. The (de)serializers work on the
+// harness's JsonValue, built with its jsonRaw and jsonArray helpers.
+func cppTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// C++ - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+struct TreeNode {
+    int val;
+    TreeNode *left;
+    TreeNode *right;
+    TreeNode() : val(0), left(nullptr), right(nullptr) {}
+    TreeNode(int x) : val(x), left(nullptr), right(nullptr) {}
+    TreeNode(int x, TreeNode *left, TreeNode *right) : val(x), left(left), right(right) {}
+};
+`,
+			DeserializerCode: `
+TreeNode* deserializeTreeNode(const JsonValue& data) {
+    if (data.array.empty() || data.array[0].isNull()) {
+        return nullptr;
+    }
+
+    TreeNode* root = new TreeNode(stoi(data.array[0].raw));
+    queue<TreeNode*> q;
+    q.push(root);
+    size_t i = 1;
+
+    while (!q.empty() && i < data.array.size()) {
+        TreeNode* node = q.front();
+        q.pop();
+
+        // Left child
+        if (i < data.array.size() && !data.array[i].isNull()) {
+            node->left = new TreeNode(stoi(data.array[i].raw));
+            q.push(node->left);
+        }
+        i++;
+
+        // Right child
+        if (i < data.array.size() && !data.array[i].isNull()) {
+            node->right = new TreeNode(stoi(data.array[i].raw));
+            q.push(node->right);
+        }
+        i++;
+    }
+
+    return root;
+}
+`,
+			SerializerCode: `
+JsonValue serializeTreeNode(TreeNode* root) {
+    JsonValue result = jsonArray();
+    queue<TreeNode*> q;
+    if (root) q.push(root);
+
+    while (!q.empty()) {
+        TreeNode* node = q.front();
+        q.pop();
+        if (node) {
+            result.array.push_back(jsonRaw(to_string(node->val)));
+            q.push(node->left);
+            q.push(node->right);
+        } else {
+            result.array.push_back(jsonRaw("null"));
+        }
+    }
+
+    // Remove trailing nulls
+    while (!result.array.empty() && result.array.back().isNull()) {
+        result.array.pop_back();
+    }
+
+    return result;
+}
+`,
+		},
+
+		// C++ - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+struct ListNode {
+    int val;
+    ListNode *next;
+    ListNode() : val(0), next(nullptr) {}
+    ListNode(int x) : val(x), next(nullptr) {}
+    ListNode(int x, ListNode *next) : val(x), next(next) {}
+};
+`,
+			DeserializerCode: `
+ListNode* deserializeListNode(const JsonValue& data) {
+    ListNode dummy;
+    ListNode* current = &dummy;
+
+    for (const auto& val : data.array) {
+        current->next = new ListNode(stoi(val.raw));
+        current = current->next;
+    }
+
+    return dummy.next;
+}
+`,
+			SerializerCode: `
+JsonValue serializeListNode(ListNode* head) {
+    JsonValue result = jsonArray();
+
+    for (ListNode* current = head; current; current = current->next) {
+        result.array.push_back(jsonRaw(to_string(current->val)));
+    }
+
+    return result;
+}
+`,
+		},
+
+		// C++ - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+struct GraphNode {
+    int val;
+    vector<GraphNode*> neighbors;
+    GraphNode() : val(0) {}
+    GraphNode(int x) : val(x) {}
+    GraphNode(int x, vector<GraphNode*> neighbors) : val(x), neighbors(neighbors) {}
+};
+`,
+			DeserializerCode: `
+GraphNode* deserializeGraphNode(const JsonValue& data) {
+    if (data.array.empty()) {
+        return nullptr;
+    }
+
+    vector<GraphNode*> nodes;
+    for (size_t i = 0; i < data.array.size(); ++i) {
+        nodes.push_back(new GraphNode(i + 1));
+    }
+    for (size_t i = 0; i < data.array.size(); ++i) {
+        for (const auto& j : data.array[i].array) {
+            nodes[i]->neighbors.push_back(nodes.at(stoi(j.raw) - 1));
+        }
+    }
+
+    return nodes[0];
+}
+`,
+			SerializerCode: `
+JsonValue serializeGraphNode(GraphNode* node) {
+    JsonValue result = jsonArray();
+    if (!node) {
+        return result;
+    }
+
+    map<int, GraphNode*> seen = {{node->val, node}};
+    queue<GraphNode*> q;
+    q.push(node);
+
+    while (!q.empty()) {
+        GraphNode* current = q.front();
+        q.pop();
+        for (GraphNode* neighbor : current->neighbors) {
+            if (seen.insert({neighbor->val, neighbor}).second) {
+                q.push(neighbor);
+            }
+        }
+    }
+
+    for (int v = 1; v <= seen.rbegin()->first; ++v) {
+        JsonValue neighbors = jsonArray();
+        auto it = seen.find(v);
+        if (it != seen.end()) {
+            for (GraphNode* neighbor : it->second->neighbors) {
+                neighbors.array.push_back(jsonRaw(to_string(neighbor->val)));
+            }
+        }
+        result.array.push_back(neighbors);
+    }
+
+    return result;
+}
+`,
+		},
+	}
+}
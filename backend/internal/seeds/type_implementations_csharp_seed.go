@@ -0,0 +1,191 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// cSharpTypeImplementations are the C# custom types, shaped like LeetCode's. Classes
+// are top-level next to the user's Solution; the (de)serializers are static members
+// of the harness class, working on its Json.
+func cSharpTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// C# - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+public class TreeNode {
+    public int val;
+    public TreeNode left;
+    public TreeNode right;
+    public TreeNode(int val = 0, TreeNode left = null, TreeNode right = null) {
+        this.val = val;
+        this.left = left;
+        this.right = right;
+    }
+}
+`,
+			DeserializerCode: `
+    static TreeNode DeserializeTreeNode(Json data) {
+        if (data.Items.Count == 0 || data.Items[0].IsNull) {
+            return null;
+        }
+
+        var root = new TreeNode((int)data.Items[0].Long());
+        var queue = new Queue<TreeNode>();
+        queue.Enqueue(root);
+        int i = 1;
+
+        while (queue.Count > 0 && i < data.Items.Count) {
+            TreeNode node = queue.Dequeue();
+
+            // Left child
+            if (!data.Items[i].IsNull) {
+                node.left = new TreeNode((int)data.Items[i].Long());
+                queue.Enqueue(node.left);
+            }
+            i++;
+
+            // Right child
+            if (i < data.Items.Count && !data.Items[i].IsNull) {
+                node.right = new TreeNode((int)data.Items[i].Long());
+                queue.Enqueue(node.right);
+            }
+            i++;
+        }
+
+        return root;
+    }
+`,
+			SerializerCode: `
+    static Json SerializeTreeNode(TreeNode root) {
+        var result = new Json { Type = Json.Kind.Arr };
+        var queue = new Queue<TreeNode>();
+        queue.Enqueue(root);
+
+        while (queue.Count > 0) {
+            TreeNode node = queue.Dequeue();
+            if (node == null) {
+                result.Items.Add(Json.Null);
+                continue;
+            }
+            result.Items.Add(ToJson(node.val));
+            queue.Enqueue(node.left);
+            queue.Enqueue(node.right);
+        }
+
+        // Remove trailing nulls
+        while (result.Items.Count > 0 && result.Items[result.Items.Count - 1].IsNull) {
+            result.Items.RemoveAt(result.Items.Count - 1);
+        }
+
+        return result;
+    }
+`,
+		},
+
+		// C# - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+public class ListNode {
+    public int val;
+    public ListNode next;
+    public ListNode(int val = 0, ListNode next = null) {
+        this.val = val;
+        this.next = next;
+    }
+}
+`,
+			DeserializerCode: `
+    static ListNode DeserializeListNode(Json data) {
+        var dummy = new ListNode();
+        ListNode current = dummy;
+
+        foreach (Json v in data.Items) {
+            current.next = new ListNode((int)v.Long());
+            current = current.next;
+        }
+
+        return dummy.next;
+    }
+`,
+			SerializerCode: `
+    static Json SerializeListNode(ListNode head) {
+        var result = new Json { Type = Json.Kind.Arr };
+
+        for (ListNode current = head; current != null; current = current.next) {
+            result.Items.Add(ToJson(current.val));
+        }
+
+        return result;
+    }
+`,
+		},
+
+		// C# - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+public class GraphNode {
+    public int val;
+    public IList<GraphNode> neighbors;
+    public GraphNode(int val = 0) {
+        this.val = val;
+        this.neighbors = new List<GraphNode>();
+    }
+}
+`,
+			DeserializerCode: `
+    static GraphNode DeserializeGraphNode(Json data) {
+        if (data.Items.Count == 0) {
+            return null;
+        }
+
+        var nodes = new GraphNode[data.Items.Count];
+        for (int i = 0; i < nodes.Length; i++) {
+            nodes[i] = new GraphNode(i + 1);
+        }
+        for (int i = 0; i < nodes.Length; i++) {
+            foreach (Json j in data.Items[i].Items) {
+                nodes[i].neighbors.Add(nodes[(int)j.Long() - 1]);
+            }
+        }
+
+        return nodes[0];
+    }
+`,
+			SerializerCode: `
+    static Json SerializeGraphNode(GraphNode node) {
+        var result = new Json { Type = Json.Kind.Arr };
+        if (node == null) {
+            return result;
+        }
+
+        var seen = new Dictionary<int, GraphNode> { { node.val, node } };
+        var queue = new Queue<GraphNode>();
+        queue.Enqueue(node);
+        int maxVal = node.val;
+
+        while (queue.Count > 0) {
+            GraphNode current = queue.Dequeue();
+            foreach (GraphNode neighbor in current.neighbors) {
+                if (!seen.ContainsKey(neighbor.val)) {
+                    seen[neighbor.val] = neighbor;
+                    queue.Enqueue(neighbor);
+                    maxVal = Math.Max(maxVal, neighbor.val);
+                }
+            }
+        }
+
+        for (int v = 1; v <= maxVal; v++) {
+            GraphNode n;
+            result.Items.Add(seen.TryGetValue(v, out n) ? ToJson(n.neighbors.Select(nb => nb.val)) : new Json { Type = Json.Kind.Arr });
+        }
+
+        return result;
+    }
+`,
+		},
+	}
+}
@@ -0,0 +1,187 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// goTypeImplementations are the Go custom types. Deserializers read values decoded by
+// encoding/json; serializers return plain values the harness encodes again.
+func goTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// Go - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+type TreeNode struct {
+	Val   int
+	Left  *TreeNode
+	Right *TreeNode
+}
+`,
+			DeserializerCode: `
+func deserializeTreeNode(data interface{}) *TreeNode {
+	values, _ := data.([]interface{})
+	if len(values) == 0 || values[0] == nil {
+		return nil
+	}
+
+	root := &TreeNode{Val: int(values[0].(float64))}
+	queue := []*TreeNode{root}
+
+	for i := 1; len(queue) > 0 && i < len(values); i += 2 {
+		node := queue[0]
+		queue = queue[1:]
+
+		// Left child
+		if values[i] != nil {
+			node.Left = &TreeNode{Val: int(values[i].(float64))}
+			queue = append(queue, node.Left)
+		}
+
+		// Right child
+		if i+1 < len(values) && values[i+1] != nil {
+			node.Right = &TreeNode{Val: int(values[i+1].(float64))}
+			queue = append(queue, node.Right)
+		}
+	}
+
+	return root
+}
+`,
+			SerializerCode: `
+func serializeTreeNode(root *TreeNode) []interface{} {
+	result := []interface{}{}
+	if root == nil {
+		return result
+	}
+
+	queue := []*TreeNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node == nil {
+			result = append(result, nil)
+			continue
+		}
+		result = append(result, node.Val)
+		queue = append(queue, node.Left, node.Right)
+	}
+
+	// Remove trailing nils
+	for len(result) > 0 && result[len(result)-1] == nil {
+		result = result[:len(result)-1]
+	}
+
+	return result
+}
+`,
+		},
+
+		// Go - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+type ListNode struct {
+	Val  int
+	Next *ListNode
+}
+`,
+			DeserializerCode: `
+func deserializeListNode(data interface{}) *ListNode {
+	values, _ := data.([]interface{})
+	dummy := &ListNode{}
+	current := dummy
+
+	for _, v := range values {
+		current.Next = &ListNode{Val: int(v.(float64))}
+		current = current.Next
+	}
+
+	return dummy.Next
+}
+`,
+			SerializerCode: `
+func serializeListNode(head *ListNode) []interface{} {
+	result := []interface{}{}
+
+	for current := head; current != nil; current = current.Next {
+		result = append(result, current.Val)
+	}
+
+	return result
+}
+`,
+		},
+
+		// Go - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+type GraphNode struct {
+	Val       int
+	Neighbors []*GraphNode
+}
+`,
+			DeserializerCode: `
+func deserializeGraphNode(data interface{}) *GraphNode {
+	lists, _ := data.([]interface{})
+	if len(lists) == 0 {
+		return nil
+	}
+
+	nodes := make([]*GraphNode, len(lists))
+	for i := range nodes {
+		nodes[i] = &GraphNode{Val: i + 1}
+	}
+	for i, raw := range lists {
+		neighbors, _ := raw.([]interface{})
+		for _, j := range neighbors {
+			nodes[i].Neighbors = append(nodes[i].Neighbors, nodes[int(j.(float64))-1])
+		}
+	}
+
+	return nodes[0]
+}
+`,
+			SerializerCode: `
+func serializeGraphNode(node *GraphNode) []interface{} {
+	result := []interface{}{}
+	if node == nil {
+		return result
+	}
+
+	seen := map[int]*GraphNode{node.Val: node}
+	queue := []*GraphNode{node}
+	maxVal := node.Val
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range current.Neighbors {
+			if _, ok := seen[neighbor.Val]; !ok {
+				seen[neighbor.Val] = neighbor
+				queue = append(queue, neighbor)
+				if neighbor.Val > maxVal {
+					maxVal = neighbor.Val
+				}
+			}
+		}
+	}
+
+	for v := 1; v <= maxVal; v++ {
+		neighbors := []interface{}{}
+		if n, ok := seen[v]; ok {
+			for _, neighbor := range n.Neighbors {
+				neighbors = append(neighbors, neighbor.Val)
+			}
+		}
+		result = append(result, neighbors)
+	}
+
+	return result
+}
+`,
+		},
+	}
+}
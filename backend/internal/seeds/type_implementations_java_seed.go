@@ -0,0 +1,197 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// javaTypeImplementations are the Java custom types. Classes are top-level next to
+// the user's Solution; the (de)serializers are static methods of the harness class,
+// working on its JsonValue.
+func javaTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// Java - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class TreeNode {
+    int val;
+    TreeNode left;
+    TreeNode right;
+    TreeNode() {}
+    TreeNode(int val) { this.val = val; }
+    TreeNode(int val, TreeNode left, TreeNode right) {
+        this.val = val;
+        this.left = left;
+        this.right = right;
+    }
+}
+`,
+			DeserializerCode: `
+    static TreeNode deserializeTreeNode(JsonValue data) {
+        if (data.array.isEmpty() || data.array.get(0).isNull()) {
+            return null;
+        }
+
+        TreeNode root = new TreeNode(Integer.parseInt(data.array.get(0).raw));
+        Deque<TreeNode> queue = new ArrayDeque<>();
+        queue.add(root);
+        int i = 1;
+
+        while (!queue.isEmpty() && i < data.array.size()) {
+            TreeNode node = queue.poll();
+
+            // Left child
+            if (i < data.array.size() && !data.array.get(i).isNull()) {
+                node.left = new TreeNode(Integer.parseInt(data.array.get(i).raw));
+                queue.add(node.left);
+            }
+            i++;
+
+            // Right child
+            if (i < data.array.size() && !data.array.get(i).isNull()) {
+                node.right = new TreeNode(Integer.parseInt(data.array.get(i).raw));
+                queue.add(node.right);
+            }
+            i++;
+        }
+
+        return root;
+    }
+`,
+			SerializerCode: `
+    static JsonValue serializeTreeNode(TreeNode root) {
+        JsonValue result = JsonValue.array();
+        // LinkedList rather than ArrayDeque, since missing children are queued as null
+        Queue<TreeNode> queue = new LinkedList<>();
+        if (root != null) queue.add(root);
+
+        while (!queue.isEmpty()) {
+            TreeNode node = queue.poll();
+            if (node != null) {
+                result.array.add(JsonValue.of(String.valueOf(node.val)));
+                queue.add(node.left);
+                queue.add(node.right);
+            } else {
+                result.array.add(JsonValue.of("null"));
+            }
+        }
+
+        // Remove trailing nulls
+        while (!result.array.isEmpty() && result.array.get(result.array.size() - 1).isNull()) {
+            result.array.remove(result.array.size() - 1);
+        }
+
+        return result;
+    }
+`,
+		},
+
+		// Java - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class ListNode {
+    int val;
+    ListNode next;
+    ListNode() {}
+    ListNode(int val) { this.val = val; }
+    ListNode(int val, ListNode next) { this.val = val; this.next = next; }
+}
+`,
+			DeserializerCode: `
+    static ListNode deserializeListNode(JsonValue data) {
+        ListNode dummy = new ListNode(0);
+        ListNode current = dummy;
+
+        for (JsonValue val : data.array) {
+            current.next = new ListNode(Integer.parseInt(val.raw));
+            current = current.next;
+        }
+
+        return dummy.next;
+    }
+`,
+			SerializerCode: `
+    static JsonValue serializeListNode(ListNode head) {
+        JsonValue result = JsonValue.array();
+
+        for (ListNode current = head; current != null; current = current.next) {
+            result.array.add(JsonValue.of(String.valueOf(current.val)));
+        }
+
+        return result;
+    }
+`,
+		},
+
+		// Java - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class GraphNode {
+    public int val;
+    public List<GraphNode> neighbors;
+    public GraphNode() { this(0); }
+    public GraphNode(int val) { this(val, new ArrayList<GraphNode>()); }
+    public GraphNode(int val, List<GraphNode> neighbors) {
+        this.val = val;
+        this.neighbors = neighbors;
+    }
+}
+`,
+			DeserializerCode: `
+    static GraphNode deserializeGraphNode(JsonValue data) {
+        if (data.array.isEmpty()) {
+            return null;
+        }
+
+        List<GraphNode> nodes = new ArrayList<>();
+        for (int i = 0; i < data.array.size(); i++) {
+            nodes.add(new GraphNode(i + 1));
+        }
+        for (int i = 0; i < data.array.size(); i++) {
+            for (JsonValue j : data.array.get(i).array) {
+                nodes.get(i).neighbors.add(nodes.get(Integer.parseInt(j.raw) - 1));
+            }
+        }
+
+        return nodes.get(0);
+    }
+`,
+			SerializerCode: `
+    static JsonValue serializeGraphNode(GraphNode node) {
+        JsonValue result = JsonValue.array();
+        if (node == null) {
+            return result;
+        }
+
+        TreeMap<Integer, GraphNode> seen = new TreeMap<>();
+        seen.put(node.val, node);
+        Deque<GraphNode> queue = new ArrayDeque<>();
+        queue.add(node);
+
+        while (!queue.isEmpty()) {
+            for (GraphNode neighbor : queue.poll().neighbors) {
+                if (seen.putIfAbsent(neighbor.val, neighbor) == null) {
+                    queue.add(neighbor);
+                }
+            }
+        }
+
+        for (int v = 1; v <= seen.lastKey(); v++) {
+            JsonValue neighbors = JsonValue.array();
+            if (seen.containsKey(v)) {
+                for (GraphNode neighbor : seen.get(v).neighbors) {
+                    neighbors.array.add(JsonValue.of(String.valueOf(neighbor.val)));
+                }
+            }
+            result.array.add(neighbors);
+        }
+
+        return result;
+    }
+`,
+		},
+	}
+}
@@ -0,0 +1,181 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// kotlinVal is the node value's name, a keyword Kotlin needs quoted in backticks
+const kotlinVal = "`val`"
+
+// kotlinTypeImplementations are the Kotlin custom types, shaped like LeetCode's.
+// Classes are top-level next to the user's Solution; the (de)serializers are
+// members of the harness object, working on its Json.
+func kotlinTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// Kotlin - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class TreeNode(var ` + kotlinVal + `: Int) {
+    var left: TreeNode? = null
+    var right: TreeNode? = null
+}
+`,
+			DeserializerCode: `
+    fun deserializeTreeNode(data: Json): TreeNode? {
+        val values = data.items()
+        if (values.isEmpty() || values[0].isNull()) {
+            return null
+        }
+
+        val root = TreeNode(values[0].int())
+        val queue = LinkedList<TreeNode>()
+        queue.add(root)
+        var i = 1
+
+        while (queue.isNotEmpty() && i < values.size) {
+            val node = queue.poll()
+
+            // Left child
+            if (!values[i].isNull()) {
+                val left = TreeNode(values[i].int())
+                node.left = left
+                queue.add(left)
+            }
+            i++
+
+            // Right child
+            if (i < values.size && !values[i].isNull()) {
+                val right = TreeNode(values[i].int())
+                node.right = right
+                queue.add(right)
+            }
+            i++
+        }
+
+        return root
+    }
+`,
+			SerializerCode: `
+    fun serializeTreeNode(root: TreeNode?): Json {
+        val result = ArrayList<Json>()
+        val queue = LinkedList<TreeNode?>()
+        queue.add(root)
+
+        while (queue.isNotEmpty()) {
+            val node = queue.poll()
+            if (node == null) {
+                result.add(Json.Null)
+                continue
+            }
+            result.add(toJson(node.` + kotlinVal + `))
+            queue.add(node.left)
+            queue.add(node.right)
+        }
+
+        // Remove trailing nulls
+        while (result.isNotEmpty() && result.last().isNull()) {
+            result.removeAt(result.size - 1)
+        }
+
+        return Json.Arr(result)
+    }
+`,
+		},
+
+		// Kotlin - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class ListNode(var ` + kotlinVal + `: Int) {
+    var next: ListNode? = null
+}
+`,
+			DeserializerCode: `
+    fun deserializeListNode(data: Json): ListNode? {
+        val dummy = ListNode(0)
+        var current = dummy
+
+        for (v in data.items()) {
+            val node = ListNode(v.int())
+            current.next = node
+            current = node
+        }
+
+        return dummy.next
+    }
+`,
+			SerializerCode: `
+    fun serializeListNode(head: ListNode?): Json {
+        val result = ArrayList<Json>()
+        var current = head
+
+        while (current != null) {
+            result.add(toJson(current.` + kotlinVal + `))
+            current = current.next
+        }
+
+        return Json.Arr(result)
+    }
+`,
+		},
+
+		// Kotlin - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class GraphNode(var ` + kotlinVal + `: Int) {
+    var neighbors: ArrayList<GraphNode?> = ArrayList()
+}
+`,
+			DeserializerCode: `
+    fun deserializeGraphNode(data: Json): GraphNode? {
+        val lists = data.items()
+        if (lists.isEmpty()) {
+            return null
+        }
+
+        val nodes = List(lists.size) { GraphNode(it + 1) }
+        for ((i, neighbors) in lists.withIndex()) {
+            for (j in neighbors.items()) {
+                nodes[i].neighbors.add(nodes[j.int() - 1])
+            }
+        }
+
+        return nodes[0]
+    }
+`,
+			SerializerCode: `
+    fun serializeGraphNode(node: GraphNode?): Json {
+        if (node == null) {
+            return Json.Arr(emptyList())
+        }
+
+        val seen = HashMap<Int, GraphNode>()
+        seen[node.` + kotlinVal + `] = node
+        val queue = LinkedList<GraphNode>()
+        queue.add(node)
+        var maxVal = node.` + kotlinVal + `
+
+        while (queue.isNotEmpty()) {
+            val current = queue.poll()
+            for (neighbor in current.neighbors.filterNotNull()) {
+                if (!seen.containsKey(neighbor.` + kotlinVal + `)) {
+                    seen[neighbor.` + kotlinVal + `] = neighbor
+                    queue.add(neighbor)
+                    maxVal = Math.max(maxVal, neighbor.` + kotlinVal + `)
+                }
+            }
+        }
+
+        val result = (1..maxVal).map { v ->
+            toJson(seen[v]?.neighbors?.filterNotNull()?.map { it.` + kotlinVal + ` } ?: emptyList<Int>())
+        }
+
+        return Json.Arr(result)
+    }
+`,
+		},
+	}
+}
@@ -0,0 +1,213 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// rustTypeImplementations are the Rust custom types, shaped like LeetCode's. Types
+// live in the harness's prelude module, so they and their fields are public; the
+// (de)serializers live in the harness module and work on its Json.
+func rustTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// Rust - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+#[derive(Debug, PartialEq, Eq)]
+pub struct TreeNode {
+    pub val: i32,
+    pub left: Option<Rc<RefCell<TreeNode>>>,
+    pub right: Option<Rc<RefCell<TreeNode>>>,
+}
+
+impl TreeNode {
+    #[inline]
+    pub fn new(val: i32) -> Self {
+        TreeNode { val, left: None, right: None }
+    }
+}
+`,
+			DeserializerCode: `
+    fn deserialize_treenode(data: &Json) -> Option<Rc<RefCell<TreeNode>>> {
+        let values = data.items();
+        if values.is_empty() || values[0].is_null() {
+            return None;
+        }
+
+        let root = Rc::new(RefCell::new(TreeNode::new(values[0].as_i64() as i32)));
+        let mut queue = std::collections::VecDeque::new();
+        queue.push_back(Rc::clone(&root));
+        let mut i = 1;
+
+        while let Some(node) = queue.pop_front() {
+            if i >= values.len() {
+                break;
+            }
+
+            // Left child
+            if !values[i].is_null() {
+                let left = Rc::new(RefCell::new(TreeNode::new(values[i].as_i64() as i32)));
+                node.borrow_mut().left = Some(Rc::clone(&left));
+                queue.push_back(left);
+            }
+            i += 1;
+
+            // Right child
+            if i < values.len() && !values[i].is_null() {
+                let right = Rc::new(RefCell::new(TreeNode::new(values[i].as_i64() as i32)));
+                node.borrow_mut().right = Some(Rc::clone(&right));
+                queue.push_back(right);
+            }
+            i += 1;
+        }
+
+        Some(root)
+    }
+`,
+			SerializerCode: `
+    fn serialize_treenode(root: Option<Rc<RefCell<TreeNode>>>) -> Json {
+        let mut result = Vec::new();
+        let mut queue = std::collections::VecDeque::new();
+        queue.push_back(root);
+
+        while let Some(node) = queue.pop_front() {
+            match node {
+                Some(node) => {
+                    let node = node.borrow();
+                    result.push(Json::Num(node.val.to_string()));
+                    queue.push_back(node.left.clone());
+                    queue.push_back(node.right.clone());
+                }
+                None => result.push(Json::Null),
+            }
+        }
+
+        // Remove trailing nulls
+        while result.last() == Some(&Json::Null) {
+            result.pop();
+        }
+
+        Json::Arr(result)
+    }
+`,
+		},
+
+		// Rust - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+#[derive(PartialEq, Eq, Clone, Debug)]
+pub struct ListNode {
+    pub val: i32,
+    pub next: Option<Box<ListNode>>,
+}
+
+impl ListNode {
+    #[inline]
+    pub fn new(val: i32) -> Self {
+        ListNode { next: None, val }
+    }
+}
+`,
+			DeserializerCode: `
+    fn deserialize_listnode(data: &Json) -> Option<Box<ListNode>> {
+        let mut head = None;
+
+        // Built from the tail, since each node owns the next
+        for v in data.items().iter().rev() {
+            let mut node = Box::new(ListNode::new(v.as_i64() as i32));
+            node.next = head;
+            head = Some(node);
+        }
+
+        head
+    }
+`,
+			SerializerCode: `
+    fn serialize_listnode(head: Option<Box<ListNode>>) -> Json {
+        let mut result = Vec::new();
+        let mut current = head.as_ref();
+
+        while let Some(node) = current {
+            result.push(Json::Num(node.val.to_string()));
+            current = node.next.as_ref();
+        }
+
+        Json::Arr(result)
+    }
+`,
+		},
+
+		// Rust - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+#[derive(Debug)]
+pub struct GraphNode {
+    pub val: i32,
+    pub neighbors: Vec<Rc<RefCell<GraphNode>>>,
+}
+
+impl GraphNode {
+    #[inline]
+    pub fn new(val: i32) -> Self {
+        GraphNode { val, neighbors: Vec::new() }
+    }
+}
+`,
+			DeserializerCode: `
+    fn deserialize_graphnode(data: &Json) -> Option<Rc<RefCell<GraphNode>>> {
+        let lists = data.items();
+        if lists.is_empty() {
+            return None;
+        }
+
+        let nodes: Vec<_> = (1..=lists.len()).map(|v| Rc::new(RefCell::new(GraphNode::new(v as i32)))).collect();
+        for (i, neighbors) in lists.iter().enumerate() {
+            for j in neighbors.items() {
+                let neighbor = Rc::clone(&nodes[j.as_i64() as usize - 1]);
+                nodes[i].borrow_mut().neighbors.push(neighbor);
+            }
+        }
+
+        Some(Rc::clone(&nodes[0]))
+    }
+`,
+			SerializerCode: `
+    fn serialize_graphnode(node: Option<Rc<RefCell<GraphNode>>>) -> Json {
+        let node = match node {
+            Some(node) => node,
+            None => return Json::Arr(Vec::new()),
+        };
+
+        let mut max_val = node.borrow().val;
+        let mut seen = HashMap::new();
+        seen.insert(max_val, Rc::clone(&node));
+        let mut queue = std::collections::VecDeque::new();
+        queue.push_back(node);
+
+        while let Some(current) = queue.pop_front() {
+            for neighbor in current.borrow().neighbors.iter() {
+                let val = neighbor.borrow().val;
+                if !seen.contains_key(&val) {
+                    seen.insert(val, Rc::clone(neighbor));
+                    queue.push_back(Rc::clone(neighbor));
+                    max_val = max_val.max(val);
+                }
+            }
+        }
+
+        let result = (1..=max_val)
+            .map(|v| match seen.get(&v) {
+                Some(n) => Json::Arr(n.borrow().neighbors.iter().map(|nb| Json::Num(nb.borrow().val.to_string())).collect()),
+                None => Json::Arr(Vec::new()),
+            })
+            .collect();
+
+        Json::Arr(result)
+    }
+`,
+		},
+	}
+}
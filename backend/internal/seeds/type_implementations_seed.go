@@ -7,24 +7,45 @@ import (
 	"gorm.io/gorm"
 )
 
+// customTypeIDs holds the ids of the seeded custom types that have implementations
+type customTypeIDs struct {
+	TreeNode  int
+	ListNode  int
+	GraphNode int
+}
+
 // SeedTypeImplementations seeds type implementations for all languages
 func SeedTypeImplementations(db *gorm.DB) error {
 	// Get custom types
-	var treeNode, listNode domain.CustomType
+	var treeNode, listNode, graphNode domain.CustomType
 	if err := db.Where("name = ?", "TreeNode").First(&treeNode).Error; err != nil {
 		return err
 	}
 	if err := db.Where("name = ?", "ListNode").First(&listNode).Error; err != nil {
 		return err
 	}
-
-	// Get languages
-	var python, javascript domain.Language
-	if err := db.Where("slug = ?", "python").First(&python).Error; err != nil {
+	if err := db.Where("name = ?", "GraphNode").First(&graphNode).Error; err != nil {
 		return err
 	}
-	if err := db.Where("slug = ?", "javascript").First(&javascript).Error; err != nil {
-		return err
+	types := customTypeIDs{TreeNode: treeNode.ID, ListNode: listNode.ID, GraphNode: graphNode.ID}
+
+	// Get languages
+	var python, javascript, java, cpp, c, golang, rust, typescript, kotlin, csharp domain.Language
+	for slug, lang := range map[string]*domain.Language{
+		"python":     &python,
+		"javascript": &javascript,
+		"java":       &java,
+		"c++":        &cpp,
+		"c":          &c,
+		"go":         &golang,
+		"rust":       &rust,
+		"typescript": &typescript,
+		"kotlin":     &kotlin,
+		"csharp":     &csharp,
+	} {
+		if err := db.Where("slug = ?", slug).First(lang).Error; err != nil {
+			return err
+		}
 	}
 
 	implementations := []domain.TypeImplementation{
@@ -240,10 +261,114 @@ function serializeListNode(head) {
     
     return result;
 }
+`,
+		},
+
+		// Python - GraphNode, as an adjacency list where node i+1 is at index i
+		{
+			CustomTypeID: graphNode.ID,
+			LanguageID:   python.ID,
+			ClassDefinition: `
+class GraphNode:
+    def __init__(self, val=0, neighbors=None):
+        self.val = val
+        self.neighbors = neighbors if neighbors is not None else []
+`,
+			DeserializerCode: `
+def deserialize_graphnode(data):
+    if not data:
+        return None
+    
+    nodes = [GraphNode(i + 1) for i in range(len(data))]
+    for i, neighbors in enumerate(data):
+        nodes[i].neighbors = [nodes[j - 1] for j in neighbors]
+    
+    return nodes[0]
+`,
+			SerializerCode: `
+def serialize_graphnode(node):
+    if not node:
+        return []
+    
+    seen = {node.val: node}
+    queue = [node]
+    
+    while queue:
+        current = queue.pop(0)
+        for neighbor in current.neighbors:
+            if neighbor.val not in seen:
+                seen[neighbor.val] = neighbor
+                queue.append(neighbor)
+    
+    return [[n.val for n in seen[v].neighbors] if v in seen else [] for v in range(1, max(seen) + 1)]
+`,
+		},
+
+		// JavaScript - GraphNode
+		{
+			CustomTypeID: graphNode.ID,
+			LanguageID:   javascript.ID,
+			ClassDefinition: `
+class GraphNode {
+    constructor(val = 0, neighbors = []) {
+        this.val = val;
+        this.neighbors = neighbors;
+    }
+}
+`,
+			DeserializerCode: `
+function deserializeGraphNode(data) {
+    if (!data || data.length === 0) {
+        return null;
+    }
+    
+    const nodes = data.map((_, i) => new GraphNode(i + 1));
+    data.forEach((neighbors, i) => {
+        nodes[i].neighbors = neighbors.map(j => nodes[j - 1]);
+    });
+    
+    return nodes[0];
+}
+`,
+			SerializerCode: `
+function serializeGraphNode(node) {
+    if (!node) return [];
+    
+    const seen = new Map([[node.val, node]]);
+    const queue = [node];
+    
+    while (queue.length > 0) {
+        const current = queue.shift();
+        for (const neighbor of current.neighbors) {
+            if (!seen.has(neighbor.val)) {
+                seen.set(neighbor.val, neighbor);
+                queue.push(neighbor);
+            }
+        }
+    }
+    
+    const result = [];
+    const maxVal = Math.max(...seen.keys());
+    for (let v = 1; v <= maxVal; v++) {
+        result.push(seen.has(v) ? seen.get(v).neighbors.map(n => n.val) : []);
+    }
+    
+    return result;
+}
 `,
 		},
 	}
 
+	// Compiled languages define the types in the harness, next to the user's code
+	implementations = append(implementations, javaTypeImplementations(types, java.ID)...)
+	implementations = append(implementations, cppTypeImplementations(types, cpp.ID)...)
+	implementations = append(implementations, cTypeImplementations(types, c.ID)...)
+	implementations = append(implementations, goTypeImplementations(types, golang.ID)...)
+	implementations = append(implementations, rustTypeImplementations(types, rust.ID)...)
+	implementations = append(implementations, typeScriptTypeImplementations(types, typescript.ID)...)
+	implementations = append(implementations, kotlinTypeImplementations(types, kotlin.ID)...)
+	implementations = append(implementations, cSharpTypeImplementations(types, csharp.ID)...)
+
 	for _, impl := range implementations {
 		// Check if exists
 		var existing domain.TypeImplementation
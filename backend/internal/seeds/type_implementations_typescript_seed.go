@@ -0,0 +1,182 @@
+package seeds
+
+import "github.com/prabalesh/loco/backend/internal/domain"
+
+// typeScriptTypeImplementations are the TypeScript custom types. They work on values
+// as JSON.parse returns them and avoid ES2015 library types such as Map, so the
+// harness compiles whatever target tsc defaults to.
+func typeScriptTypeImplementations(types customTypeIDs, languageID int) []domain.TypeImplementation {
+	return []domain.TypeImplementation{
+		// TypeScript - TreeNode
+		{
+			CustomTypeID: types.TreeNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class TreeNode {
+    val: number
+    left: TreeNode | null
+    right: TreeNode | null
+    constructor(val?: number, left?: TreeNode | null, right?: TreeNode | null) {
+        this.val = (val === undefined ? 0 : val)
+        this.left = (left === undefined ? null : left)
+        this.right = (right === undefined ? null : right)
+    }
+}
+`,
+			DeserializerCode: `
+function deserializeTreeNode(data: any[]): TreeNode | null {
+    if (!data || data.length === 0 || data[0] === null) return null;
+
+    const root = new TreeNode(data[0]);
+    const queue: TreeNode[] = [root];
+    let i = 1;
+
+    while (queue.length > 0 && i < data.length) {
+        const node = queue.shift()!;
+
+        // Left child
+        if (i < data.length && data[i] !== null) {
+            node.left = new TreeNode(data[i]);
+            queue.push(node.left);
+        }
+        i++;
+
+        // Right child
+        if (i < data.length && data[i] !== null) {
+            node.right = new TreeNode(data[i]);
+            queue.push(node.right);
+        }
+        i++;
+    }
+
+    return root;
+}
+`,
+			SerializerCode: `
+function serializeTreeNode(root: TreeNode | null): any[] {
+    if (!root) return [];
+
+    const result: any[] = [];
+    const queue: (TreeNode | null)[] = [root];
+
+    while (queue.length > 0) {
+        const node = queue.shift();
+        if (node) {
+            result.push(node.val);
+            queue.push(node.left);
+            queue.push(node.right);
+        } else {
+            result.push(null);
+        }
+    }
+
+    // Remove trailing nulls
+    while (result.length > 0 && result[result.length - 1] === null) {
+        result.pop();
+    }
+
+    return result;
+}
+`,
+		},
+
+		// TypeScript - ListNode
+		{
+			CustomTypeID: types.ListNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class ListNode {
+    val: number
+    next: ListNode | null
+    constructor(val?: number, next?: ListNode | null) {
+        this.val = (val === undefined ? 0 : val)
+        this.next = (next === undefined ? null : next)
+    }
+}
+`,
+			DeserializerCode: `
+function deserializeListNode(data: any[]): ListNode | null {
+    const dummy = new ListNode(0);
+    let current = dummy;
+
+    for (let i = 0; i < data.length; i++) {
+        current.next = new ListNode(data[i]);
+        current = current.next;
+    }
+
+    return dummy.next;
+}
+`,
+			SerializerCode: `
+function serializeListNode(head: ListNode | null): any[] {
+    const result: any[] = [];
+
+    for (let current = head; current; current = current.next) {
+        result.push(current.val);
+    }
+
+    return result;
+}
+`,
+		},
+
+		// TypeScript - GraphNode
+		{
+			CustomTypeID: types.GraphNode,
+			LanguageID:   languageID,
+			ClassDefinition: `
+class GraphNode {
+    val: number
+    neighbors: GraphNode[]
+    constructor(val?: number, neighbors?: GraphNode[]) {
+        this.val = (val === undefined ? 0 : val)
+        this.neighbors = (neighbors === undefined ? [] : neighbors)
+    }
+}
+`,
+			DeserializerCode: `
+function deserializeGraphNode(data: any[]): GraphNode | null {
+    if (!data || data.length === 0) return null;
+
+    const nodes: GraphNode[] = [];
+    for (let i = 0; i < data.length; i++) {
+        nodes.push(new GraphNode(i + 1));
+    }
+    for (let i = 0; i < data.length; i++) {
+        nodes[i].neighbors = data[i].map((j: number) => nodes[j - 1]);
+    }
+
+    return nodes[0];
+}
+`,
+			SerializerCode: `
+function serializeGraphNode(node: GraphNode | null): any[] {
+    if (!node) return [];
+
+    const seen: { [val: number]: GraphNode } = {};
+    seen[node.val] = node;
+    const queue: GraphNode[] = [node];
+    let maxVal = node.val;
+
+    while (queue.length > 0) {
+        const current = queue.shift()!;
+        for (const neighbor of current.neighbors) {
+            if (!seen[neighbor.val]) {
+                seen[neighbor.val] = neighbor;
+                queue.push(neighbor);
+                maxVal = Math.max(maxVal, neighbor.val);
+            }
+        }
+    }
+
+    const result: number[][] = [];
+    for (let v = 1; v <= maxVal; v++) {
+        result.push(seen[v] ? seen[v].neighbors.map(n => n.val) : []);
+    }
+
+    return result;
+}
+`,
+		},
+	}
+}
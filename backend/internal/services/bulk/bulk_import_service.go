@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
 	"github.com/prabalesh/loco/backend/internal/services/problem"
 	"github.com/prabalesh/loco/backend/internal/services/validation"
 	"gorm.io/gorm"
@@ -36,12 +37,26 @@ type ProblemImportData struct {
 	Difficulty              string                   `json:"difficulty"`
 	CategoryIDs             []int                    `json:"category_ids"`
 	TagIDs                  []int                    `json:"tag_ids"`
+	JudgeMode               string                   `json:"judge_mode,omitempty"`
+	InputFormat             string                   `json:"input_format,omitempty"`
+	OutputFormat            string                   `json:"output_format,omitempty"`
 	FunctionName            string                   `json:"function_name"`
 	ReturnType              domain.GenericType       `json:"return_type"`
 	Parameters              []domain.SchemaParameter `json:"parameters"`
+	Design                  *domain.DesignSchema     `json:"design,omitempty"`
 	ValidationType          string                   `json:"validation_type"`
 	ExpectedTimeComplexity  string                   `json:"expected_time_complexity"`
 	ExpectedSpaceComplexity string                   `json:"expected_space_complexity"`
+	CheckerLanguage         string                   `json:"checker_language,omitempty"`
+	CheckerCode             string                   `json:"checker_code,omitempty"`
+	InteractorLanguage      string                   `json:"interactor_language,omitempty"`
+	InteractorCode          string                   `json:"interactor_code,omitempty"`
+	QueryLimit              int                      `json:"query_limit,omitempty"`
+	GeneratorLanguage       string                   `json:"generator_language,omitempty"`
+	GeneratorCode           string                   `json:"generator_code,omitempty"`
+	ValidatorLanguage       string                   `json:"validator_language,omitempty"`
+	ValidatorCode           string                   `json:"validator_code,omitempty"`
+	Subtasks                domain.Subtasks          `json:"subtasks,omitempty"`
 	TestCases               []problem.TestCaseInput  `json:"test_cases"`
 	ReferenceSolution       *ReferenceSolutionData   `json:"reference_solution,omitempty"`
 }
@@ -182,18 +197,34 @@ func (s *BulkImportService) validateAllProblems(problems []ProblemImportData) ma
 		if p.Difficulty != "easy" && p.Difficulty != "medium" && p.Difficulty != "hard" {
 			problemErrors = append(problemErrors, "difficulty must be easy, medium, or hard")
 		}
-		if p.FunctionName == "" {
-			problemErrors = append(problemErrors, "function_name is required")
-		}
-		if p.ReturnType == "" {
-			problemErrors = append(problemErrors, "return_type is required")
-		}
-		if len(p.Parameters) == 0 {
-			problemErrors = append(problemErrors, "at least one parameter is required")
+		// Stdio and interactive problems are whole programs without a signature, design
+		// problems have their design instead
+		switch p.JudgeMode {
+		case domain.JudgeModeStdio, domain.JudgeModeInteractive:
+		case domain.JudgeModeDesign:
+			if p.Design == nil {
+				problemErrors = append(problemErrors, "design is required for design problems")
+			}
+		default:
+			if p.FunctionName == "" {
+				problemErrors = append(problemErrors, "function_name is required")
+			}
+			if p.ReturnType == "" {
+				problemErrors = append(problemErrors, "return_type is required")
+			}
+			if len(p.Parameters) == 0 {
+				problemErrors = append(problemErrors, "at least one parameter is required")
+			}
 		}
 		if len(p.TestCases) == 0 {
 			problemErrors = append(problemErrors, "at least one test case is required")
 		}
+		if p.ValidationType == domain.ValidationTypeCustom && (p.CheckerLanguage == "" || p.CheckerCode == "") {
+			problemErrors = append(problemErrors, "checker_language and checker_code are required for CUSTOM validation")
+		}
+		if p.JudgeMode == domain.JudgeModeInteractive && (p.InteractorLanguage == "" || p.InteractorCode == "") {
+			problemErrors = append(problemErrors, "interactor_language and interactor_code are required for interactive problems")
+		}
 
 		// Check for at least one public test case
 		hasPublic := false
@@ -223,12 +254,26 @@ func (s *BulkImportService) convertToCreateRequest(data ProblemImportData) probl
 		Difficulty:              data.Difficulty,
 		CategoryIDs:             data.CategoryIDs,
 		TagIDs:                  data.TagIDs,
+		JudgeMode:               data.JudgeMode,
+		InputFormat:             data.InputFormat,
+		OutputFormat:            data.OutputFormat,
 		FunctionName:            data.FunctionName,
 		ReturnType:              data.ReturnType,
 		Parameters:              data.Parameters,
+		Design:                  data.Design,
 		ValidationType:          data.ValidationType,
 		ExpectedTimeComplexity:  data.ExpectedTimeComplexity,
 		ExpectedSpaceComplexity: data.ExpectedSpaceComplexity,
+		CheckerLanguage:         data.CheckerLanguage,
+		CheckerCode:             data.CheckerCode,
+		InteractorLanguage:      data.InteractorLanguage,
+		InteractorCode:          data.InteractorCode,
+		QueryLimit:              data.QueryLimit,
+		GeneratorLanguage:       data.GeneratorLanguage,
+		GeneratorCode:           data.GeneratorCode,
+		ValidatorLanguage:       data.ValidatorLanguage,
+		ValidatorCode:           data.ValidatorCode,
+		Subtasks:                data.Subtasks,
 		TestCases:               data.TestCases,
 	}
 }
@@ -246,6 +291,7 @@ func (s *BulkImportService) validateReferenceSolution(problemID int, refSol Refe
 		ProblemID:    problemID,
 		LanguageSlug: refSol.LanguageSlug,
 		Code:         refSol.Code,
+		Lane:         queue.LaneBulk, // keep bulk validations out of the way of user traffic
 	}
 
 	_, _, err := s.validationService.SaveReferenceSolution(validateReq, language.ID, adminID)
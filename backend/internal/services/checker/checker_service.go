@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+)
+
+// Limits for a single checker run; checkers are trusted but still sandboxed
+const (
+	CheckerTimeLimitMs   = 5000
+	CheckerMemoryLimitMb = 256
+	maxMessageBytes      = 1024
+)
+
+// Exit codes of the checker protocol
+const (
+	exitAccepted = 0
+	exitRejected = 1
+)
+
+// ErrCheckerFailed means the checker itself is broken (didn't compile, crashed or
+// answered with an unknown exit code), which is the problem author's fault rather
+// than the submission's
+var ErrCheckerFailed = errors.New("checker failed")
+
+// Verdict is a checker's judgement of one answer
+type Verdict struct {
+	Accepted bool
+	Message  string
+}
+
+// checkerInput is what a checker reads from stdin: the test input, the author's
+// expected answer and the submission's answer, each as the JSON value itself, or as
+// a string of the raw text for stdio problems
+type checkerInput struct {
+	Input    json.RawMessage `json:"input"`
+	Expected json.RawMessage `json:"expected"`
+	Actual   json.RawMessage `json:"actual"`
+}
+
+// CheckerService runs problem checkers. A checker is a plain program in any
+// supported language that reads one checkerInput as JSON from stdin, exits 0 to
+// accept or 1 to reject the answer and may print a message explaining why.
+type CheckerService struct {
+	executor executor.Executor
+}
+
+func NewCheckerService(exec executor.Executor) *CheckerService {
+	return &CheckerService{executor: exec}
+}
+
+// Check judges one answer. Errors wrap ErrCheckerFailed when the checker is at
+// fault; any other error comes from the executor.
+func (s *CheckerService) Check(ctx context.Context, problem *domain.Problem, tc *domain.TestCase, actual string) (*Verdict, error) {
+	if !problem.UsesChecker() {
+		return nil, fmt.Errorf("%w: problem %d has no checker", ErrCheckerFailed, problem.ID)
+	}
+
+	encode := rawJSON
+	if problem.IsWholeProgram() {
+		encode = quoteText
+	}
+	stdin, err := json.Marshal(checkerInput{
+		Input:    encode(tc.Input),
+		Expected: encode(tc.ExpectedOutput),
+		Actual:   encode(actual),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode checker input: %w", err)
+	}
+
+	res, err := s.executor.Execute(ctx, &executor.Request{
+		ProblemID:     problem.ID,
+		Language:      *problem.CheckerLanguage,
+		Code:          *problem.CheckerCode,
+		Stdin:         string(stdin),
+		StdinKeys:     tc.StoredKeys(),
+		TimeLimitMs:   CheckerTimeLimitMs,
+		MemoryLimitMb: CheckerMemoryLimitMb,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := executor.TruncateOutput(strings.TrimSpace(res.Output), maxMessageBytes)
+	switch {
+	case res.CompileFailed:
+		return nil, fmt.Errorf("%w: compilation error: %s", ErrCheckerFailed, executor.TruncateOutput(res.Error, maxMessageBytes))
+	case res.Termination != executor.TerminationNone:
+		return nil, fmt.Errorf("%w: %s", ErrCheckerFailed, executor.TruncateOutput(res.Error, maxMessageBytes))
+	case res.ExitCode == exitAccepted:
+		return &Verdict{Accepted: true, Message: message}, nil
+	case res.ExitCode == exitRejected:
+		return &Verdict{Accepted: false, Message: message}, nil
+	default:
+		return nil, fmt.Errorf("%w: exit code %d: %s", ErrCheckerFailed, res.ExitCode, executor.TruncateOutput(res.Error, maxMessageBytes))
+	}
+}
+
+// CheckResults runs the checker over every result the harness let through and
+// fails the ones it rejects, keeping its message in the result's Error. results
+// and testCases are matched by position; errors name the failing result's TestID.
+func (s *CheckerService) CheckResults(ctx context.Context, problem *domain.Problem, testCases []domain.TestCase, results []domain.TestCaseResult) error {
+	for i := range results {
+		if i >= len(testCases) || results[i].Status != domain.TestStatusPassed {
+			continue
+		}
+
+		verdict, err := s.Check(ctx, problem, &testCases[i], results[i].ActualOutput)
+		if err != nil {
+			return fmt.Errorf("test %d: %w", results[i].TestID, err)
+		}
+		if !verdict.Accepted {
+			results[i].Status = domain.TestStatusFailed
+			results[i].Error = verdict.Message
+		}
+	}
+	return nil
+}
+
+// rawJSON passes valid JSON through untouched and quotes anything else as a string
+func rawJSON(s string) json.RawMessage {
+	if s = strings.TrimSpace(s); s != "" && json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	return quoteText(s)
+}
+
+// quoteText passes text as a JSON string, whatever it looks like
+func quoteText(s string) json.RawMessage {
+	quoted, _ := json.Marshal(s)
+	return quoted
+}
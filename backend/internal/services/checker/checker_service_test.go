@@ -0,0 +1,126 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+)
+
+func customProblem() *domain.Problem {
+	language, code := "python", "print('checker')"
+	return &domain.Problem{ID: 7, ValidationType: domain.ValidationTypeCustom, CheckerLanguage: &language, CheckerCode: &code}
+}
+
+func TestCheckPassesInputExpectedAndActual(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{Output: "  close enough\n"}, nil
+	})
+	svc := NewCheckerService(exec)
+
+	tc := &domain.TestCase{Input: `[1, 2]`, ExpectedOutput: `3.0`}
+	verdict, err := svc.Check(context.Background(), customProblem(), tc, "not json")
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if !verdict.Accepted || verdict.Message != "close enough" {
+		t.Errorf("Unexpected verdict %+v", verdict)
+	}
+
+	req := exec.Requests()[0]
+	if req.Language != "python" || req.Code != "print('checker')" || req.TimeLimitMs != CheckerTimeLimitMs {
+		t.Errorf("Unexpected checker request %+v", req)
+	}
+	var in map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Stdin), &in); err != nil {
+		t.Fatalf("Checker stdin is not JSON: %v", err)
+	}
+	if in["expected"] != 3.0 || in["actual"] != "not json" || len(in["input"].([]interface{})) != 2 {
+		t.Errorf("Unexpected checker input %v", in)
+	}
+}
+
+func TestCheckRejects(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{Output: "off by one", ExitCode: 1}, nil
+	})
+
+	verdict, err := NewCheckerService(exec).Check(context.Background(), customProblem(), &domain.TestCase{Input: "1", ExpectedOutput: "2"}, "3")
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if verdict.Accepted || verdict.Message != "off by one" {
+		t.Errorf("Unexpected verdict %+v", verdict)
+	}
+}
+
+func TestCheckBrokenChecker(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *executor.Result
+	}{
+		{"compile error", &executor.Result{CompileFailed: true, ExitCode: 1, Error: "syntax error"}},
+		{"unknown exit code", &executor.Result{ExitCode: 3}},
+		{"timeout", &executor.Result{ExitCode: -1, Termination: executor.TerminationTimeLimit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+				return tt.result, nil
+			})
+			_, err := NewCheckerService(exec).Check(context.Background(), customProblem(), &domain.TestCase{}, "1")
+			if !errors.Is(err, ErrCheckerFailed) {
+				t.Errorf("Expected ErrCheckerFailed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckResultsOnlyJudgesPassedResults(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{Output: "nope", ExitCode: 1}, nil
+	})
+
+	results := []domain.TestCaseResult{
+		{TestID: 1, Status: domain.TestStatusPassed},
+		{TestID: 2, Status: domain.TestStatusTimeout},
+	}
+	err := NewCheckerService(exec).CheckResults(context.Background(), customProblem(), make([]domain.TestCase, 2), results)
+	if err != nil {
+		t.Fatalf("CheckResults returned error: %v", err)
+	}
+	if results[0].Status != domain.TestStatusFailed || results[0].Error != "nope" {
+		t.Errorf("Expected test 1 to be rejected, got %+v", results[0])
+	}
+	if results[1].Status != domain.TestStatusTimeout {
+		t.Errorf("Expected test 2 to be left alone, got %+v", results[1])
+	}
+	if n := len(exec.Requests()); n != 1 {
+		t.Errorf("Expected the checker to run once, ran %d times", n)
+	}
+}
+
+func TestCheckStdioProblemPassesText(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{}, nil
+	})
+	problem := customProblem()
+	problem.JudgeMode = domain.JudgeModeStdio
+
+	tc := &domain.TestCase{Input: "2\n1 2\n", ExpectedOutput: "3"}
+	if _, err := NewCheckerService(exec).Check(context.Background(), problem, tc, "[3]\n"); err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	var in map[string]interface{}
+	if err := json.Unmarshal([]byte(exec.Requests()[0].Stdin), &in); err != nil {
+		t.Fatalf("Checker stdin is not JSON: %v", err)
+	}
+	if in["input"] != "2\n1 2\n" || in["expected"] != "3" || in["actual"] != "[3]\n" {
+		t.Errorf("Expected text to be passed as strings, got %v", in)
+	}
+}
@@ -31,25 +31,13 @@ func NewBoilerplateService(
 }
 
 func (s *BoilerplateService) GenerateAllBoilerplatesForProblem(problem *domain.Problem) error {
-	if problem.FunctionName == nil || *problem.FunctionName == "" {
-		return fmt.Errorf("problem has no function name")
+	// Whole-program problems have no stub or harness
+	if problem.IsWholeProgram() {
+		return nil
 	}
-	if problem.ReturnType == nil || *problem.ReturnType == "" {
-		return fmt.Errorf("problem has no return type")
-	}
-	if problem.Parameters == nil {
-		return fmt.Errorf("problem has no parameters")
-	}
-
-	var parameters []domain.SchemaParameter
-	if err := json.Unmarshal([]byte(*problem.Parameters), &parameters); err != nil {
-		return fmt.Errorf("failed to parse parameters: %w", err)
-	}
-
-	signature := domain.ProblemSchema{
-		FunctionName: *problem.FunctionName,
-		ReturnType:   domain.GenericType(*problem.ReturnType),
-		Parameters:   parameters,
+	signature, err := problem.Schema()
+	if err != nil {
+		return err
 	}
 
 	languages, err := s.languageRepo.ListActive()
@@ -64,6 +52,10 @@ func (s *BoilerplateService) GenerateAllBoilerplatesForProblem(problem *domain.P
 
 	var errors []string
 	for _, lang := range languages {
+		// A design problem can't be solved in a language without classes
+		if signature.Design != nil && !s.codeGenService.SupportsDesign(lang.Slug) {
+			continue
+		}
 		err := s.GenerateBoilerplateForLanguage(problem.ID, lang.ID, signature, lang.Slug, testCases, problem.ValidationType)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", lang.Name, err))
@@ -87,6 +79,7 @@ func (s *BoilerplateService) GenerateBoilerplateForLanguage(problemID, languageI
 	if err != nil {
 		return fmt.Errorf("failed to generate test harness template: %w", err)
 	}
+	harnessVersion := s.codeGenService.HarnessVersion(languageSlug)
 
 	exists, err := s.boilerplateRepo.Exists(problemID, languageID)
 	if err != nil {
@@ -101,6 +94,7 @@ func (s *BoilerplateService) GenerateBoilerplateForLanguage(problemID, languageI
 		existing.StubCode = stubCode
 		harnessJSON, _ := json.Marshal(map[string]string{languageSlug: harnessTemplate})
 		existing.TestHarnessTemplate = datatypes.JSON(harnessJSON)
+		existing.HarnessVersion = harnessVersion
 		return s.boilerplateRepo.Update(existing)
 	}
 
@@ -110,6 +104,7 @@ func (s *BoilerplateService) GenerateBoilerplateForLanguage(problemID, languageI
 		LanguageID:          languageID,
 		StubCode:            stubCode,
 		TestHarnessTemplate: datatypes.JSON(harnessJSON),
+		HarnessVersion:      harnessVersion,
 	}
 
 	return s.boilerplateRepo.Create(boilerplate)
@@ -146,6 +141,16 @@ func (s *BoilerplateService) GetTestHarnessTemplate(problemID, languageID int) (
 	return "", fmt.Errorf("no template found in JSON")
 }
 
+// GetHarnessVersion names the template version the stored harness of a problem's
+// language was rendered from, e.g. "python.v1"
+func (s *BoilerplateService) GetHarnessVersion(problemID, languageID int) (string, error) {
+	bp, err := s.boilerplateRepo.GetByProblemAndLanguage(problemID, languageID)
+	if err != nil {
+		return "", err
+	}
+	return bp.HarnessVersion, nil
+}
+
 func (s *BoilerplateService) InjectUserCodeIntoHarness(template, userCode string) string {
 	return strings.Replace(template, "{USER_CODE}", userCode, 1)
 }
@@ -0,0 +1,383 @@
+package codegen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+type fakeCustomTypeRepo struct {
+	names map[string]bool
+}
+
+func (r *fakeCustomTypeRepo) Create(customType *domain.CustomType) error { return nil }
+
+func (r *fakeCustomTypeRepo) GetByName(name string) (*domain.CustomType, error) {
+	if !r.names[name] {
+		return nil, errors.New("record not found")
+	}
+	return &domain.CustomType{Name: name}, nil
+}
+
+func (r *fakeCustomTypeRepo) GetAll() ([]domain.CustomType, error) { return nil, nil }
+
+// fakeTypeImplRepo answers with placeholder code naming the type and language, so
+// tests can see where each part ends up
+type fakeTypeImplRepo struct{}
+
+func (r *fakeTypeImplRepo) Create(impl *domain.TypeImplementation) error { return nil }
+
+func (r *fakeTypeImplRepo) GetByTypeAndLanguage(customTypeID, languageID int) (*domain.TypeImplementation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeTypeImplRepo) GetByTypeAndLanguageSlug(typeName, languageSlug string) (*domain.TypeImplementation, error) {
+	return &domain.TypeImplementation{
+		ClassDefinition:  "CLASS " + typeName + " " + languageSlug,
+		DeserializerCode: "DESERIALIZER " + typeName + " " + languageSlug,
+		SerializerCode:   "SERIALIZER " + typeName + " " + languageSlug,
+	}, nil
+}
+
+func newTestCodeGenService() *CodeGenService {
+	return NewCodeGenService(&fakeCustomTypeRepo{names: map[string]bool{"TreeNode": true, "ListNode": true}}, &fakeTypeImplRepo{})
+}
+
+var invertTreeSignature = domain.ProblemSchema{
+	FunctionName: "invertTree",
+	Parameters:   []domain.SchemaParameter{{Name: "root", Type: "TreeNode"}},
+	ReturnType:   "TreeNode",
+}
+
+func TestGenerateStubCodeCustomTypes(t *testing.T) {
+	svc := newTestCodeGenService()
+	tests := map[string]string{
+		"java":       "public TreeNode invertTree(TreeNode root)",
+		"c++":        "TreeNode* invertTree(TreeNode* root)",
+		"c":          "struct TreeNode* invertTree(struct TreeNode* root)",
+		"go":         "func invertTree(root *TreeNode) *TreeNode",
+		"rust":       "pub fn invert_tree(root: Option<Rc<RefCell<TreeNode>>>) -> Option<Rc<RefCell<TreeNode>>>",
+		"typescript": "function invertTree(root: TreeNode | null): TreeNode | null",
+		"kotlin":     "fun invertTree(root: TreeNode?): TreeNode?",
+		"csharp":     "public TreeNode InvertTree(TreeNode root)",
+	}
+
+	for lang, signature := range tests {
+		t.Run(lang, func(t *testing.T) {
+			stub, err := svc.GenerateStubCode(invertTreeSignature, lang)
+			if err != nil {
+				t.Fatalf("GenerateStubCode failed: %v", err)
+			}
+			if !strings.Contains(stub, signature) {
+				t.Errorf("Expected stub to contain %q, got:\n%s", signature, stub)
+			}
+			// The harness defines the type, so the stub only shows it
+			if !strings.Contains(stub, "// CLASS TreeNode "+lang) {
+				t.Errorf("Expected the definition as a comment, got:\n%s", stub)
+			}
+		})
+	}
+}
+
+func TestGenerateTestHarnessCustomTypes(t *testing.T) {
+	svc := newTestCodeGenService()
+	tests := map[string][]string{
+		"java":       {"root = deserializeTreeNode(inputObj.array.get(0));", "return serializeTreeNode(sol.invertTree(root));", "expected = expectedVal;"},
+		"c++":        {"TreeNode* root = deserializeTreeNode(inputObj.array[0]);", "auto res = serializeTreeNode(sol.invertTree(root));", "JsonValue expected = (expectedVal);"},
+		"c":          {"struct TreeNode* root = deserializeTreeNode(inputObj.array[0]);", "char* json = serializeTreeNode(res);"},
+		"go":         {"root := deserializeTreeNode(input[0])", "resChan <- serializeTreeNode(invertTree(root))"},
+		"rust":       {"let root = deserialize_treenode(&args[0]);", "serialize_treenode(Solution::invert_tree(root))"},
+		"typescript": {"const root = deserializeTreeNode(input[0]);", "return serializeTreeNode(invertTree(root));"},
+		"kotlin":     {"val root = deserializeTreeNode(args[0])", "return serializeTreeNode(Solution().invertTree(root))"},
+		"csharp":     {"var root = DeserializeTreeNode(args[0]);", "return SerializeTreeNode(new Solution().InvertTree(root));"},
+	}
+
+	for lang, wants := range tests {
+		t.Run(lang, func(t *testing.T) {
+			harness, err := svc.GenerateTestHarness(invertTreeSignature, "{USER_CODE}", lang, nil, "EXACT")
+			if err != nil {
+				t.Fatalf("GenerateTestHarness failed: %v", err)
+			}
+			for _, part := range []string{"CLASS", "DESERIALIZER", "SERIALIZER"} {
+				if !strings.Contains(harness, part+" TreeNode "+lang) {
+					t.Errorf("Expected harness to include the %s of TreeNode", strings.ToLower(part))
+				}
+			}
+			for _, want := range wants {
+				if !strings.Contains(harness, want) {
+					t.Errorf("Expected harness to contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestIdentifyCustomTypesUsesRepository(t *testing.T) {
+	svc := newTestCodeGenService()
+	sig := domain.ProblemSchema{
+		FunctionName: "merge",
+		Parameters: []domain.SchemaParameter{
+			{Name: "a", Type: "ListNode"},
+			{Name: "b", Type: "ListNode"},
+			{Name: "k", Type: domain.TypeInteger},
+			{Name: "x", Type: "Interval"}, // not registered
+		},
+		ReturnType: "ListNode",
+	}
+
+	got := svc.identifyCustomTypes(sig)
+	if len(got) != 1 || got[0] != "ListNode" {
+		t.Errorf("Expected [ListNode], got %v", got)
+	}
+
+	// Without a repository nothing is custom
+	if got := NewCodeGenService(nil, nil).identifyCustomTypes(sig); len(got) != 0 {
+		t.Errorf("Expected no custom types, got %v", got)
+	}
+}
+
+func TestTypeMappings(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	tests := []struct {
+		typ                          domain.GenericType
+		python, java, cpp, c, golang string
+	}{
+		{domain.TypeIntegerArray, "List[int]", "int[]", "vector<int>", "int*", "[]int"},
+		{domain.TypeIntegerMatrix, "List[List[int]]", "int[][]", "vector<vector<int>>", "int**", "[][]int"},
+		{domain.TypeLong, "int", "long", "long long", "long long", "int64"},
+		{domain.TypeChar, "str", "char", "char", "char", "byte"},
+		{"int?", "Optional[int]", "Integer", "optional<int>", "int", "*int"},
+		{"map<string,list<int>>", "Dict[str, List[int]]", "Map<String, int[]>", "map<string, vector<int>>", "", "map[string][]int"},
+		{"list<map<int,bool>>", "List[Dict[int, bool]]", "List<Map<Integer, Boolean>>", "vector<map<int, bool>>", "", "[]map[int]bool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			got := map[string][2]string{
+				"python": {svc.mapTypeToPython(tt.typ), tt.python},
+				"java":   {svc.mapTypeToJava(tt.typ), tt.java},
+				"c++":    {svc.mapTypeToCpp(tt.typ), tt.cpp},
+				"go":     {svc.mapTypeToGo(tt.typ), tt.golang},
+			}
+			if tt.c != "" {
+				got["c"] = [2]string{svc.mapTypeToC(tt.typ), tt.c}
+			}
+			for lang, pair := range got {
+				if pair[0] != pair[1] {
+					t.Errorf("%s: expected %s, got %s", lang, pair[1], pair[0])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatLiterals(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	grid := []interface{}{[]interface{}{1.0, 2.0}, []interface{}{3.0}}
+
+	if got := svc.formatCppLiteral(grid, domain.TypeIntegerMatrix); got != "{{1, 2}, {3}}" {
+		t.Errorf("Unexpected C++ literal %s", got)
+	}
+	if got := svc.formatJavaLiteral(grid, domain.TypeIntegerMatrix); got != "new int[][]{new int[]{1, 2}, new int[]{3}}" {
+		t.Errorf("Unexpected Java literal %s", got)
+	}
+	if got := svc.formatCLiteral([]interface{}{"a", "b\""}, domain.TypeStringArray); got != `{"a", "b\""}` {
+		t.Errorf("Unexpected C literal %s", got)
+	}
+
+	counts := map[string]interface{}{"b": 2.0, "a": nil}
+	if got := svc.formatCppLiteral(counts, "map<char,int?>"); got != "{{'a', nullopt}, {'b', 2}}" {
+		t.Errorf("Unexpected C++ map literal %s", got)
+	}
+	if got := svc.formatJavaLiteral(counts, "map<char,long?>"); got != "new HashMap<Character, Long>() {{ put('a', null); put('b', 2L); }}" {
+		t.Errorf("Unexpected Java map literal %s", got)
+	}
+}
+
+func TestGenerateCRejectsUnsupportedTypes(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	sig := domain.ProblemSchema{
+		FunctionName: "count",
+		Parameters:   []domain.SchemaParameter{{Name: "words", Type: domain.TypeStringArray}},
+		ReturnType:   "map<string,int>",
+	}
+
+	if _, err := svc.GenerateStubCode(sig, "c"); err == nil {
+		t.Error("Expected the C stub to reject a map")
+	}
+	if _, err := svc.GenerateTestHarness(sig, "{USER_CODE}", "c", nil, "EXACT"); err == nil {
+		t.Error("Expected the C harness to reject a map")
+	}
+}
+
+func TestGenerateStubCodeMatrixSizes(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	sig := domain.ProblemSchema{
+		FunctionName: "transpose",
+		Parameters:   []domain.SchemaParameter{{Name: "grid", Type: domain.TypeIntegerMatrix}},
+		ReturnType:   domain.TypeIntegerMatrix,
+	}
+
+	stub, err := svc.GenerateStubCode(sig, "c")
+	if err != nil {
+		t.Fatalf("GenerateStubCode failed: %v", err)
+	}
+	want := "int** transpose(int** grid, int gridSize, int* gridColSize, int* returnSize, int** returnColumnSizes)"
+	if !strings.Contains(stub, want) {
+		t.Errorf("Expected stub to contain %q, got:\n%s", want, stub)
+	}
+}
+
+func TestGeneratorTypeMappings(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	tests := []struct {
+		typ                              domain.GenericType
+		rust, typescript, kotlin, csharp string
+	}{
+		{domain.TypeIntegerArray, "Vec<i32>", "number[]", "IntArray", "int[]"},
+		{domain.TypeStringMatrix, "Vec<Vec<String>>", "string[][]", "Array<Array<String>>", "string[][]"},
+		{domain.TypeLong, "i64", "number", "Long", "long"},
+		{domain.TypeChar, "char", "string", "Char", "char"},
+		{"list<int?>", "Vec<Option<i32>>", "(number | null)[]", "Array<Int?>", "int?[]"},
+		{"map<string,list<int>>", "HashMap<String, Vec<i32>>", "Record<string, number[]>", "Map<String, IntArray>", "Dictionary<string, int[]>"},
+		{"list<map<int,bool>>", "Vec<HashMap<i32, bool>>", "Record<number, boolean>[]", "List<Map<Int, Boolean>>", "Dictionary<int, bool>[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			got := map[string][2]string{
+				"rust":       {svc.mapTypeToRust(tt.typ), tt.rust},
+				"typescript": {svc.mapTypeToTypeScript(tt.typ), tt.typescript},
+				"kotlin":     {svc.mapTypeToKotlin(tt.typ), tt.kotlin},
+				"csharp":     {svc.mapTypeToCSharp(tt.typ), tt.csharp},
+			}
+			for lang, pair := range got {
+				if pair[0] != pair[1] {
+					t.Errorf("%s: expected %s, got %s", lang, pair[1], pair[0])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateStubCodeNames(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	sig := domain.ProblemSchema{
+		FunctionName: "maxSubArray",
+		Parameters:   []domain.SchemaParameter{{Name: "numList", Type: domain.TypeIntegerArray}},
+		ReturnType:   domain.TypeInteger,
+	}
+	tests := map[string]string{
+		"rust":   "pub fn max_sub_array(num_list: Vec<i32>) -> i32",
+		"csharp": "public int MaxSubArray(int[] numList)",
+	}
+
+	for lang, want := range tests {
+		stub, err := svc.GenerateStubCode(sig, lang)
+		if err != nil {
+			t.Fatalf("GenerateStubCode(%s) failed: %v", lang, err)
+		}
+		if !strings.Contains(stub, want) {
+			t.Errorf("Expected %s stub to contain %q, got:\n%s", lang, want, stub)
+		}
+	}
+}
+
+func TestGenerateDesignStub(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	tests := map[string][]string{
+		"python": {"class TimeMap:", "    def __init__(self):", "    def get(self, key: str, timestamp: int) -> str:", "    def set(self, key: str, value: str, timestamp: int) -> None:"},
+		"java":   {"class TimeMap {", "    public TimeMap() {", "    public void set(String key, String value, int timestamp) {", "    public String[] history(String key) {"},
+		"go":     {"func Constructor() TimeMap {", "func (this *TimeMap) Set(key string, value string, timestamp int) {", "func (this *TimeMap) Get(key string, timestamp int) string {"},
+		"rust":   {"    fn new() -> Self {", "    fn get(&mut self, key: String, timestamp: i32) -> String {", "    fn set(&mut self, key: String, value: String, timestamp: i32) {"},
+		"csharp": {"    public TimeMap() {", "    public string[] History(string key) {"},
+	}
+
+	for lang, wants := range tests {
+		stub, err := svc.GenerateStubCode(timeMapSignature, lang)
+		if err != nil {
+			t.Fatalf("GenerateStubCode(%s) failed: %v", lang, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(stub, want) {
+				t.Errorf("Expected %s stub to contain %q, got:\n%s", lang, want, stub)
+			}
+		}
+	}
+
+	if _, err := svc.GenerateStubCode(timeMapSignature, "c"); err == nil {
+		t.Error("Expected C to reject a design problem")
+	}
+	if _, err := svc.GenerateTestHarness(timeMapSignature, "{USER_CODE}", "c", nil, "EXACT"); err == nil {
+		t.Error("Expected the C harness to reject a design problem")
+	}
+	noMethods := domain.ProblemSchema{Design: &domain.DesignSchema{ClassName: "Empty"}}
+	if _, err := svc.GenerateStubCode(noMethods, "python"); err == nil {
+		t.Error("Expected a class without methods to be rejected")
+	}
+}
+
+type fakeGenerator struct{}
+
+func (fakeGenerator) GenerateStub(sig domain.ProblemSchema, customTypes []string) (string, error) {
+	return "STUB " + sig.FunctionName, nil
+}
+
+func (fakeGenerator) GenerateHarness(sig domain.ProblemSchema, userCode string, testCases []domain.TestCase, validationType string) (string, error) {
+	return "HARNESS " + userCode, nil
+}
+
+func TestRegisterLanguage(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	sig := domain.ProblemSchema{
+		FunctionName: "solve",
+		Parameters:   []domain.SchemaParameter{{Name: "n", Type: domain.TypeInteger}},
+		ReturnType:   domain.TypeInteger,
+	}
+
+	want := []string{"c", "c++", "csharp", "go", "java", "javascript", "kotlin", "python", "rust", "typescript"}
+	if got := svc.Languages(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected built-in languages %v, got %v", want, got)
+	}
+
+	if _, err := svc.GenerateStubCode(sig, "zig"); err == nil {
+		t.Error("Expected an unregistered language to be rejected")
+	}
+
+	svc.RegisterLanguage("zig", fakeGenerator{})
+	if stub, err := svc.GenerateStubCode(sig, "zig"); err != nil || stub != "STUB solve" {
+		t.Errorf("Expected the registered stub, got %q, %v", stub, err)
+	}
+	if harness, err := svc.GenerateTestHarness(sig, "code", "zig", nil, "EXACT"); err != nil || harness != "HARNESS code" {
+		t.Errorf("Expected the registered harness, got %q, %v", harness, err)
+	}
+}
+
+func TestHarnessVersion(t *testing.T) {
+	svc := NewCodeGenService(nil, nil)
+	// C has no design harness, so it is a template behind the others
+	latest := map[string]string{"c": "c.v2"}
+	for _, lang := range svc.Languages() {
+		want, ok := latest[lang]
+		if !ok {
+			want = strings.ReplaceAll(lang, "+", "p") + ".v3"
+		}
+		if got := svc.HarnessVersion(lang); got != want {
+			t.Errorf("Expected %s to be rendered from %s, got %q", lang, want, got)
+		}
+		// Every built-in harness prints the nonce with its result
+		if !HarnessTakesNonce(svc.HarnessVersion(lang)) {
+			t.Errorf("Expected the %s harness to take a nonce", lang)
+		}
+	}
+
+	svc.RegisterLanguage("zig", fakeGenerator{})
+	if got := svc.HarnessVersion("zig"); got != "" {
+		t.Errorf("Expected no version for an unversioned generator, got %q", got)
+	}
+	if got := svc.HarnessVersion("cobol"); got != "" {
+		t.Errorf("Expected no version for an unknown language, got %q", got)
+	}
+}
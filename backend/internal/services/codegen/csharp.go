@@ -0,0 +1,83 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+var cSharpScalars = map[domain.TypeKind]string{
+	domain.KindInt:    "int",
+	domain.KindLong:   "long",
+	domain.KindDouble: "double",
+	domain.KindBool:   "bool",
+	domain.KindChar:   "char",
+	domain.KindString: "string",
+}
+
+func (s *CodeGenService) mapTypeToCSharp(typ domain.GenericType) string {
+	t, err := typ.Parse()
+	if err != nil {
+		return string(typ)
+	}
+	return cSharpType(t)
+}
+
+func cSharpType(t *domain.TypeExpr) string {
+	switch t.Kind {
+	case domain.KindList:
+		return cSharpType(t.Elem) + "[]"
+	case domain.KindMap:
+		return "Dictionary<" + cSharpType(t.Key) + ", " + cSharpType(t.Elem) + ">"
+	case domain.KindCustom:
+		return t.Name
+	case domain.KindString:
+		// Already a reference type
+		return "string"
+	}
+	if t.Nullable {
+		return cSharpScalars[t.Kind] + "?"
+	}
+	return cSharpScalars[t.Kind]
+}
+
+// pascalCase capitalizes a camelCase name from the schema, as C# names methods
+func pascalCase(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return name
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// generateCSharpStub writes the user's code in a Solution class with the method
+// named in PascalCase. Lists are arrays and maps Dictionaries.
+func (s *CodeGenService) generateCSharpStub(sig domain.ProblemSchema, customTypes []string) (string, error) {
+	var sb strings.Builder
+	if err := s.writeDefinitionComments(&sb, customTypes, "csharp"); err != nil {
+		return "", err
+	}
+
+	params := []string{}
+	for _, param := range sig.Parameters {
+		params = append(params, fmt.Sprintf("%s %s", s.mapTypeToCSharp(param.Type), param.Name))
+	}
+	sb.WriteString("public class Solution {\n")
+	sb.WriteString(fmt.Sprintf("    public %s %s(%s) {\n", s.mapTypeToCSharp(sig.ReturnType), pascalCase(sig.FunctionName), strings.Join(params, ", ")))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// cSharpHarness converts arguments by reflection on their declared type, so the
+// template only names the types. The runtime sticks to C# 6 so it also builds with
+// Mono's compiler.
+var cSharpHarness = mustLoadHarnessTemplate("csharp", template.FuncMap{
+	"cSharpType": mappedType(cSharpType),
+	"pascal":     pascalCase,
+})
@@ -0,0 +1,244 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// Design problems have the user implement a class, see domain.DesignSchema. Each
+// language writes the class the way LeetCode shows it, so its harness knows how to
+// construct it and call its methods: Go has a Constructor function and exported
+// methods, Rust a new function and snake_case methods, C# PascalCase methods. C has
+// no classes and isn't offered for design problems.
+
+// validateDesign checks what every design stub needs
+func validateDesign(design *domain.DesignSchema) error {
+	if design.ClassName == "" {
+		return errors.New("class name is required")
+	}
+	if len(design.Methods) == 0 {
+		return errors.New("at least one method is required")
+	}
+	return nil
+}
+
+// typedParams is the parameters declared in one of the two usual shapes: "name:
+// type" when typeFirst is false, "type name" when it's true
+func typedParams(params []domain.SchemaParameter, mapType func(domain.GenericType) string, typeFirst bool, name func(string) string) string {
+	res := make([]string, len(params))
+	for i, param := range params {
+		if typeFirst {
+			res[i] = fmt.Sprintf("%s %s", mapType(param.Type), name(param.Name))
+		} else {
+			res[i] = fmt.Sprintf("%s: %s", name(param.Name), mapType(param.Type))
+		}
+	}
+	return strings.Join(res, ", ")
+}
+
+func sameName(name string) string { return name }
+
+func (s *CodeGenService) generatePythonDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString("from typing import Dict, List, Optional\n\n")
+	sb.WriteString(fmt.Sprintf("class %s:\n\n", design.ClassName))
+	params := typedParams(design.Constructor, s.mapTypeToPython, false, sameName)
+	if params != "" {
+		params = ", " + params
+	}
+	sb.WriteString(fmt.Sprintf("    def __init__(self%s):\n", params))
+	sb.WriteString("        # Write your code here\n")
+	sb.WriteString("        pass\n")
+	for _, m := range design.Methods {
+		params := typedParams(m.Parameters, s.mapTypeToPython, false, sameName)
+		if params != "" {
+			params = ", " + params
+		}
+		returnType := "None"
+		if !m.IsVoid() {
+			returnType = s.mapTypeToPython(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\n    def %s(self%s) -> %s:\n", m.Name, params, returnType))
+		sb.WriteString("        pass\n")
+	}
+	return sb.String()
+}
+
+func (s *CodeGenService) generateJavaScriptDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("class %s {\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("    constructor(%s) {\n", paramNames(design.Constructor)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	for _, m := range design.Methods {
+		sb.WriteString(fmt.Sprintf("\n    %s(%s) {\n", m.Name, paramNames(m.Parameters)))
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func paramNames(params []domain.SchemaParameter) string {
+	names := make([]string, len(params))
+	for i, param := range params {
+		names[i] = param.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func (s *CodeGenService) generateTypeScriptDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("class %s {\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("    constructor(%s) {\n", typedParams(design.Constructor, s.mapTypeToTypeScript, false, sameName)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	for _, m := range design.Methods {
+		returnType := "void"
+		if !m.IsVoid() {
+			returnType = s.mapTypeToTypeScript(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\n    %s(%s): %s {\n", m.Name, typedParams(m.Parameters, s.mapTypeToTypeScript, false, sameName), returnType))
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateJavaDesignStub leaves the class package-private, since the harness's
+// public class is the one the file is named after
+func (s *CodeGenService) generateJavaDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("class %s {\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("    public %s(%s) {\n", design.ClassName, typedParams(design.Constructor, s.mapTypeToJava, true, sameName)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	for _, m := range design.Methods {
+		returnType := "void"
+		if !m.IsVoid() {
+			returnType = s.mapTypeToJava(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\n    public %s %s(%s) {\n", returnType, m.Name, typedParams(m.Parameters, s.mapTypeToJava, true, sameName)))
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (s *CodeGenService) generateCppDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString("#include <iostream>\n#include <vector>\n#include <string>\n")
+	var usesMap, usesOptional bool
+	for _, typ := range signatureTypes(domain.ProblemSchema{Design: design}) {
+		if t, err := typ.Parse(); err == nil {
+			usesMap = usesMap || t.Contains(domain.KindMap)
+			usesOptional = usesOptional || isNullableAnywhere(t)
+		}
+	}
+	if usesMap {
+		sb.WriteString("#include <map>\n")
+	}
+	if usesOptional {
+		sb.WriteString("#include <optional>\n")
+	}
+	sb.WriteString("\nusing namespace std;\n\n")
+	sb.WriteString(fmt.Sprintf("class %s {\npublic:\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("    %s(%s) {\n", design.ClassName, typedParams(design.Constructor, s.mapTypeToCpp, true, sameName)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	for _, m := range design.Methods {
+		returnType := "void"
+		if !m.IsVoid() {
+			returnType = s.mapTypeToCpp(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\n    %s %s(%s) {\n", returnType, m.Name, typedParams(m.Parameters, s.mapTypeToCpp, true, sameName)))
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("};\n")
+	return sb.String()
+}
+
+// generateGoDesignStub follows LeetCode's Go shape: a struct, a Constructor
+// returning it and pointer methods named in PascalCase
+func (s *CodeGenService) generateGoDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	goParams := func(params []domain.SchemaParameter) string {
+		res := make([]string, len(params))
+		for i, param := range params {
+			res[i] = fmt.Sprintf("%s %s", param.Name, s.mapTypeToGo(param.Type))
+		}
+		return strings.Join(res, ", ")
+	}
+	sb.WriteString(fmt.Sprintf("type %s struct {\n}\n\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("func Constructor(%s) %s {\n", goParams(design.Constructor), design.ClassName))
+	sb.WriteString("    // Write your code here\n")
+	sb.WriteString("}\n")
+	for _, m := range design.Methods {
+		returnType := ""
+		if !m.IsVoid() {
+			returnType = " " + s.mapTypeToGo(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\nfunc (this *%s) %s(%s)%s {\n", design.ClassName, pascalCase(m.Name), goParams(m.Parameters), returnType))
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
+
+func (s *CodeGenService) generateRustDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("struct %s {\n}\n\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("impl %s {\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("    fn new(%s) -> Self {\n", typedParams(design.Constructor, s.mapTypeToRust, false, snakeCase)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	for _, m := range design.Methods {
+		params := typedParams(m.Parameters, s.mapTypeToRust, false, snakeCase)
+		if params != "" {
+			params = ", " + params
+		}
+		returnType := ""
+		if !m.IsVoid() {
+			returnType = " -> " + s.mapTypeToRust(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\n    fn %s(&mut self%s)%s {\n", snakeCase(m.Name), params, returnType))
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (s *CodeGenService) generateKotlinDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("class %s(%s) {\n", design.ClassName, typedParams(design.Constructor, s.mapTypeToKotlin, false, sameName)))
+	sb.WriteString("    // Write your code here\n")
+	for _, m := range design.Methods {
+		returnType := ""
+		if !m.IsVoid() {
+			returnType = ": " + s.mapTypeToKotlin(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\n    fun %s(%s)%s {\n", m.Name, typedParams(m.Parameters, s.mapTypeToKotlin, false, sameName), returnType))
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (s *CodeGenService) generateCSharpDesignStub(design *domain.DesignSchema) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("public class %s {\n", design.ClassName))
+	sb.WriteString(fmt.Sprintf("    public %s(%s) {\n", design.ClassName, typedParams(design.Constructor, s.mapTypeToCSharp, true, sameName)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	for _, m := range design.Methods {
+		returnType := "void"
+		if !m.IsVoid() {
+			returnType = s.mapTypeToCSharp(m.ReturnType)
+		}
+		sb.WriteString(fmt.Sprintf("\n    public %s %s(%s) {\n", returnType, pascalCase(m.Name), typedParams(m.Parameters, s.mapTypeToCSharp, true, sameName)))
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,141 @@
+package codegen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// Run with -update to rewrite the golden files after an intended template change
+var updateGolden = flag.Bool("update", false, "rewrite the harness golden files")
+
+var goldenSignatures = []struct {
+	name           string
+	sig            domain.ProblemSchema
+	validationType string
+	skip           []string // languages the signature can't be generated for
+}{
+	{
+		name: "two_sum",
+		sig: domain.ProblemSchema{
+			FunctionName: "twoSum",
+			Parameters: []domain.SchemaParameter{
+				{Name: "nums", Type: domain.TypeIntegerArray},
+				{Name: "target", Type: domain.TypeInteger},
+			},
+			ReturnType: domain.TypeIntegerArray,
+		},
+		validationType: "UNORDERED",
+	},
+	{
+		name: "scalars",
+		sig: domain.ProblemSchema{
+			FunctionName: "render",
+			Parameters: []domain.SchemaParameter{
+				{Name: "grid", Type: domain.TypeIntegerMatrix},
+				{Name: "words", Type: domain.TypeStringArray},
+				{Name: "k", Type: domain.TypeLong},
+				{Name: "ratio", Type: domain.TypeDouble},
+				{Name: "sep", Type: domain.TypeChar},
+				{Name: "strict", Type: domain.TypeBoolean},
+			},
+			ReturnType: domain.TypeStringMatrix,
+		},
+		validationType: "EXACT",
+	},
+	{
+		name: "maps",
+		sig: domain.ProblemSchema{
+			FunctionName: "groupIds",
+			Parameters: []domain.SchemaParameter{
+				{Name: "counts", Type: "map<string,int>"},
+				{Name: "ids", Type: "list<int?>"},
+				{Name: "limit", Type: "long?"},
+			},
+			ReturnType: "map<int,list<string>>",
+		},
+		validationType: "CUSTOM",
+		skip:           []string{"c"},
+	},
+	{
+		name:           "custom_types",
+		sig:            invertTreeSignature,
+		validationType: "EXACT",
+	},
+	{
+		name:           "design",
+		sig:            timeMapSignature,
+		validationType: "EXACT",
+		skip:           []string{"c"},
+	},
+}
+
+// timeMapSignature is a design problem with a constructor taking nothing and
+// methods of every return shape
+var timeMapSignature = domain.ProblemSchema{Design: &domain.DesignSchema{
+	ClassName: "TimeMap",
+	Methods: []domain.MethodSchema{
+		{Name: "set", Parameters: []domain.SchemaParameter{
+			{Name: "key", Type: domain.TypeString},
+			{Name: "value", Type: domain.TypeString},
+			{Name: "timestamp", Type: domain.TypeInteger},
+		}, ReturnType: domain.TypeVoid},
+		{Name: "get", Parameters: []domain.SchemaParameter{
+			{Name: "key", Type: domain.TypeString},
+			{Name: "timestamp", Type: domain.TypeInteger},
+		}, ReturnType: domain.TypeString},
+		{Name: "history", Parameters: []domain.SchemaParameter{
+			{Name: "key", Type: domain.TypeString},
+		}, ReturnType: "list<string>"},
+	},
+}}
+
+func TestHarnessGoldenFiles(t *testing.T) {
+	svc := newTestCodeGenService()
+
+	for _, tt := range goldenSignatures {
+		for _, lang := range svc.Languages() {
+			if containsString(tt.skip, lang) {
+				continue
+			}
+			t.Run(tt.name+"/"+lang, func(t *testing.T) {
+				harness, err := svc.GenerateTestHarness(tt.sig, "{USER_CODE}", lang, nil, tt.validationType)
+				if err != nil {
+					t.Fatalf("GenerateTestHarness failed: %v", err)
+				}
+
+				path := filepath.Join("testdata", "harness", tt.name+"."+strings.ReplaceAll(lang, "+", "p")+".golden")
+				if *updateGolden {
+					if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(path, []byte(harness), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+
+				want, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("Missing golden file, run the test with -update: %v", err)
+				}
+				if harness != string(want) {
+					t.Errorf("Harness differs from %s, run the test with -update if the change is intended", path)
+				}
+			})
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
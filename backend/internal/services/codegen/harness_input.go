@@ -0,0 +1,59 @@
+package codegen
+
+import (
+	"encoding/json"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// HarnessStartupMs is the headroom on top of the per-test budgets for starting the
+// runtime and parsing the input, so a slow JVM start isn't reported as a timeout
+const HarnessStartupMs = 2000
+
+// HarnessTest is one entry of the JSON array every generated harness reads from stdin
+type HarnessTest struct {
+	Input         interface{} `json:"input"`
+	Expected      interface{} `json:"expected"`
+	TimeLimitMs   int         `json:"time_limit_ms"`
+	MemoryLimitMb int         `json:"memory_limit_mb"`
+	// Compare is left out when values must match exactly
+	Compare *domain.CompareOptions `json:"compare,omitempty"`
+	// Nonce is the same in every test of a run; the harness prints it after
+	// HarnessResultMarker. Left out for harnesses that predate it.
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// BuildHarnessInput encodes a batch of test cases as harness stdin and returns the
+// limits for the whole process: each test gets its own time budget inside the
+// harness, the process gets the sum plus startup headroom and the largest memory
+// budget of the batch. Limits are scaled by the language's multipliers. Tests that
+// aren't compared exactly carry their comparison options, and every test the nonce.
+func BuildHarnessInput(testCases []domain.TestCase, problem *domain.Problem, language *domain.Language, nonce string) (string, domain.ExecutionLimits, error) {
+	tests := make([]HarnessTest, len(testCases))
+	process := domain.ExecutionLimits{TimeMs: HarnessStartupMs}
+
+	for i := range testCases {
+		tc := &testCases[i]
+		limits := language.ScaleLimits(tc.Limits(problem))
+
+		var input, expected interface{}
+		_ = json.Unmarshal([]byte(tc.Input), &input)
+		_ = json.Unmarshal([]byte(tc.ExpectedOutput), &expected)
+		tests[i] = HarnessTest{Input: input, Expected: expected, TimeLimitMs: limits.TimeMs, MemoryLimitMb: limits.MemoryMb, Nonce: nonce}
+		if opts := tc.CompareOptions(problem); !opts.IsZero() {
+			tests[i].Compare = &opts
+		}
+
+		process.TimeMs += limits.TimeMs
+		if limits.MemoryMb > process.MemoryMb {
+			process.MemoryMb = limits.MemoryMb
+		}
+	}
+
+	data, err := json.Marshal(tests)
+	if err != nil {
+		return "", domain.ExecutionLimits{}, err
+	}
+
+	return string(data), process, nil
+}
@@ -0,0 +1,110 @@
+package codegen
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestBuildHarnessInputLimits(t *testing.T) {
+	problem := &domain.Problem{TimeLimit: 1000, MemoryLimit: 128}
+	python := &domain.Language{ExecutorConfig: domain.ExecutorConfig{
+		domain.ExecutorConfigTimeMultiplier:   2.5,
+		domain.ExecutorConfigMemoryMultiplier: 2.0,
+	}}
+	testCases := []domain.TestCase{
+		{Input: "[1, 2]", ExpectedOutput: "3"},
+		{Input: "[5, 5]", ExpectedOutput: "10", TimeLimitMs: intPtr(3000), MemoryLimitMb: intPtr(512)},
+	}
+
+	stdin, limits, err := BuildHarnessInput(testCases, problem, python, "f00d")
+	if err != nil {
+		t.Fatalf("BuildHarnessInput failed: %v", err)
+	}
+
+	var tests []HarnessTest
+	if err := json.Unmarshal([]byte(stdin), &tests); err != nil {
+		t.Fatalf("stdin is not a JSON array of tests: %v", err)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("Expected 2 tests, got %d", len(tests))
+	}
+	if tests[0].TimeLimitMs != 2500 || tests[1].TimeLimitMs != 7500 {
+		t.Errorf("Expected per-test limits 2500/7500, got %d/%d", tests[0].TimeLimitMs, tests[1].TimeLimitMs)
+	}
+	if tests[0].Nonce != "f00d" || tests[1].Nonce != "f00d" {
+		t.Errorf("Expected every test to carry the nonce, got %q/%q", tests[0].Nonce, tests[1].Nonce)
+	}
+	if got, ok := tests[1].Input.([]interface{}); !ok || len(got) != 2 {
+		t.Errorf("Expected decoded input, got %#v", tests[1].Input)
+	}
+
+	if limits.TimeMs != HarnessStartupMs+2500+7500 {
+		t.Errorf("Expected process time limit %d, got %d", HarnessStartupMs+10000, limits.TimeMs)
+	}
+	if limits.MemoryMb != 1024 {
+		t.Errorf("Expected process memory limit 1024, got %d", limits.MemoryMb)
+	}
+}
+
+func TestBuildHarnessInputDefaults(t *testing.T) {
+	// No problem limits and no language: the column defaults apply unscaled
+	_, limits, err := BuildHarnessInput([]domain.TestCase{{Input: "[]", ExpectedOutput: "0"}}, &domain.Problem{}, nil, "")
+	if err != nil {
+		t.Fatalf("BuildHarnessInput failed: %v", err)
+	}
+
+	want := domain.ExecutionLimits{TimeMs: HarnessStartupMs + domain.DefaultTimeLimitMs, MemoryMb: domain.DefaultMemoryLimitMb}
+	if limits != want {
+		t.Errorf("Expected %+v, got %+v", want, limits)
+	}
+}
+
+func TestBuildHarnessInputCompareOptions(t *testing.T) {
+	returnType := string(domain.TypeDouble)
+	problem := &domain.Problem{ReturnType: &returnType}
+	testCases := []domain.TestCase{
+		{Input: "[0.1, 0.2]", ExpectedOutput: "0.3"},
+		{Input: "[1, 2]", ExpectedOutput: "3", ValidationConfig: domain.ValidationConfig{domain.ValidationConfigAbsEpsilon: 1e-3}},
+		{Input: `["A  b"]`, ExpectedOutput: `"a b"`, ValidationConfig: domain.ValidationConfig{
+			domain.ValidationConfigRelEpsilon:       -1.0,
+			domain.ValidationConfigCaseInsensitive:  true,
+			domain.ValidationConfigIgnoreWhitespace: true,
+		}},
+	}
+
+	stdin, _, err := BuildHarnessInput(testCases, problem, nil, "")
+	if err != nil {
+		t.Fatalf("BuildHarnessInput failed: %v", err)
+	}
+	var tests []HarnessTest
+	if err := json.Unmarshal([]byte(stdin), &tests); err != nil {
+		t.Fatalf("stdin is not a JSON array of tests: %v", err)
+	}
+
+	want := []domain.CompareOptions{
+		// Doubles get the default tolerance when the test case sets none
+		{AbsEpsilon: domain.DefaultFloatEpsilon, RelEpsilon: domain.DefaultFloatEpsilon},
+		{AbsEpsilon: 1e-3},
+		// A negative epsilon is ignored but still counts as set
+		{CaseInsensitive: true, IgnoreWhitespace: true},
+	}
+	for i, opts := range want {
+		if tests[i].Compare == nil || *tests[i].Compare != opts {
+			t.Errorf("test %d: expected compare options %+v, got %+v", i, opts, tests[i].Compare)
+		}
+	}
+
+	// Exact comparisons leave the options out altogether
+	stdin, _, err = BuildHarnessInput([]domain.TestCase{{Input: "[1]", ExpectedOutput: "1"}}, &domain.Problem{}, nil, "")
+	if err != nil {
+		t.Fatalf("BuildHarnessInput failed: %v", err)
+	}
+	if strings.Contains(stdin, `"compare"`) {
+		t.Errorf("Expected no compare options for an exact comparison, got %s", stdin)
+	}
+}
@@ -0,0 +1,72 @@
+package codegen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// HarnessResultMarker is printed by every generated harness on a line of its own right
+// before its JSON result, so whatever the user's code printed to stdout can't be
+// mistaken for (or break) the result. Harnesses given a nonce print it right after the
+// marker, so user code that prints the marker itself can't pass its output off as
+// the result.
+const HarnessResultMarker = "@@LOCO_RESULT@@"
+
+// harnessNonceVersions is the first template version of each language whose harness
+// reads a nonce from its tests and prints it after the marker. Those harnesses hold
+// the nonce and the expected values where the user's code can't name them: in locals
+// of main, or in a closure read before the user's code runs where that code runs on
+// load.
+var harnessNonceVersions = map[string]int{
+	"c":          2,
+	"cpp":        3,
+	"csharp":     3,
+	"go":         3,
+	"java":       3,
+	"javascript": 3,
+	"kotlin":     3,
+	"python":     3,
+	"rust":       3,
+	"typescript": 3,
+}
+
+// NewHarnessNonce returns a random nonce for one judging of a submission
+func NewHarnessNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HarnessTakesNonce reports whether a harness rendered from the named template
+// version, e.g. "python.v3", prints the nonce of its tests after the marker.
+// Harnesses stored before versions were recorded don't.
+func HarnessTakesNonce(version string) bool {
+	name, v, ok := strings.Cut(version, ".v")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(v)
+	since, known := harnessNonceVersions[name]
+	return err == nil && known && n >= since
+}
+
+// SplitHarnessOutput separates what the user's code printed from the harness result.
+// The last marker followed by the nonce wins, since the harness prints its result
+// after the user's code has finished; output without it is all the user's. An empty
+// nonce is for harnesses that predate it: the bare marker is looked for, and output
+// without one comes from a harness generated before the marker existed and is taken
+// to be the result as a whole.
+func SplitHarnessOutput(output, nonce string) (userStdout, result string) {
+	marker := HarnessResultMarker + nonce
+	idx := strings.LastIndex(output, marker)
+	switch {
+	case idx >= 0:
+		return strings.TrimSuffix(output[:idx], "\n"), strings.TrimSpace(output[idx+len(marker):])
+	case nonce != "":
+		return strings.TrimSuffix(output, "\n"), ""
+	default:
+		return "", strings.TrimSpace(output)
+	}
+}
@@ -0,0 +1,50 @@
+package codegen
+
+import "testing"
+
+func TestSplitHarnessOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		nonce      string
+		wantStdout string
+		wantResult string
+	}{
+		{name: "result only", output: HarnessResultMarker + "\n{\"verdict\":\"ACCEPTED\"}\n", wantResult: `{"verdict":"ACCEPTED"}`},
+		{name: "user prints first", output: "debug 1\ndebug 2\n" + HarnessResultMarker + "\n{}\n", wantStdout: "debug 1\ndebug 2", wantResult: "{}"},
+		{name: "user prints the marker", output: HarnessResultMarker + "\n" + HarnessResultMarker + "\n{}", wantStdout: HarnessResultMarker, wantResult: "{}"},
+		{name: "print without newline", output: "no newline" + HarnessResultMarker + "\n{}", wantStdout: "no newline", wantResult: "{}"},
+		{name: "harness without marker", output: "{\"verdict\":\"ACCEPTED\"}\n", wantResult: `{"verdict":"ACCEPTED"}`},
+		{name: "nonce", output: "debug\n" + HarnessResultMarker + "f00d\n{}\n", nonce: "f00d", wantStdout: "debug", wantResult: "{}"},
+		{name: "user forges the marker before the result", output: HarnessResultMarker + "\n{\"verdict\":\"ACCEPTED\"}\n" + HarnessResultMarker + "f00d\n{}", nonce: "f00d",
+			wantStdout: HarnessResultMarker + "\n{\"verdict\":\"ACCEPTED\"}", wantResult: "{}"},
+		{name: "user forges the marker and exits", output: HarnessResultMarker + "\n{\"verdict\":\"ACCEPTED\"}\n", nonce: "f00d",
+			wantStdout: HarnessResultMarker + "\n{\"verdict\":\"ACCEPTED\"}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, result := SplitHarnessOutput(tt.output, tt.nonce)
+			if stdout != tt.wantStdout || result != tt.wantResult {
+				t.Errorf("Expected (%q, %q), got (%q, %q)", tt.wantStdout, tt.wantResult, stdout, result)
+			}
+		})
+	}
+}
+
+func TestHarnessTakesNonce(t *testing.T) {
+	tests := map[string]bool{
+		"python.v3": true,
+		"python.v4": true,
+		"c.v2":      true,
+		"python.v2": false,
+		"c.v1":      false,
+		"zig.v9":    false,
+		"":          false, // stored before versions were recorded
+	}
+	for version, want := range tests {
+		if got := HarnessTakesNonce(version); got != want {
+			t.Errorf("HarnessTakesNonce(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// kotlinScalars are the Kotlin types of the scalars. Lists of the non-null numbers,
+// booleans and chars are primitive arrays, other lists are Arrays unless they hold
+// maps.
+var kotlinScalars = map[domain.TypeKind]string{
+	domain.KindInt:    "Int",
+	domain.KindLong:   "Long",
+	domain.KindDouble: "Double",
+	domain.KindBool:   "Boolean",
+	domain.KindChar:   "Char",
+	domain.KindString: "String",
+}
+
+// kotlinPrimitiveArrays are the array types of the scalars that have one
+var kotlinPrimitiveArrays = map[domain.TypeKind]string{
+	domain.KindInt:    "IntArray",
+	domain.KindLong:   "LongArray",
+	domain.KindDouble: "DoubleArray",
+	domain.KindBool:   "BooleanArray",
+	domain.KindChar:   "CharArray",
+}
+
+func (s *CodeGenService) mapTypeToKotlin(typ domain.GenericType) string {
+	t, err := typ.Parse()
+	if err != nil {
+		return string(typ)
+	}
+	return kotlinType(t)
+}
+
+func kotlinType(t *domain.TypeExpr) string {
+	var base string
+	switch t.Kind {
+	case domain.KindList:
+		if prim, ok := kotlinPrimitiveArrays[t.Elem.Kind]; ok && !t.Elem.Nullable {
+			base = prim
+		} else if t.Elem.Contains(domain.KindMap) {
+			base = "List<" + kotlinType(t.Elem) + ">"
+		} else {
+			base = "Array<" + kotlinType(t.Elem) + ">"
+		}
+	case domain.KindMap:
+		base = "Map<" + kotlinType(t.Key) + ", " + kotlinType(t.Elem) + ">"
+	case domain.KindCustom:
+		return t.Name + "?"
+	default:
+		base = kotlinScalars[t.Kind]
+	}
+	if t.Nullable {
+		return base + "?"
+	}
+	return base
+}
+
+// kotlinFromJson is an expression reading a value of typ from the Json in src
+func kotlinFromJson(typ domain.GenericType, src string) string {
+	t, err := typ.Parse()
+	if err != nil {
+		return src
+	}
+	return kotlinConvert(t, src, 0)
+}
+
+func kotlinConvert(t *domain.TypeExpr, src string, depth int) string {
+	if t.Nullable {
+		return fmt.Sprintf("(if (%s.isNull()) null else %s)", src, kotlinConvert(t.NonNull(), src, depth))
+	}
+
+	item := fmt.Sprintf("item%d", depth)
+	switch t.Kind {
+	case domain.KindInt:
+		return src + ".int()"
+	case domain.KindLong:
+		return src + ".long()"
+	case domain.KindDouble:
+		return src + ".double()"
+	case domain.KindBool:
+		return src + ".bool()"
+	case domain.KindChar:
+		return src + ".str()[0]"
+	case domain.KindString:
+		return src + ".str()"
+	case domain.KindList:
+		list := fmt.Sprintf("%s.items().map { %s -> %s }", src, item, kotlinConvert(t.Elem, item, depth+1))
+		if prim, ok := kotlinPrimitiveArrays[t.Elem.Kind]; ok && !t.Elem.Nullable {
+			return list + ".to" + prim + "()"
+		}
+		if t.Elem.Contains(domain.KindMap) {
+			return list
+		}
+		return list + ".toTypedArray()"
+	case domain.KindMap:
+		key := fmt.Sprintf("key%d", depth)
+		var keyExpr string
+		switch t.Key.Kind {
+		case domain.KindInt:
+			keyExpr = key + ".toInt()"
+		case domain.KindLong:
+			keyExpr = key + ".toLong()"
+		case domain.KindChar:
+			keyExpr = key + "[0]"
+		default:
+			keyExpr = key
+		}
+		return fmt.Sprintf("%s.fields().entries.associate { (%s, %s) -> %s to %s }", src, key, item, keyExpr, kotlinConvert(t.Elem, item, depth+1))
+	}
+	return src
+}
+
+// generateKotlinStub writes the user's code as a method of a Solution class
+func (s *CodeGenService) generateKotlinStub(sig domain.ProblemSchema, customTypes []string) (string, error) {
+	var sb strings.Builder
+	if err := s.writeDefinitionComments(&sb, customTypes, "kotlin"); err != nil {
+		return "", err
+	}
+
+	params := []string{}
+	for _, param := range sig.Parameters {
+		params = append(params, fmt.Sprintf("%s: %s", param.Name, s.mapTypeToKotlin(param.Type)))
+	}
+	sb.WriteString("class Solution {\n")
+	sb.WriteString(fmt.Sprintf("    fun %s(%s): %s {\n", sig.FunctionName, strings.Join(params, ", "), s.mapTypeToKotlin(sig.ReturnType)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// kotlinHarness reads arguments from its Json by expressions generated per type;
+// results go back through the runtime's toJson
+var kotlinHarness = mustLoadHarnessTemplate("kotlin", template.FuncMap{
+	"kotlinType": mappedType(kotlinType),
+	"fromJson":   kotlinFromJson,
+})
@@ -0,0 +1,89 @@
+package codegen
+
+import (
+	"sort"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// LanguageGenerator writes the starter code and the test harness of one language.
+// Generators are registered on a CodeGenService by language slug; the built-in ones
+// are registered by NewCodeGenService.
+type LanguageGenerator interface {
+	// GenerateStub writes the function the user fills in. customTypes are the
+	// signature's custom types, which must have an implementation for the language.
+	GenerateStub(sig domain.ProblemSchema, customTypes []string) (string, error)
+	// GenerateHarness wraps the user's code in a program that reads HarnessTest
+	// values from stdin and prints HarnessResultMarker followed by its result
+	GenerateHarness(sig domain.ProblemSchema, userCode string, testCases []domain.TestCase, validationType string) (string, error)
+}
+
+// versionedGenerator is a LanguageGenerator that can name the version of the
+// harness it generates
+type versionedGenerator interface {
+	HarnessVersion() string
+}
+
+// designGenerator is a LanguageGenerator that can say whether it generates design
+// problems; generators that don't say are taken to
+type designGenerator interface {
+	SupportsDesign() bool
+}
+
+// registerBuiltinLanguages registers the generators shipped with the service
+func (s *CodeGenService) registerBuiltinLanguages() {
+	s.registerTemplateLanguage("python", s.generatePythonStub, s.generatePythonDesignStub, pythonHarness, nil)
+	s.registerTemplateLanguage("javascript", s.generateJavaScriptStub, s.generateJavaScriptDesignStub, javaScriptHarness, nil)
+	s.registerTemplateLanguage("java", s.generateJavaStub, s.generateJavaDesignStub, javaHarness, nil)
+	s.registerTemplateLanguage("c++", s.generateCppStub, s.generateCppDesignStub, cppHarness, nil)
+	s.registerTemplateLanguage("c", s.generateCStub, nil, cHarness, checkCSignature)
+	s.registerTemplateLanguage("go", s.generateGoStub, s.generateGoDesignStub, goHarness, nil)
+	s.registerTemplateLanguage("rust", s.generateRustStub, s.generateRustDesignStub, rustHarness, nil)
+	s.registerTemplateLanguage("typescript", s.generateTypeScriptStub, s.generateTypeScriptDesignStub, typeScriptHarness, nil)
+	s.registerTemplateLanguage("kotlin", s.generateKotlinStub, s.generateKotlinDesignStub, kotlinHarness, nil)
+	s.registerTemplateLanguage("csharp", s.generateCSharpStub, s.generateCSharpDesignStub, cSharpHarness, nil)
+}
+
+// registerTemplateLanguage registers a language whose harness is rendered from an
+// embedded template. designStub is nil for languages design problems can't be
+// solved in.
+func (s *CodeGenService) registerTemplateLanguage(slug string, stub func(domain.ProblemSchema, []string) (string, error), designStub func(*domain.DesignSchema) string, harness *harnessTemplate, check func(domain.ProblemSchema) error) {
+	s.RegisterLanguage(slug, &templateGenerator{s: s, slug: slug, stub: stub, designStub: designStub, harness: harness, check: check})
+}
+
+// RegisterLanguage makes a generator available under a language slug, replacing any
+// generator already registered for it
+func (s *CodeGenService) RegisterLanguage(slug string, gen LanguageGenerator) {
+	s.generators[slug] = gen
+}
+
+// Languages lists the slugs of every registered language, sorted
+func (s *CodeGenService) Languages() []string {
+	slugs := make([]string, 0, len(s.generators))
+	for slug := range s.generators {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+// SupportsDesign reports whether design problems can be solved in a language
+func (s *CodeGenService) SupportsDesign(languageSlug string) bool {
+	gen, ok := s.generators[languageSlug]
+	if !ok {
+		return false
+	}
+	if dg, ok := gen.(designGenerator); ok {
+		return dg.SupportsDesign()
+	}
+	return true
+}
+
+// HarnessVersion names the template version a language's harness is generated
+// from, or is empty when its generator doesn't say
+func (s *CodeGenService) HarnessVersion(languageSlug string) string {
+	if gen, ok := s.generators[languageSlug].(versionedGenerator); ok {
+		return gen.HarnessVersion()
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// rustCustomTypes are the custom types that aren't shared, mutable nodes
+var rustCustomTypes = map[string]string{
+	"ListNode": "Option<Box<ListNode>>",
+}
+
+func (s *CodeGenService) mapTypeToRust(typ domain.GenericType) string {
+	t, err := typ.Parse()
+	if err != nil {
+		return string(typ)
+	}
+	return rustType(t)
+}
+
+func rustType(t *domain.TypeExpr) string {
+	var base string
+	switch t.Kind {
+	case domain.KindInt:
+		base = "i32"
+	case domain.KindLong:
+		base = "i64"
+	case domain.KindDouble:
+		base = "f64"
+	case domain.KindBool:
+		base = "bool"
+	case domain.KindChar:
+		base = "char"
+	case domain.KindString:
+		base = "String"
+	case domain.KindList:
+		base = "Vec<" + rustType(t.Elem) + ">"
+	case domain.KindMap:
+		base = "HashMap<" + rustType(t.Key) + ", " + rustType(t.Elem) + ">"
+	case domain.KindCustom:
+		if mapped, ok := rustCustomTypes[t.Name]; ok {
+			return mapped
+		}
+		return fmt.Sprintf("Option<Rc<RefCell<%s>>>", t.Name)
+	}
+	if t.Nullable {
+		return "Option<" + base + ">"
+	}
+	return base
+}
+
+// snakeCase turns a camelCase name from the schema into Rust's snake_case
+func snakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// generateRustStub writes Rust in the usual "impl Solution" shape, with snake_case
+// function and parameter names
+func (s *CodeGenService) generateRustStub(sig domain.ProblemSchema, customTypes []string) (string, error) {
+	var sb strings.Builder
+	if err := s.writeDefinitionComments(&sb, customTypes, "rust"); err != nil {
+		return "", err
+	}
+
+	params := []string{}
+	for _, param := range sig.Parameters {
+		params = append(params, fmt.Sprintf("%s: %s", snakeCase(param.Name), s.mapTypeToRust(param.Type)))
+	}
+	sb.WriteString("impl Solution {\n")
+	sb.WriteString(fmt.Sprintf("    pub fn %s(%s) -> %s {\n", snakeCase(sig.FunctionName), strings.Join(params, ", "), s.mapTypeToRust(sig.ReturnType)))
+	sb.WriteString("        // Write your code here\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// rustHarness has no crates to lean on, so it carries its own JSON value and
+// converts arguments and results through the FromJson and ToJson traits,
+// implemented for every type the schema grammar maps to
+var rustHarness = mustLoadHarnessTemplate("rust", template.FuncMap{
+	"rustType": mappedType(rustType),
+	"snake":    snakeCase,
+	"snakeArgs": func(d HarnessData) string {
+		names := make([]string, len(d.Params))
+		for i, param := range d.Params {
+			names[i] = snakeCase(param.Name)
+		}
+		return strings.Join(names, ", ")
+	},
+})
@@ -0,0 +1,278 @@
+package codegen
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// Harness templates live in templates/<name>.v<version>.tmpl. A change to what a
+// harness does gets a new version next to the old one rather than an edit, so a
+// stored boilerplate can always be traced to the template that produced it; the
+// highest version of each language is the one rendered.
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templateFileRe = regexp.MustCompile(`^([a-z0-9]+)\.v([0-9]+)\.tmpl$`)
+
+// HarnessData is what every harness template is rendered from: the signature with
+// its custom types resolved for the language, and the settings shared by all
+// harnesses. Design problems have Design set instead of a function.
+type HarnessData struct {
+	Design             *HarnessDesign
+	FunctionName       string
+	Params             []HarnessParam
+	ReturnType         domain.GenericType
+	ReturnsCustom      bool
+	CustomTypes        []HarnessCustomType
+	UserCode           string
+	ValidationType     string
+	DefaultTimeLimitMs int
+	ResultMarker       string
+}
+
+// HarnessParam is a parameter and its position in a test's input array
+type HarnessParam struct {
+	Index  int
+	Name   string
+	Type   domain.GenericType
+	Custom bool
+}
+
+// HarnessDesign is the class of a design problem. Each test constructs it from the
+// first operation's arguments and calls the methods the rest name, collecting what
+// each call returns, null for the constructor and void methods.
+type HarnessDesign struct {
+	ClassName   string
+	Constructor []HarnessParam
+	Methods     []HarnessMethod
+}
+
+// HarnessMethod is a method of a design class; its parameters are indexed in the
+// operation's argument list
+type HarnessMethod struct {
+	Name       string
+	Params     []HarnessParam
+	ReturnType domain.GenericType
+	Void       bool
+}
+
+// HarnessCustomType is a custom type of the signature with its implementation in the
+// harness's language
+type HarnessCustomType struct {
+	Name             string
+	ClassDefinition  string
+	DeserializerCode string
+	SerializerCode   string
+}
+
+// ArgNames is the parameter names as a call's argument list
+func (d HarnessData) ArgNames() string {
+	names := make([]string, len(d.Params))
+	for i, param := range d.Params {
+		names[i] = param.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// harnessParams indexes parameters by their position in an argument list
+func harnessParams(params []domain.SchemaParameter) []HarnessParam {
+	var res []HarnessParam
+	for i, param := range params {
+		res = append(res, HarnessParam{Index: i, Name: param.Name, Type: param.Type})
+	}
+	return res
+}
+
+// harnessDesign is the design class as templates see it. Design problems have no
+// custom types, so nothing needs resolving.
+func harnessDesign(design *domain.DesignSchema) *HarnessDesign {
+	res := &HarnessDesign{ClassName: design.ClassName, Constructor: harnessParams(design.Constructor)}
+	for _, m := range design.Methods {
+		res.Methods = append(res.Methods, HarnessMethod{
+			Name:       m.Name,
+			Params:     harnessParams(m.Parameters),
+			ReturnType: m.ReturnType,
+			Void:       m.IsVoid(),
+		})
+	}
+	return res
+}
+
+// harnessTemplate is the latest version of one language's harness template
+type harnessTemplate struct {
+	name    string
+	version int
+	tmpl    *template.Template
+}
+
+// Version names the template and its version, e.g. "python.v1"
+func (t *harnessTemplate) Version() string {
+	return fmt.Sprintf("%s.v%d", t.name, t.version)
+}
+
+func (t *harnessTemplate) render(data HarnessData) (string, error) {
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render %s harness: %w", t.Version(), err)
+	}
+	return sb.String(), nil
+}
+
+// harnessFuncs are available to every template, next to the language's own
+var harnessFuncs = template.FuncMap{
+	"lower": func(v interface{}) string { return strings.ToLower(fmt.Sprint(v)) },
+}
+
+// mustLoadHarnessTemplate parses the latest version of a language's template with
+// the language's helper functions. Templates are embedded, so a broken one is a bug
+// caught when the package loads.
+func mustLoadHarnessTemplate(name string, funcs template.FuncMap) *harnessTemplate {
+	entries, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+
+	var latest *harnessTemplate
+	var file string
+	for _, entry := range entries {
+		m := templateFileRe.FindStringSubmatch(entry.Name())
+		if m == nil || m[1] != name {
+			continue
+		}
+		version, _ := strconv.Atoi(m[2])
+		if latest == nil || version > latest.version {
+			latest = &harnessTemplate{name: name, version: version}
+			file = entry.Name()
+		}
+	}
+	if latest == nil {
+		panic(fmt.Sprintf("codegen: no harness template for %s", name))
+	}
+
+	src, err := templateFS.ReadFile(path.Join("templates", file))
+	if err != nil {
+		panic(err)
+	}
+	latest.tmpl = template.Must(template.New(file).Funcs(harnessFuncs).Funcs(funcs).Parse(string(src)))
+	return latest
+}
+
+// mappedType adapts a type mapping to the types templates see, which are unparsed.
+// Types that don't parse are used as written.
+func mappedType(mapType func(*domain.TypeExpr) string) func(domain.GenericType) string {
+	return func(typ domain.GenericType) string {
+		t, err := typ.Parse()
+		if err != nil {
+			return string(typ)
+		}
+		return mapType(t)
+	}
+}
+
+// harnessData resolves the signature's custom types for a language
+func (s *CodeGenService) harnessData(sig domain.ProblemSchema, userCode, validationType, languageSlug string) (HarnessData, error) {
+	if sig.Design != nil {
+		return HarnessData{
+			Design:             harnessDesign(sig.Design),
+			UserCode:           userCode,
+			ValidationType:     validationType,
+			DefaultTimeLimitMs: defaultHarnessTimeLimitMs,
+			ResultMarker:       HarnessResultMarker,
+		}, nil
+	}
+
+	customTypes := s.identifyCustomTypes(sig)
+	impls, err := s.typeImplementations(customTypes, languageSlug)
+	if err != nil {
+		return HarnessData{}, err
+	}
+
+	data := HarnessData{
+		FunctionName:       sig.FunctionName,
+		ReturnType:         sig.ReturnType,
+		ReturnsCustom:      containsType(customTypes, sig.ReturnType),
+		UserCode:           userCode,
+		ValidationType:     validationType,
+		DefaultTimeLimitMs: defaultHarnessTimeLimitMs,
+		ResultMarker:       HarnessResultMarker,
+	}
+	for i, param := range sig.Parameters {
+		data.Params = append(data.Params, HarnessParam{
+			Index:  i,
+			Name:   param.Name,
+			Type:   param.Type,
+			Custom: isCustomParam(param, customTypes),
+		})
+	}
+	for i, impl := range impls {
+		data.CustomTypes = append(data.CustomTypes, HarnessCustomType{
+			Name:             customTypes[i],
+			ClassDefinition:  impl.ClassDefinition,
+			DeserializerCode: impl.DeserializerCode,
+			SerializerCode:   impl.SerializerCode,
+		})
+	}
+	return data, nil
+}
+
+// templateGenerator is a LanguageGenerator whose harness is rendered from an
+// embedded template
+type templateGenerator struct {
+	s    *CodeGenService
+	slug string
+	stub func(sig domain.ProblemSchema, customTypes []string) (string, error)
+	// designStub writes the class of a design problem, nil if the language has none
+	designStub func(design *domain.DesignSchema) string
+	harness    *harnessTemplate
+	// check rejects signatures the language has no form for, if any
+	check func(sig domain.ProblemSchema) error
+}
+
+func (g *templateGenerator) GenerateStub(sig domain.ProblemSchema, customTypes []string) (string, error) {
+	if sig.Design != nil {
+		if g.designStub == nil {
+			return "", g.designUnsupported()
+		}
+		return g.designStub(sig.Design), nil
+	}
+	return g.stub(sig, customTypes)
+}
+
+func (g *templateGenerator) GenerateHarness(sig domain.ProblemSchema, userCode string, testCases []domain.TestCase, validationType string) (string, error) {
+	if sig.Design != nil && g.designStub == nil {
+		return "", g.designUnsupported()
+	}
+	if g.check != nil {
+		if err := g.check(sig); err != nil {
+			return "", err
+		}
+	}
+	data, err := g.s.harnessData(sig, userCode, validationType, g.slug)
+	if err != nil {
+		return "", err
+	}
+	return g.harness.render(data)
+}
+
+// SupportsDesign reports whether the language has a form for design problems
+func (g *templateGenerator) SupportsDesign() bool {
+	return g.designStub != nil
+}
+
+func (g *templateGenerator) designUnsupported() error {
+	return fmt.Errorf("design problems are not supported in %s", g.slug)
+}
+
+// HarnessVersion is the version of the template the harness is rendered from
+func (g *templateGenerator) HarnessVersion() string {
+	return g.harness.Version()
+}
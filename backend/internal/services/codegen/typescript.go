@@ -0,0 +1,67 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+func (s *CodeGenService) mapTypeToTypeScript(typ domain.GenericType) string {
+	t, err := typ.Parse()
+	if err != nil {
+		return string(typ)
+	}
+	return typeScriptType(t)
+}
+
+func typeScriptType(t *domain.TypeExpr) string {
+	var base string
+	switch t.Kind {
+	case domain.KindInt, domain.KindLong, domain.KindDouble:
+		base = "number"
+	case domain.KindBool:
+		base = "boolean"
+	case domain.KindChar, domain.KindString:
+		base = "string"
+	case domain.KindList:
+		elem := typeScriptType(t.Elem)
+		if t.Elem.Nullable {
+			elem = "(" + elem + ")"
+		}
+		base = elem + "[]"
+	case domain.KindMap:
+		base = "Record<" + typeScriptType(t.Key) + ", " + typeScriptType(t.Elem) + ">"
+	case domain.KindCustom:
+		return t.Name + " | null"
+	}
+	if t.Nullable {
+		return base + " | null"
+	}
+	return base
+}
+
+// generateTypeScriptStub writes a plain function typed with the schema's types
+func (s *CodeGenService) generateTypeScriptStub(sig domain.ProblemSchema, customTypes []string) (string, error) {
+	var sb strings.Builder
+	if err := s.writeDefinitionComments(&sb, customTypes, "typescript"); err != nil {
+		return "", err
+	}
+
+	params := []string{}
+	for _, param := range sig.Parameters {
+		params = append(params, fmt.Sprintf("%s: %s", param.Name, s.mapTypeToTypeScript(param.Type)))
+	}
+	sb.WriteString(fmt.Sprintf("function %s(%s): %s {\n", sig.FunctionName, strings.Join(params, ", "), s.mapTypeToTypeScript(sig.ReturnType)))
+	sb.WriteString("    // Write your code here\n")
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// typeScriptHarness uses values as JSON.parse returns them, like the JavaScript
+// harness, so maps are plain objects typed as Record. It reaches Node through
+// globalThis, so it compiles without @types/node.
+var typeScriptHarness = mustLoadHarnessTemplate("typescript", template.FuncMap{
+	"tsType": mappedType(typeScriptType),
+})
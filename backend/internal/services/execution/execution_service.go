@@ -7,17 +7,23 @@ import (
 	"fmt"
 
 	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/checker"
 	"github.com/prabalesh/loco/backend/internal/services/codegen"
-	"github.com/prabalesh/loco/backend/internal/services/piston"
+	"github.com/prabalesh/loco/backend/internal/services/stdio"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
 	"golang.org/x/sync/errgroup"
 )
 
 type ExecutionService struct {
-	pistonClient       *piston.PistonClient
-	languageMapper     *piston.LanguageMapper
+	executor           executor.Executor
+	checker            *checker.CheckerService
+	stdio              *stdio.Runner
 	boilerplateService *codegen.BoilerplateService
 	codegenService     *codegen.CodeGenService
 	problemRepo        domain.ProblemRepository
+	languageRepo       domain.LanguageRepository
+	testData           *testdata.TestDataService
 }
 
 type ExecutionRequest struct {
@@ -37,13 +43,16 @@ type ExecutionResult struct {
 	ErrorMessage string                  `json:"error_message,omitempty"`
 }
 
-func NewExecutionService(pistonURL string, boilerplateService *codegen.BoilerplateService, codegenService *codegen.CodeGenService, problemRepo domain.ProblemRepository, executionRepo domain.PistonExecutionRepository) *ExecutionService {
+func NewExecutionService(exec executor.Executor, boilerplateService *codegen.BoilerplateService, codegenService *codegen.CodeGenService, problemRepo domain.ProblemRepository, languageRepo domain.LanguageRepository, testData *testdata.TestDataService) *ExecutionService {
 	return &ExecutionService{
-		pistonClient:       piston.NewPistonClient(pistonURL, executionRepo),
-		languageMapper:     piston.NewLanguageMapper(),
+		executor:           exec,
+		checker:            checker.NewCheckerService(exec),
+		stdio:              stdio.NewRunner(exec),
 		boilerplateService: boilerplateService,
 		codegenService:     codegenService,
 		problemRepo:        problemRepo,
+		languageRepo:       languageRepo,
+		testData:           testData,
 	}
 }
 
@@ -68,37 +77,28 @@ func (s *ExecutionService) ExecuteBatchSubmission(ctx context.Context, req Execu
 		return nil, fmt.Errorf("failed to get problem: %w", err)
 	}
 
-	var params []domain.SchemaParameter
-	if problem.Parameters != nil {
-		json.Unmarshal(*problem.Parameters, &params)
+	// The language only contributes limit multipliers, so run unscaled without it
+	language, err := s.languageRepo.GetBySlug(languageSlug)
+	if err != nil {
+		language = nil
 	}
 
-	schema := domain.ProblemSchema{
-		FunctionName: func() string {
-			if problem.FunctionName != nil {
-				return *problem.FunctionName
-			}
-			return ""
-		}(),
-		ReturnType: domain.GenericType(func() string {
-			if problem.ReturnType != nil {
-				return *problem.ReturnType
-			}
-			return "int"
-		}()),
-		Parameters: params,
+	// Stdio and interactive problems run the code as it is, without a harness
+	if problem.IsWholeProgram() {
+		return s.executeStdio(ctx, problem, language, languageSlug, req)
 	}
 
-	// 2. Generate Universal Harness (same code for all batches)
-	fullCode, err := s.codegenService.GenerateTestHarness(schema, req.UserCode, languageSlug, []domain.TestCase{}, problem.ValidationType)
+	schema, err := problem.Schema()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate harness: %w", err)
+		return nil, err
 	}
 
-	runtime, err := s.languageMapper.GetPistonRuntime(languageSlug)
+	// 2. Generate Universal Harness (same code for all batches)
+	fullCode, err := s.codegenService.GenerateTestHarness(schema, req.UserCode, languageSlug, []domain.TestCase{}, problem.ValidationType)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to generate harness: %w", err)
 	}
+	nonce := s.harnessNonce(languageSlug)
 
 	// 3. Batching
 	batchSize := 8
@@ -125,53 +125,64 @@ func (s *ExecutionService) ExecuteBatchSubmission(ctx context.Context, req Execu
 			default:
 			}
 
-			// Prepare STDIN for batch
-			stdinBytes, _ := json.Marshal(batch)
-			stdin := string(stdinBytes)
-
-			pistonReq := piston.ExecuteRequest{
-				Language: runtime.Language,
-				Version:  runtime.Version,
-				Files: []piston.File{
-					{
-						Name:    runtime.FileName,
-						Content: fullCode,
-					},
-				},
-				Stdin:          stdin,
-				CompileTimeout: 10000,
-				RunTimeout:     5000,
-				ProblemID:      req.ProblemID,
-				SubmissionID:   nil, // Test runs don't have submission ID here
+			// Prepare STDIN for batch; results keep the values as they are stored
+			loaded, err := s.testData.Load(gCtx, batch)
+			if err != nil {
+				return err
+			}
+			stdin, limits, err := codegen.BuildHarnessInput(loaded, problem, language, nonce)
+			if err != nil {
+				return err
+			}
+
+			execReq := &executor.Request{
+				Language:      languageSlug,
+				Code:          fullCode,
+				Stdin:         stdin,
+				StdinKeys:     testdata.Keys(batch),
+				ProblemID:     req.ProblemID,
+				SubmissionID:  nil, // Test runs don't have submission ID here
+				TimeLimitMs:   limits.TimeMs,
+				MemoryLimitMb: limits.MemoryMb,
 			}
 
-			fmt.Printf("\n--- Piston Execution Request ---\n")
-			fmt.Printf("Stdin (batch size %d): %s\n", len(batch), stdin)
-			fmt.Printf("Time Limit (RunTimeout): %d ms\n", pistonReq.RunTimeout)
-			fmt.Printf("Compile Timeout: %d ms\n", pistonReq.CompileTimeout)
-			fmt.Printf("Problem Memory Limit: %d MB\n", problem.MemoryLimit)
+			fmt.Printf("\n--- Execution Request ---\n")
+			fmt.Printf("Stdin (batch size %d): %s\n", len(batch), execReq.LoggedStdin(0))
+			fmt.Printf("Limits: %d ms, %d MB\n", limits.TimeMs, limits.MemoryMb)
 			fmt.Printf("-------------------------------\n\n")
 
-			pistonResp, err := s.pistonClient.Execute(pistonReq)
+			execRes, err := s.executor.Execute(gCtx, execReq)
 			if err != nil {
 				return err
 			}
 
 			// Check for compilation errors
-			if pistonResp.Compile != nil && pistonResp.Compile.Code != 0 {
+			if execRes.CompileFailed {
 				results[i] = &ExecutionResult{
 					Status:       domain.SubmissionStatusCompilationError,
-					ErrorMessage: pistonResp.Compile.Stderr,
+					ErrorMessage: execRes.Error,
 				}
 				return fmt.Errorf("compilation error") // Stop other batches
 			}
 
 			// Parse results for this batch
-			batchRes, err := s.validateOutput(pistonResp.Run.Stdout, batch)
+			batchRes, err := s.validateOutput(execRes.Output, nonce, batch)
 			if err != nil {
 				return err
 			}
 
+			if problem.UsesChecker() {
+				if err := s.applyChecker(gCtx, problem, loaded, batchRes); err != nil {
+					return err
+				}
+			}
+
+			// Stopped by a limit or a signal before the harness could report
+			if execRes.Termination != executor.TerminationNone && len(batchRes.TestResults) == 0 {
+				batchRes.Status = execRes.Termination.SubmissionStatus()
+				batchRes.ErrorMessage = execRes.Error
+			}
+
 			results[i] = batchRes
 
 			// Short circuit WA/TLE/RE in batches
@@ -224,25 +235,222 @@ func (s *ExecutionService) ExecuteBatchSubmission(ctx context.Context, req Execu
 	return finalResult, nil
 }
 
+// executeStdio judges a whole program on each test's raw input
+func (s *ExecutionService) executeStdio(ctx context.Context, problem *domain.Problem, language *domain.Language, languageSlug string, req ExecutionRequest) (*ExecutionResult, error) {
+	testCases, err := s.testData.Load(ctx, req.TestCases)
+	if err != nil {
+		return nil, err
+	}
+	prog := stdio.Program{LanguageSlug: languageSlug, Code: req.UserCode, Language: language}
+	res, err := s.stdio.Run(ctx, problem, prog, testCases)
+	if errors.Is(err, checker.ErrCheckerFailed) || errors.Is(err, executor.ErrInteractiveUnsupported) {
+		return &ExecutionResult{
+			Status:       domain.SubmissionStatusInternalError,
+			TotalTests:   len(req.TestCases),
+			TestResults:  []domain.TestCaseResult{},
+			ErrorMessage: err.Error(),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if res.CompileFailed {
+		return &ExecutionResult{
+			Status:       domain.SubmissionStatusCompilationError,
+			TotalTests:   len(req.TestCases),
+			TestResults:  []domain.TestCaseResult{},
+			ErrorMessage: res.CompileError,
+		}, nil
+	}
+
+	status, message, passed := res.Verdict(problem, len(req.TestCases))
+	return &ExecutionResult{
+		Status:       status,
+		TestResults:  res.TestResults,
+		TotalTests:   len(req.TestCases),
+		PassedTests:  passed,
+		Runtime:      res.Runtime,
+		Memory:       res.Memory,
+		ErrorMessage: message,
+	}, nil
+}
+
+// ComputeOutputs runs code on the test cases without judging it, for computing
+// their expected outputs from a reference solution. Every test runs; a result
+// passes when the code answered, and its ActualOutput is then the answer encoded
+// the way expected outputs are stored. Interactive problems have no outputs.
+func (s *ExecutionService) ComputeOutputs(ctx context.Context, problem *domain.Problem, languageSlug, code string, testCases []domain.TestCase) (*ExecutionResult, error) {
+	language, err := s.languageRepo.GetBySlug(languageSlug)
+	if err != nil {
+		language = nil
+	}
+	testCases, err = s.testData.Load(ctx, testCases)
+	if err != nil {
+		return nil, err
+	}
+
+	if problem.IsWholeProgram() {
+		prog := stdio.Program{LanguageSlug: languageSlug, Code: code, Language: language}
+		res, err := s.stdio.Outputs(ctx, problem, prog, testCases)
+		if err != nil {
+			return nil, err
+		}
+		if res.CompileFailed {
+			return &ExecutionResult{Status: domain.SubmissionStatusCompilationError, TotalTests: len(testCases), TestResults: []domain.TestCaseResult{}, ErrorMessage: res.CompileError}, nil
+		}
+		return outputsResult(problem, res.TestResults, len(testCases)), nil
+	}
+
+	schema, err := problem.Schema()
+	if err != nil {
+		return nil, err
+	}
+	fullCode, err := s.codegenService.GenerateTestHarness(schema, code, languageSlug, []domain.TestCase{}, problem.ValidationType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate harness: %w", err)
+	}
+	nonce := s.harnessNonce(languageSlug)
+
+	// One batch after the other, so a failing test doesn't stop the rest
+	const batchSize = 8
+	rows := make([]domain.TestCaseResult, 0, len(testCases))
+	for start := 0; start < len(testCases); start += batchSize {
+		batch := testCases[start:min(start+batchSize, len(testCases))]
+		stdin, limits, err := codegen.BuildHarnessInput(batch, problem, language, nonce)
+		if err != nil {
+			return nil, err
+		}
+		execRes, err := s.executor.Execute(ctx, &executor.Request{
+			Language:      languageSlug,
+			Code:          fullCode,
+			Stdin:         stdin,
+			StdinKeys:     testdata.Keys(batch),
+			ProblemID:     problem.ID,
+			TimeLimitMs:   limits.TimeMs,
+			MemoryLimitMb: limits.MemoryMb,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if execRes.CompileFailed {
+			return &ExecutionResult{Status: domain.SubmissionStatusCompilationError, TotalTests: len(testCases), TestResults: []domain.TestCaseResult{}, ErrorMessage: execRes.Error}, nil
+		}
+
+		var verdict harnessVerdict
+		_, result := codegen.SplitHarnessOutput(execRes.Output, nonce)
+		_ = json.Unmarshal([]byte(result), &verdict)
+		for i := range batch {
+			row := domain.TestCaseResult{
+				TestID:   start + i + 1,
+				Input:    batch[i].Input,
+				IsSample: batch[i].IsSample,
+			}
+			switch {
+			case i >= len(verdict.TestResults):
+				// The harness died before it reported this test
+				row.Status = domain.TestStatusRuntimeError
+				if execRes.Termination != executor.TerminationNone {
+					row.Status = execRes.Termination.TestStatus()
+				}
+				row.Error = executor.TruncateOutput(execRes.Error, executor.MaxStoredTestOutputBytes)
+			case verdict.TestResults[i].Status == domain.TestStatusPassed || verdict.TestResults[i].Status == domain.TestStatusFailed:
+				// Compared with an expected output that isn't known yet, so either way
+				// the code answered; harnesses print a null answer as nothing
+				row.Status = domain.TestStatusPassed
+				row.ActualOutput = verdict.TestResults[i].Actual
+				if row.ActualOutput == "" {
+					row.ActualOutput = "null"
+				}
+			default:
+				row.Status = verdict.TestResults[i].Status
+				row.Error = verdict.TestResults[i].Error
+			}
+			rows = append(rows, row)
+		}
+	}
+	return outputsResult(problem, rows, len(testCases)), nil
+}
+
+// outputsResult sums up the results of ComputeOutputs
+func outputsResult(problem *domain.Problem, rows []domain.TestCaseResult, total int) *ExecutionResult {
+	res := &ExecutionResult{Status: domain.SubmissionStatusAccepted, TestResults: rows, TotalTests: total}
+	for i := range rows {
+		if rows[i].Status != domain.TestStatusPassed {
+			if res.Status == domain.SubmissionStatusAccepted {
+				res.Status, res.ErrorMessage = rows[i].Verdict(problem)
+			}
+			continue
+		}
+		res.PassedTests++
+		res.Runtime = max(res.Runtime, rows[i].TimeMS)
+		res.Memory = max(res.Memory, rows[i].MemoryKB)
+	}
+	return res
+}
+
+// applyChecker lets the problem's checker judge the answers the harness passed. A
+// broken checker turns the batch into an internal error; executor failures are
+// returned.
+func (s *ExecutionService) applyChecker(ctx context.Context, problem *domain.Problem, testCases []domain.TestCase, batchRes *ExecutionResult) error {
+	for i := range batchRes.TestResults {
+		tr := &batchRes.TestResults[i]
+		if i >= len(testCases) || tr.Status != "Passed" {
+			continue
+		}
+
+		verdict, err := s.checker.Check(ctx, problem, &testCases[i], tr.ActualOutput)
+		if errors.Is(err, checker.ErrCheckerFailed) {
+			batchRes.Status = domain.SubmissionStatusInternalError
+			batchRes.ErrorMessage = fmt.Sprintf("test %d: %v", tr.TestID, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !verdict.Accepted {
+			tr.Status = "Failed"
+			tr.Error = verdict.Message
+			batchRes.PassedTests--
+			if batchRes.Status == domain.SubmissionStatusAccepted {
+				batchRes.Status = domain.SubmissionStatusWrongAnswer
+			}
+		}
+	}
+	return nil
+}
+
 type harnessVerdict struct {
 	Verdict     string `json:"verdict"`
 	Runtime     int    `json:"runtime"`
 	Memory      int    `json:"memory"`
 	TestResults []struct {
 		Passed bool   `json:"passed"`
+		Status string `json:"status"`
 		Input  string `json:"input"`
 		Actual string `json:"actual"`
 		Error  string `json:"error"`
+		Stdout string `json:"stdout"`
 	} `json:"test_results"`
 }
 
+// harnessNonce returns a nonce for a run of a freshly generated harness, or none
+// when the language's generator doesn't print one
+func (s *ExecutionService) harnessNonce(languageSlug string) string {
+	if !codegen.HarnessTakesNonce(s.codegenService.HarnessVersion(languageSlug)) {
+		return ""
+	}
+	return codegen.NewHarnessNonce()
+}
+
 // validateOutput parses stdout from the harness and aggregates results
-func (s *ExecutionService) validateOutput(stdout string, testCases []domain.TestCase) (*ExecutionResult, error) {
+func (s *ExecutionService) validateOutput(stdout, nonce string, testCases []domain.TestCase) (*ExecutionResult, error) {
+	_, result := codegen.SplitHarnessOutput(stdout, nonce)
+
 	var verdict harnessVerdict
-	if err := json.Unmarshal([]byte(stdout), &verdict); err != nil {
+	if err := json.Unmarshal([]byte(result), &verdict); err != nil {
 		return &ExecutionResult{
 			Status:       domain.SubmissionStatusRuntimeError,
-			ErrorMessage: fmt.Sprintf("Failed to parse output: %v\nRaw output: %s", err, stdout),
+			ErrorMessage: fmt.Sprintf("Failed to parse output: %v\nRaw output: %s", err, executor.TruncateOutput(stdout, executor.MaxStoredOutputBytes)),
 			TestResults:  []domain.TestCaseResult{},
 		}, nil
 	}
@@ -255,6 +463,8 @@ func (s *ExecutionService) validateOutput(stdout string, testCases []domain.Test
 		overallStatus = domain.SubmissionStatusWrongAnswer
 	case "TLE":
 		overallStatus = domain.SubmissionStatusTimeLimitExceeded
+	case "MLE":
+		overallStatus = domain.SubmissionStatusMemoryLimitExceeded
 	case "RUNTIME_ERROR":
 		overallStatus = domain.SubmissionStatusRuntimeError
 	default:
@@ -265,7 +475,9 @@ func (s *ExecutionService) validateOutput(stdout string, testCases []domain.Test
 	passedCount := 0
 	for i, tr := range verdict.TestResults {
 		status := "Passed"
-		if !tr.Passed {
+		if tr.Status == domain.TestStatusMemoryExceeded || tr.Status == domain.TestStatusOutputExceeded {
+			status = tr.Status
+		} else if !tr.Passed {
 			status = "Failed"
 		} else {
 			passedCount++
@@ -277,11 +489,13 @@ func (s *ExecutionService) validateOutput(stdout string, testCases []domain.Test
 			Input:        tr.Input,
 			ActualOutput: tr.Actual,
 			Error:        tr.Error,
+			Stdout:       executor.TruncateOutput(tr.Stdout, executor.MaxStoredTestOutputBytes),
 		}
 
 		if i < len(testCases) {
 			results[i].IsSample = testCases[i].IsSample
 			results[i].ExpectedOutput = testCases[i].ExpectedOutput
+			results[i].Subtask = testCases[i].Subtask
 		}
 	}
 
@@ -0,0 +1,372 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/domain/uerror"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/execution"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
+)
+
+// Limits for a single generator run; generators are trusted but still sandboxed
+const (
+	GeneratorTimeLimitMs   = 5000
+	GeneratorMemoryLimitMb = 256
+	maxMessageBytes        = 1024
+)
+
+// Most test cases one request may generate and most inputs one stress test may try
+const (
+	MaxGeneratedTests   = 100
+	MaxStressIterations = 500
+)
+
+// stressRoundSize is how many inputs a stress test tries per run of the candidate;
+// it fits in one harness batch, so a round's results are in the order of its inputs
+const stressRoundSize = 8
+
+var (
+	// ErrNoGenerator means the problem has no generator to run
+	ErrNoGenerator = errors.New("problem has no generator")
+
+	// ErrNoReference means the problem has no validated reference solution to compute
+	// expected outputs with
+	ErrNoReference = errors.New("problem has no validated reference solution")
+
+	// ErrGeneratorFailed means the generator itself is broken (didn't compile,
+	// crashed or printed something that isn't a test input)
+	ErrGeneratorFailed = errors.New("generator failed")
+
+	// ErrReferenceFailed means the reference solution didn't answer a generated input
+	ErrReferenceFailed = errors.New("reference solution failed")
+)
+
+// generatorInput is what a generator reads from stdin
+type generatorInput struct {
+	Seed int64 `json:"seed"`
+	Size int   `json:"size"`
+}
+
+// GeneratorService produces test cases from a problem's generator. A generator is
+// a plain program in any supported language that reads one generatorInput as JSON
+// from stdin and prints a test input: the JSON value of the input for function and
+// design problems, the text itself for stdio problems. It must print the same input
+// for the same seed and size. Expected outputs come from the problem's validated
+// reference solution.
+type GeneratorService struct {
+	executor      executor.Executor
+	execution     *execution.ExecutionService
+	problemRepo   domain.ProblemRepository
+	testCaseRepo  domain.TestCaseRepository
+	referenceRepo domain.ReferenceSolutionRepository
+	testData      *testdata.TestDataService
+}
+
+func NewGeneratorService(
+	exec executor.Executor,
+	executionService *execution.ExecutionService,
+	problemRepo domain.ProblemRepository,
+	testCaseRepo domain.TestCaseRepository,
+	referenceRepo domain.ReferenceSolutionRepository,
+	testData *testdata.TestDataService,
+) *GeneratorService {
+	return &GeneratorService{
+		executor:      exec,
+		execution:     executionService,
+		problemRepo:   problemRepo,
+		testCaseRepo:  testCaseRepo,
+		referenceRepo: referenceRepo,
+		testData:      testData,
+	}
+}
+
+// GenerateRequest asks for Count test cases from the seeds Seed, Seed+1, ...
+type GenerateRequest struct {
+	Count    int   `json:"count"`
+	Seed     int64 `json:"seed"`
+	Size     int   `json:"size"`
+	IsSample bool  `json:"is_sample"`
+
+	// Subtask is the name of the problem's subtask the tests go to, if any
+	Subtask string `json:"subtask"`
+
+	// ReferenceLanguage is the slug of the reference solution computing the expected
+	// outputs; empty picks any validated one
+	ReferenceLanguage string `json:"reference_language"`
+}
+
+// StressRequest asks to run a candidate solution against the reference on
+// Iterations generated inputs from the seeds Seed, Seed+1, ...
+type StressRequest struct {
+	LanguageSlug      string `json:"language_slug"`
+	Code              string `json:"code"`
+	Iterations        int    `json:"iterations"`
+	Seed              int64  `json:"seed"`
+	Size              int    `json:"size"`
+	ReferenceLanguage string `json:"reference_language"`
+
+	// SaveDivergence keeps the input the candidate diverged on as a hidden test case
+	SaveDivergence bool `json:"save_divergence"`
+}
+
+// StressResult is the outcome of a stress test: Accepted when the candidate
+// answered every input like the reference, otherwise its verdict on the first input
+// it didn't
+type StressResult struct {
+	Status       domain.SubmissionStatus `json:"status"`
+	Iterations   int                     `json:"iterations"` // inputs the candidate was judged on
+	ErrorMessage string                  `json:"error_message,omitempty"`
+	Divergence   *Divergence             `json:"divergence,omitempty"`
+	TestCase     *domain.TestCase        `json:"test_case,omitempty"` // the saved divergence
+}
+
+// Divergence is the first input a stress test found the candidate wrong on
+type Divergence struct {
+	Iteration int    `json:"iteration"`
+	Seed      int64  `json:"seed"`
+	Input     string `json:"input"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual"`
+	Error     string `json:"error,omitempty"`
+}
+
+// GenerateInput runs the problem's generator once. Errors wrap ErrGeneratorFailed
+// when the generator is at fault; any other error comes from the executor.
+func (s *GeneratorService) GenerateInput(ctx context.Context, problem *domain.Problem, seed int64, size int) (string, error) {
+	if !problem.HasGenerator() {
+		return "", ErrNoGenerator
+	}
+
+	stdin, err := json.Marshal(generatorInput{Seed: seed, Size: size})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode generator input: %w", err)
+	}
+	res, err := s.executor.Execute(ctx, &executor.Request{
+		ProblemID:     problem.ID,
+		Language:      *problem.GeneratorLanguage,
+		Code:          *problem.GeneratorCode,
+		Stdin:         string(stdin),
+		TimeLimitMs:   GeneratorTimeLimitMs,
+		MemoryLimitMb: GeneratorMemoryLimitMb,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case res.CompileFailed:
+		return "", fmt.Errorf("%w: compilation error: %s", ErrGeneratorFailed, executor.TruncateOutput(res.Error, maxMessageBytes))
+	case res.Termination != executor.TerminationNone:
+		return "", fmt.Errorf("%w: seed %d: %s", ErrGeneratorFailed, seed, executor.TruncateOutput(res.Error, maxMessageBytes))
+	case res.ExitCode != 0:
+		return "", fmt.Errorf("%w: seed %d: exit code %d: %s", ErrGeneratorFailed, seed, res.ExitCode, executor.TruncateOutput(res.Error, maxMessageBytes))
+	}
+
+	if problem.IsWholeProgram() {
+		return res.Output, nil
+	}
+	input := strings.TrimSpace(res.Output)
+	if !json.Valid([]byte(input)) {
+		return "", fmt.Errorf("%w: seed %d: output is not JSON: %s", ErrGeneratorFailed, seed, executor.TruncateOutput(input, maxMessageBytes))
+	}
+	return input, nil
+}
+
+// GenerateTestCases generates test cases, computes their expected outputs with the
+// reference solution and saves them after the problem's other test cases
+func (s *GeneratorService) GenerateTestCases(ctx context.Context, problemID int, req GenerateRequest) ([]domain.TestCase, error) {
+	if req.Count < 1 || req.Count > MaxGeneratedTests {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"count": fmt.Sprintf("count must be between 1 and %d", MaxGeneratedTests)}}
+	}
+	problem, reference, err := s.load(problemID, req.ReferenceLanguage)
+	if err != nil {
+		return nil, err
+	}
+	if req.Subtask != "" && !problem.Subtasks.Has(req.Subtask) {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"subtask": fmt.Sprintf("subtask %q is not defined", req.Subtask)}}
+	}
+
+	testCases, err := s.generate(ctx, problem, reference, req.Seed, req.Size, req.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.testCaseRepo.CountByProblemID(problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count test cases: %w", err)
+	}
+	for i := range testCases {
+		testCases[i].Source = domain.TestSourceGenerated
+		testCases[i].IsSample = req.IsSample
+		testCases[i].Subtask = req.Subtask
+		testCases[i].OrderIndex = count + i
+		if err := s.testData.Offload(ctx, &testCases[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.testCaseRepo.CreateMany(testCases); err != nil {
+		return nil, fmt.Errorf("failed to save test cases: %w", err)
+	}
+	return testCases, nil
+}
+
+// Stress runs a candidate solution on generated inputs, a round at a time, until it
+// answers one differently than the reference solution or every input passes
+func (s *GeneratorService) Stress(ctx context.Context, problemID int, req StressRequest) (*StressResult, error) {
+	if req.Code == "" || req.LanguageSlug == "" {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"code": "language_slug and code are required"}}
+	}
+	if req.Iterations < 1 || req.Iterations > MaxStressIterations {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"iterations": fmt.Sprintf("iterations must be between 1 and %d", MaxStressIterations)}}
+	}
+	problem, reference, err := s.load(problemID, req.ReferenceLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StressResult{Status: domain.SubmissionStatusAccepted}
+	for start := 0; start < req.Iterations; start += stressRoundSize {
+		round, err := s.generate(ctx, problem, reference, req.Seed+int64(start), req.Size, min(stressRoundSize, req.Iterations-start))
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := s.execution.ExecuteBatchSubmission(ctx, execution.ExecutionRequest{
+			ProblemID: problemID,
+			UserCode:  req.Code,
+			TestCases: round,
+		}, req.LanguageSlug)
+		if err != nil {
+			return nil, err
+		}
+		if res.Status == domain.SubmissionStatusCompilationError {
+			result.Status = res.Status
+			result.ErrorMessage = res.ErrorMessage
+			return result, nil
+		}
+		if res.Status == domain.SubmissionStatusAccepted {
+			result.Iterations += len(round)
+			continue
+		}
+
+		// The run stopped before reporting on any input when no row failed, so the
+		// round's first input is all it can be pinned on
+		k := 0
+		var row *domain.TestCaseResult
+		for i := range res.TestResults {
+			if !strings.EqualFold(res.TestResults[i].Status, domain.TestStatusPassed) {
+				k, row = res.TestResults[i].TestID-1, &res.TestResults[i]
+				break
+			}
+		}
+		if k < 0 || k >= len(round) {
+			k = 0
+		}
+
+		result.Status = res.Status
+		result.ErrorMessage = res.ErrorMessage
+		result.Iterations += k + 1
+		result.Divergence = &Divergence{
+			Iteration: start + k + 1,
+			Seed:      *round[k].GeneratorSeed,
+			Input:     round[k].Input,
+			Expected:  round[k].ExpectedOutput,
+		}
+		if row != nil {
+			result.Divergence.Actual = row.ActualOutput
+			result.Divergence.Error = row.Error
+		}
+
+		if req.SaveDivergence {
+			tc := round[k]
+			tc.Source = domain.TestSourceStress
+			if tc.OrderIndex, err = s.testCaseRepo.CountByProblemID(problemID); err != nil {
+				return nil, fmt.Errorf("failed to count test cases: %w", err)
+			}
+			if err := s.testData.Offload(ctx, &tc); err != nil {
+				return nil, err
+			}
+			if err := s.testCaseRepo.Create(&tc); err != nil {
+				return nil, fmt.Errorf("failed to save test case: %w", err)
+			}
+			result.TestCase = &tc
+		}
+		return result, nil
+	}
+	return result, nil
+}
+
+// load fetches a problem that can have tests generated and the reference solution
+// in the given language, or any validated one
+func (s *GeneratorService) load(problemID int, referenceLanguage string) (*domain.Problem, *domain.ProblemReferenceSolution, error) {
+	problem, err := s.problemRepo.GetByID(problemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem not found: %w", err)
+	}
+	if problem.IsInteractive() {
+		return nil, nil, &uerror.ValidationError{Errors: map[string]string{"judge_mode": "interactive problems can't have tests generated"}}
+	}
+	if !problem.HasGenerator() {
+		return nil, nil, ErrNoGenerator
+	}
+
+	solutions, err := s.referenceRepo.GetAllByProblemID(problemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch reference solutions: %w", err)
+	}
+	for i := range solutions {
+		if solutions[i].IsValidated && (referenceLanguage == "" || solutions[i].Language.Slug == referenceLanguage) {
+			return problem, &solutions[i], nil
+		}
+	}
+	if referenceLanguage != "" {
+		return nil, nil, fmt.Errorf("%w in %s", ErrNoReference, referenceLanguage)
+	}
+	return nil, nil, ErrNoReference
+}
+
+// generate produces count unsaved test cases from the seeds seed, seed+1, ... with
+// their expected outputs computed by the reference solution
+func (s *GeneratorService) generate(ctx context.Context, problem *domain.Problem, reference *domain.ProblemReferenceSolution, seed int64, size, count int) ([]domain.TestCase, error) {
+	testCases := make([]domain.TestCase, count)
+	for i := range testCases {
+		testSeed := seed + int64(i)
+		input, err := s.GenerateInput(ctx, problem, testSeed, size)
+		if err != nil {
+			return nil, err
+		}
+		testCases[i] = domain.TestCase{
+			ProblemID:           problem.ID,
+			Input:               input,
+			Source:              domain.TestSourceGenerated,
+			GeneratorSeed:       &testSeed,
+			GeneratorSize:       &size,
+			ReferenceLanguageID: &reference.LanguageID,
+		}
+	}
+
+	res, err := s.execution.ComputeOutputs(ctx, problem, reference.Language.Slug, reference.Code, testCases)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status == domain.SubmissionStatusCompilationError {
+		return nil, fmt.Errorf("%w: compilation error: %s", ErrReferenceFailed, executor.TruncateOutput(res.ErrorMessage, maxMessageBytes))
+	}
+	for i := range testCases {
+		if i >= len(res.TestResults) || res.TestResults[i].Status != domain.TestStatusPassed {
+			message := res.ErrorMessage
+			if i < len(res.TestResults) {
+				message = res.TestResults[i].Error
+			}
+			return nil, fmt.Errorf("%w: seed %d: %s", ErrReferenceFailed, seed+int64(i), executor.TruncateOutput(message, maxMessageBytes))
+		}
+		testCases[i].ExpectedOutput = res.TestResults[i].ActualOutput
+	}
+	return testCases, nil
+}
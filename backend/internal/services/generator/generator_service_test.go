@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/execution"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
+)
+
+type fakeProblemRepo struct {
+	domain.ProblemRepository
+	problem *domain.Problem
+}
+
+func (r *fakeProblemRepo) GetByID(id int) (*domain.Problem, error) {
+	if r.problem == nil || r.problem.ID != id {
+		return nil, errors.New("problem not found")
+	}
+	return r.problem, nil
+}
+
+type fakeLanguageRepo struct {
+	domain.LanguageRepository
+}
+
+func (r *fakeLanguageRepo) GetBySlug(slug string) (*domain.Language, error) {
+	return nil, errors.New("language not found")
+}
+
+type fakeTestCaseRepo struct {
+	domain.TestCaseRepository
+	saved []domain.TestCase
+}
+
+func (r *fakeTestCaseRepo) CountByProblemID(problemID int) (int, error) { return 5, nil }
+
+func (r *fakeTestCaseRepo) Create(tc *domain.TestCase) error {
+	r.saved = append(r.saved, *tc)
+	return nil
+}
+
+func (r *fakeTestCaseRepo) CreateMany(tcs []domain.TestCase) error {
+	r.saved = append(r.saved, tcs...)
+	return nil
+}
+
+type fakeReferenceRepo struct {
+	domain.ReferenceSolutionRepository
+	solutions []domain.ProblemReferenceSolution
+}
+
+func (r *fakeReferenceRepo) GetAllByProblemID(problemID int) ([]domain.ProblemReferenceSolution, error) {
+	return r.solutions, nil
+}
+
+// sumPrograms answers like a generator printing "seed seed", a reference adding the
+// two numbers it reads and a candidate that gets the sum wrong when they are wrongAt
+func sumPrograms(wrongAt int) func(req *executor.Request) (*executor.Result, error) {
+	return func(req *executor.Request) (*executor.Result, error) {
+		if req.Code == "gen" {
+			var in generatorInput
+			if err := json.Unmarshal([]byte(req.Stdin), &in); err != nil {
+				return &executor.Result{ExitCode: 1, Error: err.Error()}, nil
+			}
+			return &executor.Result{Output: fmt.Sprintf("%d %d\n", in.Seed, in.Seed)}, nil
+		}
+		var a, b int
+		fmt.Sscan(req.Stdin, &a, &b)
+		if req.Code == "candidate" && a == wrongAt {
+			return &executor.Result{Output: "0\n"}, nil
+		}
+		return &executor.Result{Output: fmt.Sprintf("%d\n", a+b)}, nil
+	}
+}
+
+func newTestService(exec executor.Executor) (*GeneratorService, *fakeTestCaseRepo) {
+	generatorLanguage, generatorCode := "python", "gen"
+	problems := &fakeProblemRepo{problem: &domain.Problem{
+		ID:                7,
+		JudgeMode:         domain.JudgeModeStdio,
+		ValidationType:    domain.ValidationTypeTokens,
+		GeneratorLanguage: &generatorLanguage,
+		GeneratorCode:     &generatorCode,
+	}}
+	testCases := &fakeTestCaseRepo{}
+	references := &fakeReferenceRepo{solutions: []domain.ProblemReferenceSolution{
+		{ProblemID: 7, LanguageID: 2, Code: "reference", Language: domain.Language{ID: 2, Slug: "cpp"}},
+		{ProblemID: 7, LanguageID: 1, Code: "reference", IsValidated: true, Language: domain.Language{ID: 1, Slug: "python"}},
+	}}
+	testData := testdata.NewTestDataService(nil, 0)
+	executionService := execution.NewExecutionService(exec, nil, nil, problems, &fakeLanguageRepo{}, testData)
+	return NewGeneratorService(exec, executionService, problems, testCases, references, testData), testCases
+}
+
+func TestGenerateInput(t *testing.T) {
+	exec := executor.NewFakeExecutor(sumPrograms(-1))
+	s, _ := newTestService(exec)
+	problem, _ := s.problemRepo.GetByID(7)
+
+	input, err := s.GenerateInput(context.Background(), problem, 42, 10)
+	if err != nil {
+		t.Fatalf("GenerateInput returned error: %v", err)
+	}
+	if input != "42 42\n" {
+		t.Errorf("Unexpected input %q", input)
+	}
+	if req := exec.Requests()[0]; req.Stdin != `{"seed":42,"size":10}` || req.TimeLimitMs != GeneratorTimeLimitMs {
+		t.Errorf("Unexpected request %+v", req)
+	}
+
+	// Function problems need the input as JSON
+	function := *problem
+	function.JudgeMode = domain.JudgeModeFunction
+	if _, err := s.GenerateInput(context.Background(), &function, 42, 10); !errors.Is(err, ErrGeneratorFailed) {
+		t.Errorf("Expected ErrGeneratorFailed for text input, got %v", err)
+	}
+
+	exec.Handler = func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{CompileFailed: true, Error: "gen.py: SyntaxError"}, nil
+	}
+	if _, err := s.GenerateInput(context.Background(), problem, 1, 10); !errors.Is(err, ErrGeneratorFailed) || !strings.Contains(err.Error(), "SyntaxError") {
+		t.Errorf("Expected ErrGeneratorFailed, got %v", err)
+	}
+
+	problem.GeneratorCode = nil
+	if _, err := s.GenerateInput(context.Background(), problem, 1, 10); !errors.Is(err, ErrNoGenerator) {
+		t.Errorf("Expected ErrNoGenerator, got %v", err)
+	}
+}
+
+func TestGenerateTestCases(t *testing.T) {
+	s, repo := newTestService(executor.NewFakeExecutor(sumPrograms(-1)))
+
+	testCases, err := s.GenerateTestCases(context.Background(), 7, GenerateRequest{Count: 3, Seed: 10, Size: 5})
+	if err != nil {
+		t.Fatalf("GenerateTestCases returned error: %v", err)
+	}
+	if len(testCases) != 3 || len(repo.saved) != 3 {
+		t.Fatalf("Expected 3 saved test cases, got %d", len(repo.saved))
+	}
+	for i, tc := range repo.saved {
+		seed := 10 + i
+		if tc.Input != fmt.Sprintf("%d %d\n", seed, seed) || tc.ExpectedOutput != fmt.Sprintf("%d\n", 2*seed) {
+			t.Errorf("Unexpected test case %d: %q -> %q", i, tc.Input, tc.ExpectedOutput)
+		}
+		if tc.Source != domain.TestSourceGenerated || *tc.GeneratorSeed != int64(seed) || *tc.GeneratorSize != 5 || *tc.ReferenceLanguageID != 1 || tc.OrderIndex != 5+i {
+			t.Errorf("Unexpected provenance of test case %d: %+v", i, tc)
+		}
+	}
+
+	if _, err := s.GenerateTestCases(context.Background(), 7, GenerateRequest{Count: 1, ReferenceLanguage: "cpp"}); !errors.Is(err, ErrNoReference) {
+		t.Errorf("Expected ErrNoReference for an unvalidated reference, got %v", err)
+	}
+}
+
+func TestStressFindsFirstDivergence(t *testing.T) {
+	s, repo := newTestService(executor.NewFakeExecutor(sumPrograms(11)))
+	req := StressRequest{LanguageSlug: "python", Code: "candidate", Iterations: 20, SaveDivergence: true}
+
+	res, err := s.Stress(context.Background(), 7, req)
+	if err != nil {
+		t.Fatalf("Stress returned error: %v", err)
+	}
+	if res.Status != domain.SubmissionStatusWrongAnswer || res.Iterations != 12 || res.Divergence == nil {
+		t.Fatalf("Unexpected result %+v", res)
+	}
+	d := res.Divergence
+	if d.Iteration != 12 || d.Seed != 11 || d.Input != "11 11\n" || d.Expected != "22\n" || d.Actual != "0\n" {
+		t.Errorf("Unexpected divergence %+v", d)
+	}
+	if len(repo.saved) != 1 || repo.saved[0].Source != domain.TestSourceStress || repo.saved[0].Input != "11 11\n" || repo.saved[0].ExpectedOutput != "22\n" {
+		t.Errorf("Expected the divergence to be saved, got %+v", repo.saved)
+	}
+
+	req.Seed = 12
+	if res, err := s.Stress(context.Background(), 7, req); err != nil || res.Status != domain.SubmissionStatusAccepted || res.Iterations != 20 || res.Divergence != nil {
+		t.Errorf("Expected every input to pass, got %+v, %v", res, err)
+	}
+}
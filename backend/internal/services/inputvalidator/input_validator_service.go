@@ -0,0 +1,122 @@
+package inputvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
+)
+
+// Limits for a single validator run; validators are trusted but still sandboxed
+const (
+	ValidatorTimeLimitMs   = 5000
+	ValidatorMemoryLimitMb = 256
+	maxMessageBytes        = 1024
+)
+
+// Exit codes of the validator protocol
+const (
+	exitValid   = 0
+	exitInvalid = 1
+)
+
+// InputValidatorService checks test inputs against a problem's constraints: those
+// declared on the parameters of its schema, then its validator program if it has
+// one. A validator is a plain program in any supported language that reads a test
+// input from stdin, as the JSON value for function and design problems or the text
+// itself for stdio and interactive problems, exits 0 to accept or 1 to reject it and
+// may print a message explaining why.
+type InputValidatorService struct {
+	executor executor.Executor
+	testData *testdata.TestDataService
+}
+
+func NewInputValidatorService(exec executor.Executor, testData *testdata.TestDataService) *InputValidatorService {
+	return &InputValidatorService{executor: exec, testData: testData}
+}
+
+// validate returns what is wrong with the input of a test, nothing when it is valid.
+// Errors wrap domain.ErrValidatorFailed when the validator is at fault; any other
+// error comes from the executor.
+func (s *InputValidatorService) validate(ctx context.Context, problem *domain.Problem, tc *domain.TestCase) ([]string, error) {
+	var violations []string
+	input := tc.Input
+
+	if !problem.IsWholeProgram() {
+		schema, err := problem.Schema()
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(input), &value); err != nil {
+			return []string{fmt.Sprintf("input is not JSON: %v", err)}, nil
+		}
+		violations = schema.CheckConstraints(value)
+	}
+
+	if !problem.HasValidator() {
+		return violations, nil
+	}
+	res, err := s.executor.Execute(ctx, &executor.Request{
+		ProblemID:     problem.ID,
+		Language:      *problem.ValidatorLanguage,
+		Code:          *problem.ValidatorCode,
+		Stdin:         input,
+		StdinKeys:     inputKeys(tc),
+		TimeLimitMs:   ValidatorTimeLimitMs,
+		MemoryLimitMb: ValidatorMemoryLimitMb,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := executor.TruncateOutput(strings.TrimSpace(res.Output), maxMessageBytes)
+	switch {
+	case res.CompileFailed:
+		return nil, fmt.Errorf("%w: compilation error: %s", domain.ErrValidatorFailed, executor.TruncateOutput(res.Error, maxMessageBytes))
+	case res.Termination != executor.TerminationNone:
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidatorFailed, executor.TruncateOutput(res.Error, maxMessageBytes))
+	case res.ExitCode == exitValid:
+		return violations, nil
+	case res.ExitCode == exitInvalid:
+		if message == "" {
+			message = "rejected by the validator"
+		}
+		return append(violations, message), nil
+	default:
+		return nil, fmt.Errorf("%w: exit code %d: %s", domain.ErrValidatorFailed, res.ExitCode, executor.TruncateOutput(res.Error, maxMessageBytes))
+	}
+}
+
+// ValidateTestCases validates every test case's input and reports those that break
+// the problem's constraints
+func (s *InputValidatorService) ValidateTestCases(ctx context.Context, problem *domain.Problem, testCases []domain.TestCase) ([]domain.TestCaseViolation, error) {
+	testCases, err := s.testData.Load(ctx, testCases)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []domain.TestCaseViolation
+	for i := range testCases {
+		errs, err := s.validate(ctx, problem, &testCases[i])
+		if err != nil {
+			return nil, fmt.Errorf("test case %d: %w", i+1, err)
+		}
+		if len(errs) > 0 {
+			violations = append(violations, domain.TestCaseViolation{TestCase: i + 1, TestCaseID: testCases[i].ID, Errors: errs})
+		}
+	}
+	return violations, nil
+}
+
+// inputKeys names the stored input of a test, so that it isn't logged
+func inputKeys(tc *domain.TestCase) []string {
+	if tc.InputKey == nil {
+		return nil
+	}
+	return []string{*tc.InputKey}
+}
@@ -0,0 +1,76 @@
+package inputvalidator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
+	"gorm.io/datatypes"
+)
+
+func TestValidateTestCases(t *testing.T) {
+	functionName, returnType := "sum", "int"
+	params := datatypes.JSON(`[{"name":"nums","type":"list<int>","constraints":{"max_length":3,"unique":true}}]`)
+	validatorLanguage, validatorCode := "python", "validator"
+	problem := &domain.Problem{
+		ID:                3,
+		FunctionName:      &functionName,
+		ReturnType:        &returnType,
+		Parameters:        &params,
+		ValidatorLanguage: &validatorLanguage,
+		ValidatorCode:     &validatorCode,
+	}
+
+	// The validator rejects negative numbers
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		if strings.Contains(req.Stdin, "-") {
+			return &executor.Result{ExitCode: exitInvalid, Output: "nums must not be negative\n"}, nil
+		}
+		return &executor.Result{ExitCode: exitValid}, nil
+	})
+	s := NewInputValidatorService(exec, testdata.NewTestDataService(nil, 0))
+
+	testCases := []domain.TestCase{
+		{ID: 10, Input: `[[1,2,3]]`},
+		{ID: 11, Input: `[[1,1]]`},
+		{ID: 12, Input: `[[-1]]`},
+		{ID: 13, Input: `[1,2,3,4]`},
+	}
+	violations, err := s.ValidateTestCases(context.Background(), problem, testCases)
+	if err != nil {
+		t.Fatalf("ValidateTestCases returned error: %v", err)
+	}
+	want := []domain.TestCaseViolation{
+		{TestCase: 2, TestCaseID: 11, Errors: []string{"nums: element 1 repeats 1"}},
+		{TestCase: 3, TestCaseID: 12, Errors: []string{"nums must not be negative"}},
+		{TestCase: 4, TestCaseID: 13, Errors: []string{"nums: length 4 is greater than 3"}},
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("Expected %d violations, got %+v", len(want), violations)
+	}
+	for i, v := range violations {
+		if v.TestCase != want[i].TestCase || v.TestCaseID != want[i].TestCaseID || strings.Join(v.Errors, "; ") != strings.Join(want[i].Errors, "; ") {
+			t.Errorf("Expected %+v, got %+v", want[i], v)
+		}
+	}
+	if req := exec.Requests()[0]; req.Stdin != `[[1,2,3]]` || req.TimeLimitMs != ValidatorTimeLimitMs {
+		t.Errorf("Unexpected request %+v", req)
+	}
+
+	exec.Handler = func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{ExitCode: 2, Error: "IndexError"}, nil
+	}
+	if _, err := s.ValidateTestCases(context.Background(), problem, testCases); !errors.Is(err, domain.ErrValidatorFailed) {
+		t.Errorf("Expected ErrValidatorFailed, got %v", err)
+	}
+
+	// Whole-program problems have only their validator
+	stdio := &domain.Problem{ID: 4, JudgeMode: domain.JudgeModeStdio}
+	if violations, err := s.ValidateTestCases(context.Background(), stdio, []domain.TestCase{{Input: "not json"}}); err != nil || len(violations) != 0 {
+		t.Errorf("Expected no violations, got %+v, %v", violations, err)
+	}
+}
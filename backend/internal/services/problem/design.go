@@ -0,0 +1,179 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// designTypes is the parsed parameter types of a design's constructor and methods
+// and the return types of its methods, nil for void methods
+type designTypes struct {
+	constructor []*domain.TypeExpr
+	params      map[string][]*domain.TypeExpr
+	returns     map[string]*domain.TypeExpr
+}
+
+// validateDesign validates the class of a design problem and checks its test
+// cases against it
+func (s *ProblemService) validateDesign(req CreateProblemRequest) error {
+	design := req.Design
+	if design == nil {
+		return errors.New("design is required for design problems")
+	}
+	if !isValidIdentifier(design.ClassName) {
+		return errors.New("design class_name must be a valid identifier")
+	}
+	if len(design.Methods) == 0 {
+		return errors.New("design must have at least one method")
+	}
+
+	types := designTypes{
+		params:  make(map[string][]*domain.TypeExpr, len(design.Methods)),
+		returns: make(map[string]*domain.TypeExpr, len(design.Methods)),
+	}
+	var err error
+	if types.constructor, err = s.validateDesignParams(design.Constructor); err != nil {
+		return fmt.Errorf("invalid constructor: %w", err)
+	}
+	for _, method := range design.Methods {
+		if !isValidIdentifier(method.Name) {
+			return fmt.Errorf("method name %q must be a valid identifier", method.Name)
+		}
+		if method.Name == design.ClassName {
+			return fmt.Errorf("method %s cannot be named after the class", method.Name)
+		}
+		if _, ok := types.params[method.Name]; ok {
+			return fmt.Errorf("method %s is defined more than once", method.Name)
+		}
+		if types.params[method.Name], err = s.validateDesignParams(method.Parameters); err != nil {
+			return fmt.Errorf("invalid method %s: %w", method.Name, err)
+		}
+		if method.IsVoid() {
+			types.returns[method.Name] = nil
+			continue
+		}
+		if types.returns[method.Name], err = s.validateDesignType(method.ReturnType); err != nil {
+			return fmt.Errorf("invalid return type of method %s: %w", method.Name, err)
+		}
+	}
+
+	for i, tc := range req.TestCases {
+		if err := checkDesignTestCase(tc, design, types, req.ValidationType); err != nil {
+			return fmt.Errorf("test case %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s *ProblemService) validateDesignParams(params []domain.SchemaParameter) ([]*domain.TypeExpr, error) {
+	types := make([]*domain.TypeExpr, len(params))
+	for i, param := range params {
+		if !isValidIdentifier(param.Name) {
+			return nil, fmt.Errorf("parameter name %q must be a valid identifier", param.Name)
+		}
+		paramType, err := s.validateDesignType(param.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type for parameter %s: %w", param.Name, err)
+		}
+		if param.Constraints != nil {
+			if err := param.Constraints.Validate(paramType); err != nil {
+				return nil, fmt.Errorf("invalid constraints for parameter %s: %w", param.Name, err)
+			}
+		}
+		types[i] = paramType
+	}
+	return types, nil
+}
+
+// validateDesignType parses a type of a design; the harnesses build no custom
+// types for the class's methods
+func (s *ProblemService) validateDesignType(typ domain.GenericType) (*domain.TypeExpr, error) {
+	if typ == "" {
+		return nil, errors.New("type cannot be empty")
+	}
+	parsed, err := s.validateType(typ, false)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Contains(domain.KindCustom) {
+		return nil, errors.New("custom types are not supported in design problems")
+	}
+	return parsed, nil
+}
+
+// checkDesignTestCase checks a test is its operations and their arguments, the
+// first operation constructing the class, and that it expects one output for each
+// operation, null for the constructor and void methods
+func checkDesignTestCase(tc TestCaseInput, design *domain.DesignSchema, types designTypes, validationType string) error {
+	input, ok := tc.Input.([]interface{})
+	if !ok || len(input) != 2 {
+		return errors.New("input must be an array of the operations and their arguments")
+	}
+	operations, ok := input[0].([]interface{})
+	if !ok || len(operations) == 0 {
+		return errors.New("operations must be a non-empty array")
+	}
+	arguments, ok := input[1].([]interface{})
+	if !ok || len(arguments) != len(operations) {
+		return fmt.Errorf("arguments must be an array of %d argument lists", len(operations))
+	}
+
+	for k, op := range operations {
+		name, ok := op.(string)
+		if !ok {
+			return fmt.Errorf("operation %d must be a string", k+1)
+		}
+		var params []domain.SchemaParameter
+		var paramTypes []*domain.TypeExpr
+		switch {
+		case k == 0:
+			if name != design.ClassName {
+				return fmt.Errorf("the first operation must be %s", design.ClassName)
+			}
+			params, paramTypes = design.Constructor, types.constructor
+		default:
+			method, ok := design.Method(name)
+			if !ok {
+				return fmt.Errorf("operation %d: unknown method %s", k+1, name)
+			}
+			params, paramTypes = method.Parameters, types.params[name]
+		}
+
+		args, ok := arguments[k].([]interface{})
+		if !ok || len(args) != len(params) {
+			return fmt.Errorf("operation %d: %s takes %d arguments", k+1, name, len(params))
+		}
+		for j, arg := range args {
+			if err := paramTypes[j].Check(arg); err != nil {
+				return fmt.Errorf("operation %d: invalid value for %s: %w", k+1, params[j].Name, err)
+			}
+		}
+	}
+
+	// A custom checker may accept outputs of any shape
+	if validationType == domain.ValidationTypeCustom {
+		return nil
+	}
+	expected, ok := tc.ExpectedOutput.([]interface{})
+	if !ok || len(expected) != len(operations) {
+		return fmt.Errorf("expected_output must be an array of %d outputs", len(operations))
+	}
+	for k, value := range expected {
+		var returnType *domain.TypeExpr
+		if k > 0 {
+			returnType = types.returns[operations[k].(string)]
+		}
+		if returnType == nil {
+			if value != nil {
+				return fmt.Errorf("expected output %d must be null", k+1)
+			}
+			continue
+		}
+		if err := returnType.Check(value); err != nil {
+			return fmt.Errorf("invalid expected output %d: %w", k+1, err)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package problem
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/prabalesh/loco/backend/internal/domain"
 	"github.com/prabalesh/loco/backend/internal/services/codegen"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
 	"gorm.io/datatypes"
 )
 
@@ -20,6 +22,8 @@ type ProblemService struct {
 	customTypeRepo     domain.CustomTypeRepository
 	referenceRepo      domain.ReferenceSolutionRepository
 	boilerplateService *codegen.BoilerplateService
+	inputValidator     domain.InputValidator
+	testData           *testdata.TestDataService
 }
 
 func NewProblemService(
@@ -30,6 +34,8 @@ func NewProblemService(
 	customTypeRepo domain.CustomTypeRepository,
 	referenceRepo domain.ReferenceSolutionRepository,
 	boilerplateService *codegen.BoilerplateService,
+	inputValidator domain.InputValidator,
+	testData *testdata.TestDataService,
 ) *ProblemService {
 	return &ProblemService{
 		problemRepo:        problemRepo,
@@ -39,6 +45,8 @@ func NewProblemService(
 		customTypeRepo:     customTypeRepo,
 		referenceRepo:      referenceRepo,
 		boilerplateService: boilerplateService,
+		inputValidator:     inputValidator,
+		testData:           testData,
 	}
 }
 
@@ -48,26 +56,53 @@ type CreateProblemRequest struct {
 	Difficulty              string                   `json:"difficulty"`
 	CategoryIDs             []int                    `json:"category_ids"`
 	TagIDs                  []int                    `json:"tag_ids"`
+	JudgeMode               string                   `json:"judge_mode"` // function (default), design, stdio or interactive
+	InputFormat             string                   `json:"input_format"`
+	OutputFormat            string                   `json:"output_format"`
 	FunctionName            string                   `json:"function_name"`
 	ReturnType              domain.GenericType       `json:"return_type"`
 	Parameters              []domain.SchemaParameter `json:"parameters"`
+	Design                  *domain.DesignSchema     `json:"design"` // In place of the function, for design problems
 	ValidationType          string                   `json:"validation_type"`
 	ExpectedTimeComplexity  string                   `json:"expected_time_complexity"`
 	ExpectedSpaceComplexity string                   `json:"expected_space_complexity"`
+	CheckerLanguage         string                   `json:"checker_language"`
+	CheckerCode             string                   `json:"checker_code"`
+	InteractorLanguage      string                   `json:"interactor_language"`
+	InteractorCode          string                   `json:"interactor_code"`
+	QueryLimit              int                      `json:"query_limit"`
+	GeneratorLanguage       string                   `json:"generator_language"`
+	GeneratorCode           string                   `json:"generator_code"`
+	ValidatorLanguage       string                   `json:"validator_language"`
+	ValidatorCode           string                   `json:"validator_code"`
+	Subtasks                domain.Subtasks          `json:"subtasks"`
 	TestCases               []TestCaseInput          `json:"test_cases"`
 }
 
 type TestCaseInput struct {
-	Input          interface{} `json:"input"` // Array of parameter values or single value if 1 param
+	Input          interface{} `json:"input"` // Array of parameter values or single value if 1 param; text for stdio and interactive problems; operations and their arguments for design problems
 	ExpectedOutput interface{} `json:"expected_output"`
 	IsSample       bool        `json:"is_sample"`
 	InputSize      *int        `json:"input_size"`
 	TimeLimitMs    *int        `json:"time_limit_ms"`
 	MemoryLimitMb  *int        `json:"memory_limit_mb"`
+	Subtask        string      `json:"subtask"` // name of one of the problem's subtasks
+
+	ValidationConfig domain.ValidationConfig `json:"validation_config"` // Comparison options, see domain.CompareOptions
 }
 
 // CreateProblem creates a new problem with auto-generation
 func (s *ProblemService) CreateProblem(req CreateProblemRequest, createdBy int) (*domain.Problem, error) {
+	if req.JudgeMode == "" {
+		req.JudgeMode = domain.JudgeModeFunction
+	}
+	if req.ValidationType == "" {
+		req.ValidationType = "EXACT"
+		if req.JudgeMode == domain.JudgeModeStdio {
+			req.ValidationType = domain.ValidationTypeTokens
+		}
+	}
+
 	// Validate request
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
@@ -82,22 +117,15 @@ func (s *ProblemService) CreateProblem(req CreateProblemRequest, createdBy int)
 		return nil, err
 	}
 
-	// Convert parameters to JSON
-	paramsJSON, err := json.Marshal(req.Parameters)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal parameters: %w", err)
-	}
-	paramsData := datatypes.JSON(paramsJSON)
-
 	// Create problem model
 	problem := &domain.Problem{
 		Title:                   req.Title,
 		Slug:                    slug,
 		Description:             req.Description,
 		Difficulty:              req.Difficulty,
-		FunctionName:            &req.FunctionName,
-		ReturnType:              (*string)(&req.ReturnType),
-		Parameters:              &paramsData,
+		JudgeMode:               req.JudgeMode,
+		InputFormat:             req.InputFormat,
+		OutputFormat:            req.OutputFormat,
 		ValidationType:          req.ValidationType,
 		ValidationStatus:        "draft",
 		ExpectedTimeComplexity:  &req.ExpectedTimeComplexity,
@@ -106,6 +134,58 @@ func (s *ProblemService) CreateProblem(req CreateProblemRequest, createdBy int)
 		Visibility:              "private",
 		CreatedBy:               &createdBy,
 	}
+	if req.ValidationType == domain.ValidationTypeCustom {
+		problem.CheckerLanguage = &req.CheckerLanguage
+		problem.CheckerCode = &req.CheckerCode
+	}
+	if problem.IsInteractive() {
+		problem.InteractorLanguage = &req.InteractorLanguage
+		problem.InteractorCode = &req.InteractorCode
+		problem.QueryLimit = req.QueryLimit
+	}
+	if req.GeneratorLanguage != "" {
+		problem.GeneratorLanguage = &req.GeneratorLanguage
+		problem.GeneratorCode = &req.GeneratorCode
+	}
+	if req.ValidatorLanguage != "" {
+		problem.ValidatorLanguage = &req.ValidatorLanguage
+		problem.ValidatorCode = &req.ValidatorCode
+	}
+	problem.Subtasks = req.Subtasks
+
+	// Whole-program problems have no signature, design problems have their design
+	// instead
+	if problem.IsDesign() {
+		designJSON, err := json.Marshal(req.Design)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal design: %w", err)
+		}
+		designData := datatypes.JSON(designJSON)
+		problem.Design = &designData
+	} else if !problem.IsWholeProgram() {
+		paramsJSON, err := json.Marshal(req.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal parameters: %w", err)
+		}
+		paramsData := datatypes.JSON(paramsJSON)
+		problem.FunctionName = &req.FunctionName
+		problem.ReturnType = (*string)(&req.ReturnType)
+		problem.Parameters = &paramsData
+	}
+
+	// Test inputs must respect the problem's constraints
+	testCases, err := s.buildTestCases(problem, req.TestCases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test cases: %w", err)
+	}
+	violations, err := s.inputValidator.ValidateTestCases(context.Background(), problem, testCases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate test cases: %w", err)
+	}
+	if len(violations) > 0 {
+		v := violations[0]
+		return nil, fmt.Errorf("test case %d: %s", v.TestCase, strings.Join(v.Errors, "; "))
+	}
 
 	// Save problem
 	if err := s.problemRepo.Create(problem); err != nil {
@@ -144,14 +224,23 @@ func (s *ProblemService) CreateProblem(req CreateProblemRequest, createdBy int)
 	}
 
 	// Create test cases
-	if err := s.createTestCases(problem.ID, req.TestCases); err != nil {
+	for i := range testCases {
+		testCases[i].ProblemID = problem.ID
+		if err := s.testData.Offload(context.Background(), &testCases[i]); err != nil {
+			return nil, fmt.Errorf("failed to create test cases: %w", err)
+		}
+	}
+	if err := s.testCaseRepo.CreateMany(testCases); err != nil {
 		return nil, fmt.Errorf("failed to create test cases: %w", err)
 	}
 
-	// Auto-generate boilerplates (async recommended but sync for simplicity)
-	if err := s.boilerplateService.GenerateAllBoilerplatesForProblem(problem); err != nil {
-		// Log error but don't fail the request
-		fmt.Printf("Warning: Failed to generate boilerplates: %v\n", err)
+	// Auto-generate boilerplates (async recommended but sync for simplicity);
+	// whole-program problems are judged without them
+	if !problem.IsWholeProgram() {
+		if err := s.boilerplateService.GenerateAllBoilerplatesForProblem(problem); err != nil {
+			// Log error but don't fail the request
+			fmt.Printf("Warning: Failed to generate boilerplates: %v\n", err)
+		}
 	}
 
 	return problem, nil
@@ -175,6 +264,105 @@ func (s *ProblemService) validateCreateRequest(req CreateProblemRequest) error {
 		return errors.New("difficulty must be easy, medium, or hard")
 	}
 
+	// Judge mode
+	if !domain.IsValidJudgeMode(req.JudgeMode) {
+		return errors.New("judge_mode must be function, design, stdio or interactive")
+	}
+	stdio := req.JudgeMode == domain.JudgeModeStdio
+	interactive := req.JudgeMode == domain.JudgeModeInteractive
+
+	// Validation type
+	validTypes := map[string]bool{
+		"EXACT": true, "UNORDERED": true, "SUBSET": true, "ANY_MATCH": true, domain.ValidationTypeCustom: true,
+	}
+	if stdio {
+		validTypes = map[string]bool{
+			"EXACT": true, domain.ValidationTypeLines: true, domain.ValidationTypeTokens: true, domain.ValidationTypeCustom: true,
+		}
+	}
+	if req.JudgeMode == domain.JudgeModeDesign {
+		// The outputs of a design test are in the order of its operations
+		validTypes = map[string]bool{"EXACT": true, domain.ValidationTypeCustom: true}
+	}
+	if !validTypes[req.ValidationType] {
+		return errors.New("invalid validation_type")
+	}
+	if req.ValidationType == domain.ValidationTypeCustom && (req.CheckerLanguage == "" || req.CheckerCode == "") {
+		return errors.New("checker_language and checker_code are required for CUSTOM validation")
+	}
+
+	// Interactor
+	if interactive {
+		if req.ValidationType == domain.ValidationTypeCustom {
+			return errors.New("interactive problems are judged by their interactor, not a checker")
+		}
+		if req.InteractorLanguage == "" || req.InteractorCode == "" {
+			return errors.New("interactor_language and interactor_code are required for interactive problems")
+		}
+		if req.QueryLimit < 0 {
+			return errors.New("query_limit cannot be negative")
+		}
+	}
+
+	// Generator
+	if (req.GeneratorLanguage == "") != (req.GeneratorCode == "") {
+		return errors.New("generator_language and generator_code must be set together")
+	}
+
+	// Validator
+	if (req.ValidatorLanguage == "") != (req.ValidatorCode == "") {
+		return errors.New("validator_language and validator_code must be set together")
+	}
+
+	// Subtasks
+	if err := req.Subtasks.Validate(); err != nil {
+		return err
+	}
+	for i, tc := range req.TestCases {
+		if tc.Subtask != "" && !req.Subtasks.Has(tc.Subtask) {
+			return fmt.Errorf("test case %d: subtask %q is not defined", i+1, tc.Subtask)
+		}
+	}
+
+	// Test cases
+	if len(req.TestCases) == 0 {
+		return errors.New("at least one test case is required")
+	}
+
+	// Check at least one public test case
+	hasPublic := false
+	for _, tc := range req.TestCases {
+		if tc.IsSample {
+			hasPublic = true
+			break
+		}
+	}
+	if !hasPublic {
+		return errors.New("at least one public test case (is_sample=true) is required")
+	}
+
+	if stdio || interactive {
+		// Test cases are the program's input and output as text; the expected output
+		// of an interactive test is only a hint for the interactor and may be left out
+		for i, tc := range req.TestCases {
+			if _, ok := tc.Input.(string); !ok {
+				return fmt.Errorf("test case %d: input must be text", i+1)
+			}
+			if _, ok := tc.ExpectedOutput.(string); !ok && !(interactive && tc.ExpectedOutput == nil) {
+				return fmt.Errorf("test case %d: expected_output must be text", i+1)
+			}
+		}
+		return nil
+	}
+	if req.JudgeMode == domain.JudgeModeDesign {
+		return s.validateDesign(req)
+	}
+	return s.validateSignature(req)
+}
+
+// validateSignature validates the signature of a function problem and checks its
+// test cases against it
+func (s *ProblemService) validateSignature(req CreateProblemRequest) error {
 	// Function name
 	if req.FunctionName == "" {
 		return errors.New("function_name is required")
@@ -187,54 +375,90 @@ func (s *ProblemService) validateCreateRequest(req CreateProblemRequest) error {
 	if req.ReturnType == "" {
 		return errors.New("return_type is required")
 	}
+	returnType, err := s.validateType(req.ReturnType, false)
+	if err != nil {
+		return fmt.Errorf("invalid return_type: %w", err)
+	}
 
 	// Parameters
 	if len(req.Parameters) == 0 {
 		return errors.New("at least one parameter is required")
 	}
-	for _, param := range req.Parameters {
+	paramTypes := make([]*domain.TypeExpr, len(req.Parameters))
+	for i, param := range req.Parameters {
 		if param.Name == "" {
 			return errors.New("parameter name cannot be empty")
 		}
 		if param.Type == "" {
 			return errors.New("parameter type cannot be empty")
 		}
-		if param.IsCustom {
-			// Validate custom type exists
-			if _, err := s.customTypeRepo.GetByName(string(param.Type)); err != nil {
-				return fmt.Errorf("invalid custom type: %s", param.Type)
+		paramType, err := s.validateType(param.Type, param.IsCustom)
+		if err != nil {
+			return fmt.Errorf("invalid type for parameter %s: %w", param.Name, err)
+		}
+		if param.Constraints != nil {
+			if err := param.Constraints.Validate(paramType); err != nil {
+				return fmt.Errorf("invalid constraints for parameter %s: %w", param.Name, err)
 			}
 		}
+		paramTypes[i] = paramType
 	}
 
-	// Validation type
-	validTypes := map[string]bool{
-		"EXACT": true, "UNORDERED": true, "SUBSET": true, "ANY_MATCH": true,
+	// Test case values must match the signature
+	for i, tc := range req.TestCases {
+		if err := checkTestCaseInput(tc.Input, req.Parameters, paramTypes); err != nil {
+			return fmt.Errorf("test case %d: %w", i+1, err)
+		}
+		// A custom checker may accept outputs of any shape
+		if req.ValidationType != domain.ValidationTypeCustom {
+			if err := returnType.Check(tc.ExpectedOutput); err != nil {
+				return fmt.Errorf("test case %d: invalid expected_output: %w", i+1, err)
+			}
+		}
 	}
-	if req.ValidationType == "" {
-		req.ValidationType = "EXACT"
+
+	return nil
+}
+
+// validateType parses a schema type and makes sure a custom type is registered
+func (s *ProblemService) validateType(typ domain.GenericType, isCustom bool) (*domain.TypeExpr, error) {
+	parsed, err := typ.Parse()
+	if err != nil {
+		return nil, err
 	}
-	if !validTypes[req.ValidationType] {
-		return errors.New("invalid validation_type")
+	if parsed.Kind == domain.KindCustom {
+		if _, err := s.customTypeRepo.GetByName(parsed.Name); err != nil {
+			return nil, fmt.Errorf("invalid custom type: %s", parsed.Name)
+		}
+	} else if isCustom {
+		return nil, fmt.Errorf("%s is not a custom type", typ)
 	}
+	return parsed, nil
+}
 
-	// Test cases
-	if len(req.TestCases) == 0 {
-		return errors.New("at least one test case is required")
+// checkTestCaseInput checks each input value against its parameter's type. A
+// problem with one parameter may give its value without the surrounding array.
+func checkTestCaseInput(input interface{}, params []domain.SchemaParameter, types []*domain.TypeExpr) error {
+	values, ok := input.([]interface{})
+	if !ok || len(values) != len(params) {
+		if len(params) == 1 {
+			if err := types[0].Check(input); err != nil {
+				return fmt.Errorf("invalid value for %s: %w", params[0].Name, err)
+			}
+			return nil
+		}
+		return fmt.Errorf("input must be an array of %d values", len(params))
 	}
 
-	// Check at least one public test case
-	hasPublic := false
-	for _, tc := range req.TestCases {
-		if tc.IsSample {
-			hasPublic = true
-			break
+	for i, value := range values {
+		if err := types[i].Check(value); err != nil {
+			// A lone list parameter of length one is indistinguishable from its wrapper
+			if len(params) == 1 && types[0].Check(input) == nil {
+				return nil
+			}
+			return fmt.Errorf("invalid value for %s: %w", params[i].Name, err)
 		}
 	}
-	if !hasPublic {
-		return errors.New("at least one public test case (is_sample=true) is required")
-	}
-
 	return nil
 }
 
@@ -277,38 +501,38 @@ func (s *ProblemService) ensureUniqueSlug(slug string, excludeID int) (string, e
 	}
 }
 
-// createTestCases creates test cases for a problem
-func (s *ProblemService) createTestCases(problemID int, testCaseInputs []TestCaseInput) error {
+// buildTestCases encodes the test cases of a problem, to be saved along with it
+func (s *ProblemService) buildTestCases(problem *domain.Problem, testCaseInputs []TestCaseInput) ([]domain.TestCase, error) {
 	testCases := []domain.TestCase{}
 
 	for i, tcInput := range testCaseInputs {
-		// Convert input to JSON
-		inputJSON, err := json.Marshal(tcInput.Input)
+		input, err := problem.EncodeTestValue(tcInput.Input)
 		if err != nil {
-			return fmt.Errorf("invalid test case input at index %d: %w", i, err)
+			return nil, fmt.Errorf("invalid test case input at index %d: %w", i, err)
 		}
 
-		// Convert expected output to JSON
-		outputJSON, err := json.Marshal(tcInput.ExpectedOutput)
+		output, err := problem.EncodeTestValue(tcInput.ExpectedOutput)
 		if err != nil {
-			return fmt.Errorf("invalid test case output at index %d: %w", i, err)
+			return nil, fmt.Errorf("invalid test case output at index %d: %w", i, err)
 		}
 
 		testCase := domain.TestCase{
-			ProblemID:      problemID,
-			Input:          string(inputJSON),
-			ExpectedOutput: string(outputJSON),
+			Input:          input,
+			ExpectedOutput: output,
 			IsSample:       tcInput.IsSample,
 			InputSize:      tcInput.InputSize,
 			TimeLimitMs:    tcInput.TimeLimitMs,
 			MemoryLimitMb:  tcInput.MemoryLimitMb,
 			OrderIndex:     i,
+			Subtask:        tcInput.Subtask,
+
+			ValidationConfig: tcInput.ValidationConfig,
 		}
 
 		testCases = append(testCases, testCase)
 	}
 
-	return s.testCaseRepo.CreateMany(testCases)
+	return testCases, nil
 }
 
 // isValidIdentifier checks if string is valid identifier (alphanumeric + underscore)
@@ -342,9 +566,14 @@ func (s *ProblemService) GetProblemDetail(slug string, isAdmin bool) (map[string
 		"slug":                      problem.Slug,
 		"description":               problem.Description,
 		"difficulty":                problem.Difficulty,
+		"judge_mode":                problem.JudgeMode,
+		"input_format":              problem.InputFormat,
+		"output_format":             problem.OutputFormat,
+		"query_limit":               problem.QueryLimit,
 		"function_name":             problem.FunctionName,
 		"return_type":               problem.ReturnType,
 		"parameters":                problem.Parameters,
+		"design":                    problem.Design,
 		"validation_type":           problem.ValidationType,
 		"expected_time_complexity":  problem.ExpectedTimeComplexity,
 		"expected_space_complexity": problem.ExpectedSpaceComplexity,
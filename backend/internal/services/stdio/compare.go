@@ -0,0 +1,79 @@
+package stdio
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+// CompareOutput reports whether a program's output matches the expected output.
+// LINES and EXACT compare line by line, ignoring trailing whitespace on each line and
+// blank lines at the end; TOKENS, or any comparison that ignores whitespace, compares
+// the whitespace-separated tokens. Tokens that are both numbers match within the
+// options' tolerances.
+func CompareOutput(expected, actual, validationType string, opts domain.CompareOptions) bool {
+	if validationType == domain.ValidationTypeTokens || opts.IgnoreWhitespace {
+		return compareTokens(strings.Fields(expected), strings.Fields(actual), opts)
+	}
+
+	want, got := outputLines(expected), outputLines(actual)
+	if len(want) != len(got) {
+		return false
+	}
+	tolerant := opts.AbsEpsilon > 0 || opts.RelEpsilon > 0
+	for i := range want {
+		if want[i] == got[i] || (opts.CaseInsensitive && strings.EqualFold(want[i], got[i])) {
+			continue
+		}
+		// Numbers within the tolerance may be printed differently
+		if tolerant && compareTokens(strings.Fields(want[i]), strings.Fields(got[i]), opts) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// outputLines splits output into lines without trailing whitespace, dropping the
+// blank lines at the end
+func outputLines(s string) []string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func compareTokens(want, got []string, opts domain.CompareOptions) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if !tokenEqual(want[i], got[i], opts) {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenEqual(want, got string, opts domain.CompareOptions) bool {
+	if want == got || (opts.CaseInsensitive && strings.EqualFold(want, got)) {
+		return true
+	}
+	if opts.AbsEpsilon == 0 && opts.RelEpsilon == 0 {
+		return false
+	}
+
+	a, errA := strconv.ParseFloat(want, 64)
+	b, errB := strconv.ParseFloat(got, 64)
+	if errA != nil || errB != nil || math.IsNaN(a) || math.IsNaN(b) {
+		return false
+	}
+	tol := math.Max(opts.AbsEpsilon, opts.RelEpsilon*math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) <= tol
+}
@@ -0,0 +1,39 @@
+package stdio
+
+import (
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+)
+
+func TestCompareOutput(t *testing.T) {
+	tests := []struct {
+		name           string
+		expected       string
+		actual         string
+		validationType string
+		opts           domain.CompareOptions
+		want           bool
+	}{
+		{"identical", "1 2\n3\n", "1 2\n3\n", "EXACT", domain.CompareOptions{}, true},
+		{"trailing whitespace and blank lines", "1 2\n3", "1 2  \r\n3\n\n", domain.ValidationTypeLines, domain.CompareOptions{}, true},
+		{"line break differs", "1 2\n3", "1\n2 3", domain.ValidationTypeLines, domain.CompareOptions{}, false},
+		{"tokens ignore layout", "1 2\n3", "1\n2    3\n", domain.ValidationTypeTokens, domain.CompareOptions{}, true},
+		{"missing token", "1 2 3", "1 2", domain.ValidationTypeTokens, domain.CompareOptions{}, false},
+		{"ignore whitespace flag", "a b", "a\nb", "EXACT", domain.CompareOptions{IgnoreWhitespace: true}, true},
+		{"case insensitive", "YES", "yes", "EXACT", domain.CompareOptions{CaseInsensitive: true}, true},
+		{"case sensitive", "YES", "yes", "EXACT", domain.CompareOptions{}, false},
+		{"within tolerance", "0.3333 1", "0.33331 1.0", domain.ValidationTypeLines, domain.CompareOptions{AbsEpsilon: 1e-4}, true},
+		{"outside tolerance", "0.3333", "0.34", domain.ValidationTypeTokens, domain.CompareOptions{AbsEpsilon: 1e-4}, false},
+		{"relative tolerance", "1000000", "1000000.5", domain.ValidationTypeTokens, domain.CompareOptions{RelEpsilon: 1e-6}, true},
+		{"no tolerance for words", "abc", "abd", domain.ValidationTypeTokens, domain.CompareOptions{AbsEpsilon: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareOutput(tt.expected, tt.actual, tt.validationType, tt.opts); got != tt.want {
+				t.Errorf("CompareOutput(%q, %q) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
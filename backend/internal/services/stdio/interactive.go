@@ -0,0 +1,90 @@
+package stdio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/checker"
+)
+
+// Exit codes of the interactor protocol, the same as a checker's
+const (
+	interactorAccepted = 0
+	interactorRejected = 1
+)
+
+// interact runs one test of an interactive problem. The interactor reads the test's
+// input and expected output from executor.InteractorInputFile and
+// executor.InteractorAnswerFile, talks to the program over stdin and stdout, then
+// exits 0 to accept or 1 to reject, with the reason on stderr. It may run as long
+// as the program plus a checker's time limit, since it spends most of its time
+// waiting.
+func (r *Runner) interact(ctx context.Context, problem *domain.Problem, prog Program, tc *domain.TestCase) (*domain.TestCaseResult, *executor.Result, error) {
+	if !problem.HasInteractor() {
+		return nil, nil, fmt.Errorf("%w: problem %d has no interactor", checker.ErrCheckerFailed, problem.ID)
+	}
+	interactive, ok := r.executor.(executor.InteractiveExecutor)
+	if !ok {
+		return nil, nil, executor.ErrInteractiveUnsupported
+	}
+
+	limits := prog.Language.ScaleLimits(tc.Limits(problem))
+	res, err := interactive.ExecuteInteractive(ctx, &executor.InteractiveRequest{
+		Solution: executor.Request{
+			ProblemID:     problem.ID,
+			SubmissionID:  prog.SubmissionID,
+			Language:      prog.LanguageSlug,
+			Version:       prog.Version,
+			Code:          prog.Code,
+			TimeLimitMs:   limits.TimeMs,
+			MemoryLimitMb: limits.MemoryMb,
+		},
+		Interactor: executor.Request{
+			ProblemID:     problem.ID,
+			Language:      *problem.InteractorLanguage,
+			Code:          *problem.InteractorCode,
+			TimeLimitMs:   limits.TimeMs + checker.CheckerTimeLimitMs,
+			MemoryLimitMb: checker.CheckerMemoryLimitMb,
+		},
+		Input:      tc.Input,
+		Answer:     tc.ExpectedOutput,
+		QueryLimit: problem.QueryLimit,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Solution.CompileFailed {
+		return nil, res.Solution, nil
+	}
+
+	inter := res.Interactor
+	if inter.CompileFailed {
+		return nil, nil, fmt.Errorf("%w: interactor did not compile: %s", checker.ErrCheckerFailed,
+			executor.TruncateOutput(inter.Error, executor.MaxStoredTestOutputBytes))
+	}
+
+	row := newRow(tc, res.Solution)
+	switch {
+	case res.QueryLimitExceeded:
+		row.Status = domain.TestStatusFailed
+		row.Error = fmt.Sprintf("Query limit exceeded (%d allowed)", problem.QueryLimit)
+	case res.Solution.Termination != executor.TerminationNone:
+		programFailed(row, res.Solution)
+	case inter.Termination != executor.TerminationNone:
+		return nil, nil, fmt.Errorf("%w: interactor was stopped: %s", checker.ErrCheckerFailed, inter.Error)
+	case inter.ExitCode == interactorRejected:
+		row.Status = domain.TestStatusFailed
+		row.Error = executor.TruncateOutput(strings.TrimSpace(inter.Error), executor.MaxStoredTestOutputBytes)
+	case inter.ExitCode != interactorAccepted:
+		return nil, nil, fmt.Errorf("%w: interactor exited with code %d: %s", checker.ErrCheckerFailed, inter.ExitCode, inter.Error)
+	case res.Solution.ExitCode != 0:
+		// Accepted by the interactor but crashed on the way out
+		programFailed(row, res.Solution)
+	default:
+		row.Status = domain.TestStatusPassed
+	}
+	return row, nil, nil
+}
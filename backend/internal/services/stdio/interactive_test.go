@@ -0,0 +1,164 @@
+package stdio
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/checker"
+)
+
+func interactiveProblem() *domain.Problem {
+	language, code := "python", "interactor"
+	return &domain.Problem{
+		ID: 5, JudgeMode: domain.JudgeModeInteractive, TimeLimit: 1000, MemoryLimit: 128,
+		InteractorLanguage: &language, InteractorCode: &code, QueryLimit: 10,
+	}
+}
+
+func interactiveTestCases() []domain.TestCase {
+	return []domain.TestCase{
+		{Input: "42\n", IsSample: true},
+		{Input: "7\n"},
+	}
+}
+
+// interaction answers every run with the given solution and interactor results
+func interaction(solution, interactor executor.Result) func(req *executor.InteractiveRequest) (*executor.InteractiveResult, error) {
+	return func(req *executor.InteractiveRequest) (*executor.InteractiveResult, error) {
+		sol, inter := solution, interactor
+		return &executor.InteractiveResult{Solution: &sol, Interactor: &inter, Queries: 3}, nil
+	}
+}
+
+func TestInteractAccepted(t *testing.T) {
+	exec := executor.NewFakeExecutor(nil)
+	exec.InteractiveHandler = interaction(executor.Result{Output: "50\n25\n42\n", Runtime: 12, Memory: 800}, executor.Result{})
+	problem := interactiveProblem()
+
+	res, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "c++", Code: "guess"}, interactiveTestCases())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if status, _, passed := res.Verdict(problem, 2); status != domain.SubmissionStatusAccepted || passed != 2 {
+		t.Errorf("Unexpected verdict %s with %d passed", status, passed)
+	}
+	if res.Runtime != 12 || res.TestResults[0].ActualOutput != "50\n25\n42\n" {
+		t.Errorf("Unexpected result %+v", res)
+	}
+
+	reqs := exec.InteractiveRequests()
+	if len(reqs) != 2 || len(exec.Requests()) != 0 {
+		t.Fatalf("Expected 2 interactive runs and no plain ones, got %d and %d", len(reqs), len(exec.Requests()))
+	}
+	req := reqs[1]
+	if req.Input != "7\n" || req.QueryLimit != 10 || req.Solution.Code != "guess" || req.Interactor.Code != "interactor" {
+		t.Errorf("Unexpected request %+v", req)
+	}
+	if req.Solution.TimeLimitMs != 1000 || req.Interactor.TimeLimitMs != 1000+checker.CheckerTimeLimitMs {
+		t.Errorf("Unexpected time limits %d and %d", req.Solution.TimeLimitMs, req.Interactor.TimeLimitMs)
+	}
+}
+
+func TestInteractVerdicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(req *executor.InteractiveRequest) (*executor.InteractiveResult, error)
+		status     domain.SubmissionStatus
+		message    string
+		compileErr bool
+	}{
+		{
+			name:    "interactor rejects",
+			handler: interaction(executor.Result{}, executor.Result{ExitCode: 1, Error: "guessed 43, wanted 42\n"}),
+			status:  domain.SubmissionStatusWrongAnswer,
+			message: "Failed on test 1: guessed 43, wanted 42",
+		},
+		{
+			name: "query limit",
+			handler: func(req *executor.InteractiveRequest) (*executor.InteractiveResult, error) {
+				return &executor.InteractiveResult{
+					Solution: &executor.Result{ExitCode: -1, Signal: "SIGKILL"}, Interactor: &executor.Result{ExitCode: 1},
+					Queries: 11, QueryLimitExceeded: true,
+				}, nil
+			},
+			status:  domain.SubmissionStatusWrongAnswer,
+			message: "Failed on test 1: Query limit exceeded (10 allowed)",
+		},
+		{
+			name:    "solution times out",
+			handler: interaction(executor.Result{ExitCode: -1, Termination: executor.TerminationTimeLimit}, executor.Result{ExitCode: 1}),
+			status:  domain.SubmissionStatusTimeLimitExceeded,
+		},
+		{
+			name:    "solution crashes after being accepted",
+			handler: interaction(executor.Result{ExitCode: 2, Error: "segfault"}, executor.Result{}),
+			status:  domain.SubmissionStatusRuntimeError,
+			message: "segfault",
+		},
+		{
+			name: "solution does not compile",
+			handler: func(req *executor.InteractiveRequest) (*executor.InteractiveResult, error) {
+				return &executor.InteractiveResult{Solution: &executor.Result{CompileFailed: true, Error: "error: expected ';'"}}, nil
+			},
+			compileErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := executor.NewFakeExecutor(nil)
+			exec.InteractiveHandler = tt.handler
+			problem := interactiveProblem()
+
+			res, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "python"}, interactiveTestCases())
+			if err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+			if tt.compileErr {
+				if !res.CompileFailed || res.CompileError != "error: expected ';'" {
+					t.Errorf("Expected a compile failure, got %+v", res)
+				}
+				return
+			}
+			status, message, _ := res.Verdict(problem, 2)
+			if status != tt.status || message != tt.message {
+				t.Errorf("Expected %s %q, got %s %q", tt.status, tt.message, status, message)
+			}
+			if len(exec.InteractiveRequests()) != 1 {
+				t.Errorf("Expected the run to stop after test 1")
+			}
+		})
+	}
+}
+
+func TestInteractBrokenInteractor(t *testing.T) {
+	tests := []struct {
+		name       string
+		interactor executor.Result
+	}{
+		{"compile error", executor.Result{CompileFailed: true, Error: "syntax error"}},
+		{"unknown exit code", executor.Result{ExitCode: 3}},
+		{"timeout", executor.Result{ExitCode: -1, Termination: executor.TerminationTimeLimit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := executor.NewFakeExecutor(nil)
+			exec.InteractiveHandler = interaction(executor.Result{}, tt.interactor)
+			_, err := NewRunner(exec).Run(context.Background(), interactiveProblem(), Program{LanguageSlug: "python"}, interactiveTestCases())
+			if !errors.Is(err, checker.ErrCheckerFailed) {
+				t.Errorf("Expected ErrCheckerFailed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestInteractUnsupportedExecutor(t *testing.T) {
+	_, err := NewRunner(executor.NewFakeExecutor(nil)).Run(context.Background(), interactiveProblem(), Program{LanguageSlug: "python"}, interactiveTestCases())
+	if !errors.Is(err, executor.ErrInteractiveUnsupported) {
+		t.Errorf("Expected ErrInteractiveUnsupported, got %v", err)
+	}
+}
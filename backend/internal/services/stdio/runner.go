@@ -0,0 +1,248 @@
+package stdio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/checker"
+)
+
+// Runner judges whole-program problems: the submitted program runs once per test
+// case, either with the test's input on stdin and what it prints compared with the
+// expected output or handed to the problem's checker, or, for interactive problems,
+// talking to the problem's interactor, which decides. There is no harness, so
+// nothing of the program's output is reserved for the judge.
+type Runner struct {
+	executor executor.Executor
+	checker  *checker.CheckerService
+}
+
+func NewRunner(exec executor.Executor) *Runner {
+	return &Runner{
+		executor: exec,
+		checker:  checker.NewCheckerService(exec),
+	}
+}
+
+// Program is the submission a Runner runs
+type Program struct {
+	SubmissionID *int
+	LanguageSlug string
+	Version      string // empty means the executor's default version
+	Code         string
+
+	// Language scales the limits; nil runs with the problem's limits as they are
+	Language *domain.Language
+}
+
+// Result is the outcome of a run. A program that doesn't compile has no test
+// results.
+type Result struct {
+	TestResults   []domain.TestCaseResult
+	CompileFailed bool
+	CompileError  string
+	Runtime       int // slowest test, milliseconds
+	Memory        int // peak of any test, kilobytes
+}
+
+// Verdict is the status of the whole run, with how many tests passed: accepted when
+// every test ran and passed, otherwise the verdict of the first test that didn't
+func (r *Result) Verdict(problem *domain.Problem, total int) (domain.SubmissionStatus, string, int) {
+	status, message := domain.SubmissionStatusAccepted, ""
+	passed := 0
+	for i := range r.TestResults {
+		tr := &r.TestResults[i]
+		if tr.Status == domain.TestStatusPassed {
+			passed++
+		} else if status == domain.SubmissionStatusAccepted {
+			status, message = tr.Verdict(problem)
+		}
+	}
+	if status == domain.SubmissionStatusAccepted && passed < total {
+		return domain.SubmissionStatusInternalError, "Not every test was run", passed
+	}
+	return status, message, passed
+}
+
+// Run runs the program on the test cases in order and stops at the first test it
+// doesn't pass, like a contest judge, unless the problem has subtasks, which are
+// scored on every test. Errors wrap checker.ErrCheckerFailed when the
+// problem's checker or interactor is broken and executor.ErrInteractiveUnsupported
+// when the executor can't run interactive problems; any other error comes from the
+// executor.
+func (r *Runner) Run(ctx context.Context, problem *domain.Problem, prog Program, testCases []domain.TestCase) (*Result, error) {
+	result := &Result{TestResults: make([]domain.TestCaseResult, 0, len(testCases))}
+
+	runTest := r.runTest
+	if problem.IsInteractive() {
+		runTest = r.interact
+	}
+
+	for i := range testCases {
+		row, compiled, err := runTest(ctx, problem, prog, &testCases[i])
+		if err != nil {
+			return nil, fmt.Errorf("test %d: %w", i+1, err)
+		}
+		if compiled != nil {
+			result.CompileFailed = true
+			result.CompileError = executor.TruncateOutput(compiled.Error, executor.MaxStoredOutputBytes)
+			result.TestResults = nil
+			return result, nil
+		}
+
+		row.TestID = i + 1
+		result.TestResults = append(result.TestResults, *row)
+		if row.TimeMS > result.Runtime {
+			result.Runtime = row.TimeMS
+		}
+		if row.MemoryKB > result.Memory {
+			result.Memory = row.MemoryKB
+		}
+		if row.Status != domain.TestStatusPassed && !problem.HasSubtasks() {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// inputKeys names the stored input of a test, so that it isn't logged
+func inputKeys(tc *domain.TestCase) []string {
+	if tc.InputKey == nil {
+		return nil
+	}
+	return []string{*tc.InputKey}
+}
+
+// runTest runs one test with its input on stdin. A program that doesn't compile
+// comes back as the failed executor result instead of a row.
+func (r *Runner) runTest(ctx context.Context, problem *domain.Problem, prog Program, tc *domain.TestCase) (*domain.TestCaseResult, *executor.Result, error) {
+	limits := prog.Language.ScaleLimits(tc.Limits(problem))
+	res, err := r.executor.Execute(ctx, &executor.Request{
+		ProblemID:     problem.ID,
+		SubmissionID:  prog.SubmissionID,
+		Language:      prog.LanguageSlug,
+		Version:       prog.Version,
+		Code:          prog.Code,
+		Stdin:         tc.Input,
+		StdinKeys:     inputKeys(tc),
+		TimeLimitMs:   limits.TimeMs,
+		MemoryLimitMb: limits.MemoryMb,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.CompileFailed {
+		return nil, res, nil
+	}
+
+	row := newRow(tc, res)
+	switch {
+	case res.Termination != executor.TerminationNone || res.ExitCode != 0:
+		programFailed(row, res)
+	case problem.UsesChecker():
+		verdict, err := r.checker.Check(ctx, problem, tc, res.Output)
+		if err != nil {
+			return nil, nil, err
+		}
+		row.Status = domain.TestStatusPassed
+		if !verdict.Accepted {
+			row.Status = domain.TestStatusFailed
+			row.Error = verdict.Message
+		}
+	case CompareOutput(tc.ExpectedOutput, res.Output, problem.ValidationType, tc.CompareOptions(problem)):
+		row.Status = domain.TestStatusPassed
+	default:
+		row.Status = domain.TestStatusFailed
+	}
+	return row, nil, nil
+}
+
+func newRow(tc *domain.TestCase, res *executor.Result) *domain.TestCaseResult {
+	row := &domain.TestCaseResult{
+		Input:          tc.Input,
+		ExpectedOutput: tc.ExpectedOutput,
+		ActualOutput:   executor.TruncateOutput(res.Output, executor.MaxStoredTestOutputBytes),
+		TimeMS:         res.Runtime,
+		MemoryKB:       res.Memory,
+		IsSample:       tc.IsSample,
+		Subtask:        tc.Subtask,
+	}
+	// Values from the test-data store are too large to keep with every result
+	if tc.InputKey != nil {
+		row.Input = ""
+	}
+	if tc.ExpectedOutputKey != nil {
+		row.ExpectedOutput = ""
+	}
+	return row
+}
+
+// programFailed marks a row whose program was stopped or exited with an error
+func programFailed(row *domain.TestCaseResult, res *executor.Result) {
+	row.Error = executor.TruncateOutput(res.Error, executor.MaxStoredTestOutputBytes)
+	if res.Termination != executor.TerminationNone {
+		row.Status = res.Termination.TestStatus()
+		return
+	}
+	row.Status = domain.TestStatusRuntimeError
+	if row.Error == "" {
+		row.Error = fmt.Sprintf("exit code %d", res.ExitCode)
+	}
+}
+
+// Outputs runs the program on every test's input without judging what it prints,
+// for computing expected outputs from a reference solution: a test passes when the
+// program ran to completion, and its actual output is all it printed, not cut to
+// what is stored for submissions. Interactive problems have no output of their own
+// and can't be run this way.
+func (r *Runner) Outputs(ctx context.Context, problem *domain.Problem, prog Program, testCases []domain.TestCase) (*Result, error) {
+	if problem.IsInteractive() {
+		return nil, fmt.Errorf("interactive problem %d has no outputs to compute", problem.ID)
+	}
+	result := &Result{TestResults: make([]domain.TestCaseResult, 0, len(testCases))}
+
+	for i := range testCases {
+		tc := &testCases[i]
+		limits := prog.Language.ScaleLimits(tc.Limits(problem))
+		res, err := r.executor.Execute(ctx, &executor.Request{
+			ProblemID:     problem.ID,
+			SubmissionID:  prog.SubmissionID,
+			Language:      prog.LanguageSlug,
+			Version:       prog.Version,
+			Code:          prog.Code,
+			Stdin:         tc.Input,
+			StdinKeys:     inputKeys(tc),
+			TimeLimitMs:   limits.TimeMs,
+			MemoryLimitMb: limits.MemoryMb,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("test %d: %w", i+1, err)
+		}
+		if res.CompileFailed {
+			result.CompileFailed = true
+			result.CompileError = executor.TruncateOutput(res.Error, executor.MaxStoredOutputBytes)
+			result.TestResults = nil
+			return result, nil
+		}
+
+		row := newRow(tc, res)
+		row.TestID = i + 1
+		row.ActualOutput = res.Output
+		row.Status = domain.TestStatusPassed
+		if res.Termination != executor.TerminationNone || res.ExitCode != 0 {
+			programFailed(row, res)
+		}
+		result.TestResults = append(result.TestResults, *row)
+		if row.TimeMS > result.Runtime {
+			result.Runtime = row.TimeMS
+		}
+		if row.MemoryKB > result.Memory {
+			result.Memory = row.MemoryKB
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,213 @@
+package stdio
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
+	"github.com/prabalesh/loco/backend/internal/services/checker"
+)
+
+func stdioProblem() *domain.Problem {
+	return &domain.Problem{ID: 3, JudgeMode: domain.JudgeModeStdio, ValidationType: domain.ValidationTypeTokens, TimeLimit: 1000, MemoryLimit: 128}
+}
+
+func stdioTestCases() []domain.TestCase {
+	return []domain.TestCase{
+		{Input: "1 2\n", ExpectedOutput: "3\n", IsSample: true},
+		{Input: "5 5\n", ExpectedOutput: "10\n"},
+		{Input: "7 1\n", ExpectedOutput: "8\n"},
+	}
+}
+
+// sumProgram answers like a program that adds the two numbers it reads, getting
+// the answer for wrongInput wrong
+func sumProgram(wrongInput string) func(req *executor.Request) (*executor.Result, error) {
+	answers := map[string]string{"1 2\n": "3\n", "5 5\n": "10\n", "7 1\n": "8\n"}
+	return func(req *executor.Request) (*executor.Result, error) {
+		if req.Stdin == wrongInput {
+			return &executor.Result{Output: "0\n", Runtime: 5, Memory: 900}, nil
+		}
+		return &executor.Result{Output: answers[req.Stdin], Runtime: 10, Memory: 1000}, nil
+	}
+}
+
+func TestRunAccepted(t *testing.T) {
+	exec := executor.NewFakeExecutor(sumProgram(""))
+	problem := stdioProblem()
+
+	res, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "python", Code: "print(sum(map(int, input().split())))"}, stdioTestCases())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	status, message, passed := res.Verdict(problem, 3)
+	if status != domain.SubmissionStatusAccepted || message != "" || passed != 3 {
+		t.Errorf("Unexpected verdict %s %q %d", status, message, passed)
+	}
+	if res.Runtime != 10 || res.Memory != 1000 {
+		t.Errorf("Unexpected runtime %d and memory %d", res.Runtime, res.Memory)
+	}
+
+	reqs := exec.Requests()
+	if len(reqs) != 3 {
+		t.Fatalf("Expected 3 runs, got %d", len(reqs))
+	}
+	if reqs[1].Stdin != "5 5\n" || reqs[1].Code != "print(sum(map(int, input().split())))" || reqs[1].TimeLimitMs != 1000 || reqs[1].MemoryLimitMb != 128 {
+		t.Errorf("Unexpected request %+v", reqs[1])
+	}
+}
+
+func TestRunStopsAtFirstFailure(t *testing.T) {
+	exec := executor.NewFakeExecutor(sumProgram("5 5\n"))
+	problem := stdioProblem()
+
+	res, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "python"}, stdioTestCases())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(exec.Requests()) != 2 || len(res.TestResults) != 2 {
+		t.Fatalf("Expected the run to stop at test 2, ran %d", len(exec.Requests()))
+	}
+	status, message, passed := res.Verdict(problem, 3)
+	if status != domain.SubmissionStatusWrongAnswer || message != "Failed on test 2" || passed != 1 {
+		t.Errorf("Unexpected verdict %s %q %d", status, message, passed)
+	}
+	if res.TestResults[1].ActualOutput != "0\n" {
+		t.Errorf("Unexpected actual output %q", res.TestResults[1].ActualOutput)
+	}
+}
+
+func TestRunWithSubtasksRunsEveryTest(t *testing.T) {
+	exec := executor.NewFakeExecutor(sumProgram("5 5\n"))
+	problem := stdioProblem()
+	problem.Subtasks = domain.Subtasks{{Name: "a", Points: 40}, {Name: "b", Points: 60}}
+	testCases := stdioTestCases()
+	testCases[0].Subtask, testCases[1].Subtask, testCases[2].Subtask = "a", "a", "b"
+
+	res, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "python"}, testCases)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(res.TestResults) != 3 || res.TestResults[2].Subtask != "b" {
+		t.Fatalf("Expected every test run and tagged with its subtask, got %+v", res.TestResults)
+	}
+	status, message, passed := res.Verdict(problem, 3)
+	if status != domain.SubmissionStatusWrongAnswer || message != "Failed on test 2" || passed != 2 {
+		t.Errorf("Unexpected verdict %s %q %d", status, message, passed)
+	}
+	if score, _ := problem.Score(status, res.TestResults); score != 60 {
+		t.Errorf("Expected a score of 60, got %d", score)
+	}
+}
+
+func TestRunProgramFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *executor.Result
+		status domain.SubmissionStatus
+	}{
+		{"non-zero exit", &executor.Result{ExitCode: 1, Error: "Traceback"}, domain.SubmissionStatusRuntimeError},
+		{"time limit", &executor.Result{ExitCode: -1, Termination: executor.TerminationTimeLimit}, domain.SubmissionStatusTimeLimitExceeded},
+		{"memory limit", &executor.Result{ExitCode: -1, Termination: executor.TerminationMemoryLimit}, domain.SubmissionStatusMemoryLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+				return tt.result, nil
+			})
+			problem := stdioProblem()
+			res, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "python"}, stdioTestCases())
+			if err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+			if status, _, _ := res.Verdict(problem, 3); status != tt.status {
+				t.Errorf("Expected %s, got %s", tt.status, status)
+			}
+		})
+	}
+}
+
+func TestRunCompileError(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		return &executor.Result{CompileFailed: true, ExitCode: 1, Error: "main.cpp:1: error"}, nil
+	})
+
+	res, err := NewRunner(exec).Run(context.Background(), stdioProblem(), Program{LanguageSlug: "cpp"}, stdioTestCases())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !res.CompileFailed || res.CompileError != "main.cpp:1: error" || len(res.TestResults) != 0 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+}
+
+func TestRunWithChecker(t *testing.T) {
+	checkerLanguage, checkerCode := "python", "checker"
+	problem := stdioProblem()
+	problem.ValidationType = domain.ValidationTypeCustom
+	problem.CheckerLanguage, problem.CheckerCode = &checkerLanguage, &checkerCode
+
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		if req.Code == checkerCode {
+			return &executor.Result{Output: "any order is fine"}, nil
+		}
+		return &executor.Result{Output: "whatever\n"}, nil
+	})
+
+	res, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "python"}, stdioTestCases()[:1])
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if status, _, _ := res.Verdict(problem, 1); status != domain.SubmissionStatusAccepted {
+		t.Errorf("Expected the checker to accept, got %s", status)
+	}
+
+	exec = executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		if req.Code == checkerCode {
+			return &executor.Result{ExitCode: 3}, nil
+		}
+		return &executor.Result{Output: "whatever\n"}, nil
+	})
+	if _, err := NewRunner(exec).Run(context.Background(), problem, Program{LanguageSlug: "python"}, stdioTestCases()); !errors.Is(err, checker.ErrCheckerFailed) {
+		t.Errorf("Expected ErrCheckerFailed, got %v", err)
+	}
+}
+
+func TestOutputsRunsEveryTest(t *testing.T) {
+	exec := executor.NewFakeExecutor(func(req *executor.Request) (*executor.Result, error) {
+		if req.Stdin == "5 5\n" {
+			return &executor.Result{ExitCode: 1, Error: "Traceback"}, nil
+		}
+		return sumProgram("")(req)
+	})
+	tcs := stdioTestCases()
+	for i := range tcs {
+		tcs[i].ExpectedOutput = ""
+	}
+
+	res, err := NewRunner(exec).Outputs(context.Background(), stdioProblem(), Program{LanguageSlug: "python"}, tcs)
+	if err != nil {
+		t.Fatalf("Outputs returned error: %v", err)
+	}
+	if len(res.TestResults) != 3 {
+		t.Fatalf("Expected every test to run, got %d results", len(res.TestResults))
+	}
+	if res.TestResults[0].Status != domain.TestStatusPassed || res.TestResults[0].ActualOutput != "3\n" {
+		t.Errorf("Unexpected result %+v", res.TestResults[0])
+	}
+	if res.TestResults[1].Status != domain.TestStatusRuntimeError || res.TestResults[1].Error != "Traceback" {
+		t.Errorf("Unexpected result %+v", res.TestResults[1])
+	}
+	if res.TestResults[2].Status != domain.TestStatusPassed || res.TestResults[2].ActualOutput != "8\n" || res.TestResults[2].TestID != 3 {
+		t.Errorf("Unexpected result %+v", res.TestResults[2])
+	}
+
+	problem := stdioProblem()
+	problem.JudgeMode = domain.JudgeModeInteractive
+	if _, err := NewRunner(exec).Outputs(context.Background(), problem, Program{LanguageSlug: "python"}, tcs); err == nil {
+		t.Error("Expected interactive problems to be rejected")
+	}
+}
@@ -0,0 +1,134 @@
+package testdata
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/blobstore"
+)
+
+// TestDataService keeps large test inputs and expected outputs out of the database,
+// and with it out of the cached test lists and the execution logs. Values longer
+// than the inline limit are written to a blob store when a test case is saved and
+// read back only when it is run. Samples always stay inline since they are shown
+// with the problem.
+type TestDataService struct {
+	store       blobstore.Store
+	inlineLimit int
+}
+
+// NewTestDataService returns a service offloading values over inlineLimit bytes to
+// store; with a limit of zero or less everything stays in the database, and store
+// is only needed to read what was offloaded before
+func NewTestDataService(store blobstore.Store, inlineLimit int) *TestDataService {
+	return &TestDataService{store: store, inlineLimit: inlineLimit}
+}
+
+// Offload moves the values of a test case that are over the inline limit to the
+// store, and brings those of a sample back inline. Call it whenever a test case's
+// values or sample flag change, before saving it.
+func (s *TestDataService) Offload(ctx context.Context, tc *domain.TestCase) error {
+	if tc.IsSample {
+		return s.inline(ctx, tc)
+	}
+	if err := s.offload(ctx, &tc.Input, &tc.InputKey); err != nil {
+		return fmt.Errorf("failed to store test input: %w", err)
+	}
+	if err := s.offload(ctx, &tc.ExpectedOutput, &tc.ExpectedOutputKey); err != nil {
+		return fmt.Errorf("failed to store expected output: %w", err)
+	}
+	return nil
+}
+
+// offload decides where a value lives; a value already in the store shows up as an
+// empty column with a key and is left there
+func (s *TestDataService) offload(ctx context.Context, value *string, key **string) error {
+	if *value == "" && *key != nil {
+		return nil
+	}
+	*key = nil
+	if s.inlineLimit <= 0 || len(*value) <= s.inlineLimit {
+		return nil
+	}
+
+	stored, err := s.store.Put(ctx, []byte(*value))
+	if err != nil {
+		return err
+	}
+	*value, *key = "", &stored
+	return nil
+}
+
+func (s *TestDataService) inline(ctx context.Context, tc *domain.TestCase) error {
+	loaded, err := s.Load(ctx, []domain.TestCase{*tc})
+	if err != nil {
+		return err
+	}
+	tc.Input, tc.ExpectedOutput = loaded[0].Input, loaded[0].ExpectedOutput
+	tc.InputKey, tc.ExpectedOutputKey = nil, nil
+	return nil
+}
+
+// Load returns the test cases with the values kept in the store filled in. The
+// keys are left set, so runs can tell what not to log. Test cases are copied
+// rather than changed, since lists of them are cached.
+func (s *TestDataService) Load(ctx context.Context, testCases []domain.TestCase) ([]domain.TestCase, error) {
+	var loaded []domain.TestCase
+	for i := range testCases {
+		if testCases[i].InputKey == nil && testCases[i].ExpectedOutputKey == nil {
+			continue
+		}
+		if loaded == nil {
+			loaded = append([]domain.TestCase(nil), testCases...)
+		}
+		tc := &loaded[i]
+		if tc.InputKey != nil {
+			data, err := s.get(ctx, *tc.InputKey)
+			if err != nil {
+				return nil, fmt.Errorf("test case %d: input: %w", tc.ID, err)
+			}
+			tc.Input = data
+		}
+		if tc.ExpectedOutputKey != nil {
+			data, err := s.get(ctx, *tc.ExpectedOutputKey)
+			if err != nil {
+				return nil, fmt.Errorf("test case %d: expected output: %w", tc.ID, err)
+			}
+			tc.ExpectedOutput = data
+		}
+	}
+	if loaded == nil {
+		return testCases, nil
+	}
+	return loaded, nil
+}
+
+func (s *TestDataService) get(ctx context.Context, key string) (string, error) {
+	if s.store == nil {
+		return "", fmt.Errorf("no test data store to read %s from", key)
+	}
+	data, err := blobstore.Get(ctx, s.store, key)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Keys returns the store keys of the values of test cases kept in the store
+func Keys(testCases []domain.TestCase) []string {
+	var keys []string
+	for i := range testCases {
+		keys = append(keys, testCases[i].StoredKeys()...)
+	}
+	return keys
+}
+
+// Open streams a stored value
+func (s *TestDataService) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if s.store == nil {
+		return nil, blobstore.ErrNotFound
+	}
+	return s.store.Open(ctx, key)
+}
@@ -0,0 +1,64 @@
+package testdata
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/blobstore"
+)
+
+func TestOffloadAndLoad(t *testing.T) {
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewTestDataService(store, 16)
+	ctx := context.Background()
+
+	large := strings.Repeat("7 ", 100)
+	tc := domain.TestCase{ID: 1, Input: large, ExpectedOutput: "700"}
+	if err := s.Offload(ctx, &tc); err != nil {
+		t.Fatalf("Offload returned error: %v", err)
+	}
+	if tc.Input != "" || tc.InputKey == nil || *tc.InputKey != blobstore.Key([]byte(large)) {
+		t.Errorf("Expected the input to be stored, got %q under %v", tc.Input, tc.InputKey)
+	}
+	if tc.ExpectedOutput != "700" || tc.ExpectedOutputKey != nil {
+		t.Errorf("Expected the short output to stay inline, got %+v", tc)
+	}
+
+	// Offloading again keeps what is stored
+	if err := s.Offload(ctx, &tc); err != nil || tc.InputKey == nil {
+		t.Errorf("Expected the input to stay stored, got %+v, %v", tc, err)
+	}
+
+	cached := []domain.TestCase{tc, {ID: 2, Input: "1"}}
+	loaded, err := s.Load(ctx, cached)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded[0].Input != large || loaded[0].InputKey == nil || loaded[1].Input != "1" {
+		t.Errorf("Unexpected loaded test cases %+v", loaded)
+	}
+	if cached[0].Input != "" {
+		t.Error("Expected Load to leave its argument alone")
+	}
+
+	// Replacing the value with a short one brings it back inline
+	tc.Input = "7"
+	if err := s.Offload(ctx, &tc); err != nil || tc.InputKey != nil || tc.Input != "7" {
+		t.Errorf("Expected the short input inline, got %+v, %v", tc, err)
+	}
+
+	// Samples are shown with the problem, so they are always inline
+	sample := domain.TestCase{Input: large, IsSample: true}
+	if err := s.Offload(ctx, &sample); err != nil || sample.InputKey != nil || sample.Input != large {
+		t.Errorf("Expected the sample inline, got %+v, %v", sample, err)
+	}
+	tc.Input, tc.InputKey, tc.IsSample = "", cached[0].InputKey, true
+	if err := s.Offload(ctx, &tc); err != nil || tc.InputKey != nil || tc.Input != large {
+		t.Errorf("Expected a stored test turned sample back inline, got %v", err)
+	}
+}
@@ -41,6 +41,9 @@ type ValidateRequest struct {
 	ProblemID    int    `json:"problem_id"`
 	LanguageSlug string `json:"language_slug"`
 	Code         string `json:"code"`
+
+	// Lane is the queue lane the validation job goes to; defaults to the validation lane
+	Lane queue.Lane `json:"-"`
 }
 
 type ValidationResult struct {
@@ -161,7 +164,11 @@ func (s *ValidationService) SaveReferenceSolution(req ValidateRequest, languageI
 
 	// 4. Enqueue Submission
 	ctx := context.Background()
-	if err := s.jobQueue.EnqueueSubmission(ctx, submission.ID); err != nil {
+	lane := req.Lane
+	if lane == "" {
+		lane = queue.LaneValidation
+	}
+	if err := s.jobQueue.EnqueueSubmission(ctx, submission.ID, lane); err != nil {
 		// If queue fails, mark submission as error
 		submission.Status = domain.SubmissionStatusInternalError
 		submission.ErrorMessage = "Failed to enqueue validation job"
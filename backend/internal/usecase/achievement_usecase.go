@@ -13,6 +13,30 @@ import (
 
 const EventAchievementUnlocked = "achievement_unlocked"
 
+// Achievements unlocked by the number of problems solved, overall and by difficulty
+var (
+	solverSlugs  = []string{"solver-i", "solver-ii", "solver-iii", "solver-iv", "solver-v", "solver-vi", "solver-vii", "solver-viii", "solver-ix", "solver-x"}
+	solverCounts = []int{1, 10, 25, 50, 100, 200, 300, 400, 500, 1000}
+
+	difficultySlugs = map[string][]string{
+		"Easy":   {"easy-peasy-i", "easy-peasy-ii", "easy-peasy-iii", "easy-peasy-iv", "easy-peasy-v"},
+		"Medium": {"medium-well-i", "medium-well-ii", "medium-well-iii", "medium-well-iv", "medium-well-v"},
+		"Hard":   {"hard-core-i", "hard-core-ii", "hard-core-iii", "hard-core-iv", "hard-core-v"},
+	}
+	difficultyCounts = []int{10, 50, 100, 200, 500}
+
+	// Achievements unlocked by a submission getting a verdict
+	verdictAchievements = map[string]domain.SubmissionStatus{
+		"bug-hunter":  domain.SubmissionStatusWrongAnswer,
+		"speed-demon": domain.SubmissionStatusTimeLimitExceeded,
+		"memory-leak": domain.SubmissionStatusMemoryLimitExceeded,
+	}
+)
+
+// persistenceAttempts is how many submissions to a problem must come before the one
+// solving it for "persistence"
+const persistenceAttempts = 10
+
 type AchievementUsecase struct {
 	achievementRepo domain.AchievementRepository
 	userRepo        domain.UserRepository
@@ -68,8 +92,7 @@ func (u *AchievementUsecase) CheckAndUnlock(userID int, slug string) error {
 	}
 
 	user.XP += achievement.XPReward
-	// Simple level formula: Level = 1 + (XP / 100)
-	user.Level = 1 + (user.XP / 100)
+	user.Level = domain.LevelForXP(user.XP)
 
 	if err := u.userRepo.Update(user); err != nil {
 		return err
@@ -117,8 +140,6 @@ func (u *AchievementUsecase) EvaluateSubmissionAchievements(submission *domain.S
 		}
 
 		// Problem Solving counts (Solver I-X)
-		solverSlugs := []string{"solver-i", "solver-ii", "solver-iii", "solver-iv", "solver-v", "solver-vi", "solver-vii", "solver-viii", "solver-ix", "solver-x"}
-		solverCounts := []int{1, 10, 25, 50, 100, 200, 300, 400, 500, 1000}
 		for i, count := range solverCounts {
 			if stats.ProblemsSolved >= count {
 				_ = u.CheckAndUnlock(userID, solverSlugs[i])
@@ -127,22 +148,9 @@ func (u *AchievementUsecase) EvaluateSubmissionAchievements(submission *domain.S
 
 		// Difficulty based (Easy Peasy, Medium Well, Hard Core)
 		for _, dist := range stats.SolvedDistribution {
-			var slugs []string
-			var counts []int
-			switch dist.Difficulty {
-			case "Easy":
-				slugs = []string{"easy-peasy-i", "easy-peasy-ii", "easy-peasy-iii", "easy-peasy-iv", "easy-peasy-v"}
-				counts = []int{10, 50, 100, 200, 500}
-			case "Medium":
-				slugs = []string{"medium-well-i", "medium-well-ii", "medium-well-iii", "medium-well-iv", "medium-well-v"}
-				counts = []int{10, 50, 100, 200, 500}
-			case "Hard":
-				slugs = []string{"hard-core-i", "hard-core-ii", "hard-core-iii", "hard-core-iv", "hard-core-v"}
-				counts = []int{10, 50, 100, 200, 500}
-			}
-
-			for i, count := range counts {
-				if dist.Count >= count {
+			slugs := difficultySlugs[dist.Difficulty]
+			for i, count := range difficultyCounts {
+				if i < len(slugs) && dist.Count >= count {
 					_ = u.CheckAndUnlock(userID, slugs[i])
 				}
 			}
@@ -156,16 +164,16 @@ func (u *AchievementUsecase) EvaluateSubmissionAchievements(submission *domain.S
 
 		// Persistence (Solved after 10+ attempts)
 		// We subtract 1 because the current successful submission is included
-		if err == nil && (attempts-1) >= 10 {
+		if err == nil && (attempts-1) >= persistenceAttempts {
 			_ = u.CheckAndUnlock(userID, "persistence")
 		}
 
-	} else if submission.Status == domain.SubmissionStatusWrongAnswer {
-		_ = u.CheckAndUnlock(userID, "bug-hunter")
-	} else if submission.Status == domain.SubmissionStatusTimeLimitExceeded {
-		_ = u.CheckAndUnlock(userID, "speed-demon")
-	} else if submission.Status == domain.SubmissionStatusMemoryLimitExceeded {
-		_ = u.CheckAndUnlock(userID, "memory-leak")
+	} else {
+		for slug, status := range verdictAchievements {
+			if submission.Status == status {
+				_ = u.CheckAndUnlock(userID, slug)
+			}
+		}
 	}
 
 	// 3. Streak based
@@ -180,6 +188,81 @@ func (u *AchievementUsecase) EvaluateSubmissionAchievements(submission *domain.S
 	return nil
 }
 
+// RevokeUnmetAchievements takes back the achievements the user no longer qualifies
+// for, as when a rejudge changed the verdicts they were awarded for, along with the
+// XP they awarded. Streaks count the days the user submitted on, whatever the
+// verdict, so a rejudge leaves them as they are.
+func (u *AchievementUsecase) RevokeUnmetAchievements(userID int, stats *dto.UserStats) error {
+	var unmet []string
+	if stats.AcceptedSubmissions == 0 {
+		unmet = append(unmet, "first-blood")
+	}
+	for i, count := range solverCounts {
+		if stats.ProblemsSolved < count {
+			unmet = append(unmet, solverSlugs[i])
+		}
+	}
+	solved := make(map[string]int, len(stats.SolvedDistribution))
+	for _, dist := range stats.SolvedDistribution {
+		solved[dist.Difficulty] = dist.Count
+	}
+	for difficulty, slugs := range difficultySlugs {
+		for i, count := range difficultyCounts {
+			if i < len(slugs) && solved[difficulty] < count {
+				unmet = append(unmet, slugs[i])
+			}
+		}
+	}
+
+	// Achievements for a verdict or for how a problem was solved
+	counts := map[string]func() (int64, error){
+		"one-shot":    func() (int64, error) { return u.submissionRepo.CountFirstAttemptSolves(userID) },
+		"persistence": func() (int64, error) { return u.submissionRepo.CountSolvesAfterAttempts(userID, persistenceAttempts) },
+	}
+	for slug, status := range verdictAchievements {
+		counts[slug] = func() (int64, error) { return u.submissionRepo.CountByUserStatus(userID, status) }
+	}
+	for slug, count := range counts {
+		n, err := count()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			unmet = append(unmet, slug)
+		}
+	}
+
+	for _, slug := range unmet {
+		if err := u.revoke(userID, slug); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revoke takes back an achievement if the user has it, with its XP
+func (u *AchievementUsecase) revoke(userID int, slug string) error {
+	achievement, err := u.achievementRepo.GetBySlug(slug)
+	if err != nil {
+		return fmt.Errorf("achievement not found: %s", slug)
+	}
+
+	revoked, err := u.achievementRepo.Revoke(userID, achievement)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return nil
+	}
+
+	u.logger.Info("Achievement revoked",
+		zap.Int("user_id", userID),
+		zap.String("slug", slug),
+		zap.Int("xp_revoked", achievement.XPReward),
+	)
+	return nil
+}
+
 func (u *AchievementUsecase) ListAll() ([]domain.Achievement, error) {
 	return u.achievementRepo.GetAll()
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/prabalesh/loco/backend/internal/domain"
 	"github.com/prabalesh/loco/backend/internal/domain/dto"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -16,17 +17,21 @@ type AdminUsecase struct {
 	userRepo            domain.UserRepository
 	submissionRepo      domain.SubmissionRepository
 	pistonExecutionRepo domain.PistonExecutionRepository
+	judgementRepo       domain.SubmissionJudgementRepository
 	problemRepo         domain.ProblemRepository
+	jobQueue            queue.JobQueue
 	redis               *redis.Client
 	logger              *zap.Logger
 }
 
-func NewAdminUsecase(userRepo domain.UserRepository, problemRepo domain.ProblemRepository, submissionRepo domain.SubmissionRepository, pistonExecutionRepo domain.PistonExecutionRepository, redis *redis.Client, logger *zap.Logger) *AdminUsecase {
+func NewAdminUsecase(userRepo domain.UserRepository, problemRepo domain.ProblemRepository, submissionRepo domain.SubmissionRepository, pistonExecutionRepo domain.PistonExecutionRepository, judgementRepo domain.SubmissionJudgementRepository, jobQueue queue.JobQueue, redis *redis.Client, logger *zap.Logger) *AdminUsecase {
 	return &AdminUsecase{
 		userRepo:            userRepo,
 		problemRepo:         problemRepo,
 		submissionRepo:      submissionRepo,
 		pistonExecutionRepo: pistonExecutionRepo,
+		judgementRepo:       judgementRepo,
+		jobQueue:            jobQueue,
 		redis:               redis,
 		logger:              logger,
 	}
@@ -179,9 +184,15 @@ func (u *AdminUsecase) GetAnalytics() (*dto.AdminAnalytics, error) {
 
 	pendingSubmissions, _ := u.submissionRepo.CountPending()
 
-	var queueSize int64
+	var queueSize, deadLetterSize int64
+	laneDepths := make(map[string]int64, len(queue.Lanes))
 	if u.redis != nil {
-		queueSize, _ = u.redis.LLen(context.Background(), "submission:queue").Result()
+		for _, lane := range queue.Lanes {
+			depth, _ := u.redis.LLen(context.Background(), queue.LaneKey(lane)).Result()
+			laneDepths[string(lane)] = depth
+			queueSize += depth
+		}
+		deadLetterSize, _ = u.redis.LLen(context.Background(), queue.SubmissionDeadName).Result()
 	}
 
 	// Calculate oldest pending submission age
@@ -249,6 +260,8 @@ func (u *AdminUsecase) GetAnalytics() (*dto.AdminAnalytics, error) {
 		PendingSubmissions: int(pendingSubmissions),
 		ActiveWorkers:      activeWorkers,
 		QueueSize:          queueSize,
+		QueueLanes:         laneDepths,
+		DeadLetterSize:     deadLetterSize,
 		OldestPendingAge:   oldestAge,
 		QueueHealthStatus:  queueHealthStatus,
 		SubmissionHistory:  dailyStats,
@@ -287,6 +300,30 @@ func (u *AdminUsecase) ListPistonExecutions(page, limit int) ([]domain.PistonExe
 	return executions, total, nil
 }
 
+// ListSubmissionJudgements returns every attempt at judging a submission, oldest
+// first, without their test results
+func (u *AdminUsecase) ListSubmissionJudgements(submissionID int) ([]domain.SubmissionJudgement, error) {
+	if _, err := u.submissionRepo.GetByID(submissionID); err != nil {
+		return nil, errors.New("submission not found")
+	}
+
+	judgements, err := u.judgementRepo.ListBySubmission(submissionID)
+	if err != nil {
+		u.logger.Error("Failed to list judgements", zap.Error(err), zap.Int("submission_id", submissionID))
+		return nil, errors.New("failed to fetch judgements")
+	}
+	return judgements, nil
+}
+
+// GetJudgement returns an attempt at judging a submission with its test results
+func (u *AdminUsecase) GetJudgement(judgementID int) (*domain.SubmissionJudgement, error) {
+	judgement, err := u.judgementRepo.GetByID(judgementID)
+	if err != nil {
+		return nil, errors.New("judgement not found")
+	}
+	return judgement, nil
+}
+
 // ListSubmissions returns a paginated list of all submissions
 func (u *AdminUsecase) ListSubmissions(page, limit int) ([]domain.Submission, int64, error) {
 	if page < 1 {
@@ -311,3 +348,63 @@ func (u *AdminUsecase) ListSubmissions(page, limit int) ([]domain.Submission, in
 
 	return submissions, total, nil
 }
+
+// ListDeadSubmissions returns a page of submission jobs that exhausted their retries
+func (u *AdminUsecase) ListDeadSubmissions(page, limit int) ([]queue.SubmissionJob, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	jobs, total, err := u.jobQueue.ListDeadSubmissions(context.Background(), offset, limit)
+	if err != nil {
+		u.logger.Error("Failed to list dead submissions", zap.Error(err))
+		return nil, 0, errors.New("failed to fetch dead submissions")
+	}
+
+	return jobs, total, nil
+}
+
+// ReplayDeadSubmission resets a dead-lettered submission to pending and enqueues it again
+func (u *AdminUsecase) ReplayDeadSubmission(adminID, submissionID int) error {
+	if _, err := u.submissionRepo.GetByID(submissionID); err != nil {
+		return errors.New("submission not found")
+	}
+
+	// Taking the job off the dead-letter list first makes sure it is replayed once
+	job, err := u.jobQueue.RemoveDeadSubmission(context.Background(), submissionID)
+	if err != nil {
+		if errors.Is(err, queue.ErrJobNotFound) {
+			return errors.New("submission is not in the dead-letter list")
+		}
+		u.logger.Error("Failed to remove dead submission", zap.Error(err), zap.Int("submission_id", submissionID))
+		return errors.New("failed to replay submission")
+	}
+
+	// The worker skips anything that isn't pending. A verdict a worker still holding
+	// the job wrote in the meantime stands, so only the status is reset, and only
+	// while the judge has given up on the submission.
+	reset, err := u.submissionRepo.ResetForReplay(submissionID)
+	if err != nil {
+		u.logger.Error("Failed to reset submission for replay", zap.Error(err), zap.Int("submission_id", submissionID))
+		return errors.New("failed to reset submission")
+	}
+	if !reset {
+		return errors.New("submission was judged since it was dead-lettered")
+	}
+
+	if err := u.jobQueue.EnqueueSubmission(context.Background(), submissionID, job.Lane); err != nil {
+		u.logger.Error("Failed to replay dead submission", zap.Error(err), zap.Int("submission_id", submissionID))
+		return errors.New("failed to replay submission")
+	}
+
+	u.logger.Info("Admin replayed dead submission",
+		zap.Int("admin_id", adminID),
+		zap.Int("submission_id", submissionID),
+	)
+
+	return nil
+}
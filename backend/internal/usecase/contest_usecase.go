@@ -0,0 +1,415 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/domain/dto"
+	"github.com/prabalesh/loco/backend/internal/domain/uerror"
+	"github.com/prabalesh/loco/backend/internal/domain/validator"
+	"go.uber.org/zap"
+)
+
+type ContestUsecase struct {
+	contestRepo       domain.ContestRepository
+	problemRepo       domain.ProblemRepository
+	userRepo          domain.UserRepository
+	submissionRepo    domain.SubmissionRepository
+	submissionUsecase *SubmissionUsecase
+	logger            *zap.Logger
+	now               func() time.Time
+}
+
+func NewContestUsecase(
+	contestRepo domain.ContestRepository,
+	problemRepo domain.ProblemRepository,
+	userRepo domain.UserRepository,
+	submissionRepo domain.SubmissionRepository,
+	submissionUsecase *SubmissionUsecase,
+	logger *zap.Logger,
+) *ContestUsecase {
+	return &ContestUsecase{
+		contestRepo:       contestRepo,
+		problemRepo:       problemRepo,
+		userRepo:          userRepo,
+		submissionRepo:    submissionRepo,
+		submissionUsecase: submissionUsecase,
+		logger:            logger,
+		now:               time.Now,
+	}
+}
+
+// ========== ADMIN OPERATIONS ==========
+
+// CreateContest creates a contest with its problems
+func (u *ContestUsecase) CreateContest(req *dto.CreateContestRequest, adminID int) (*domain.Contest, error) {
+	contest := &domain.Contest{CreatedBy: adminID}
+	if err := u.applyContestRequest(contest, req); err != nil {
+		return nil, err
+	}
+
+	if err := u.contestRepo.Create(contest); err != nil {
+		u.logger.Error("Failed to create contest", zap.Error(err), zap.Int("admin_id", adminID))
+		return nil, errors.New("failed to create contest")
+	}
+
+	u.logger.Info("Contest created successfully",
+		zap.Int("contest_id", contest.ID),
+		zap.String("slug", contest.Slug),
+		zap.Int("created_by", adminID),
+	)
+
+	return u.contestRepo.GetByID(contest.ID)
+}
+
+// UpdateContest replaces a contest's settings and problems
+func (u *ContestUsecase) UpdateContest(contestID int, req *dto.CreateContestRequest) (*domain.Contest, error) {
+	contest, err := u.contestRepo.GetByID(contestID)
+	if err != nil {
+		return nil, errors.New("contest not found")
+	}
+	if err := u.applyContestRequest(contest, req); err != nil {
+		return nil, err
+	}
+
+	if err := u.contestRepo.Update(contest); err != nil {
+		u.logger.Error("Failed to update contest", zap.Error(err), zap.Int("contest_id", contestID))
+		return nil, errors.New("failed to update contest")
+	}
+
+	return u.contestRepo.GetByID(contestID)
+}
+
+// applyContestRequest validates req and sets it on contest
+func (u *ContestUsecase) applyContestRequest(contest *domain.Contest, req *dto.CreateContestRequest) error {
+	if validationErrors := validator.ValidateContestRequest(req); len(validationErrors) > 0 {
+		return &uerror.ValidationError{Errors: validationErrors}
+	}
+
+	slug := strings.TrimSpace(req.Slug)
+	if slug == "" {
+		slug = contest.Slug
+	}
+	if slug == "" {
+		slug = generateSlug(req.Title)
+	}
+	exists, err := u.contestRepo.SlugExists(slug, contest.ID)
+	if err != nil {
+		u.logger.Error("Failed to check contest slug", zap.Error(err), zap.String("slug", slug))
+		return errors.New("failed to save contest")
+	}
+	if exists {
+		return errors.New("contest slug already exists")
+	}
+
+	problems := make([]domain.ContestProblem, len(req.Problems))
+	for i, p := range req.Problems {
+		if _, err := u.problemRepo.GetByID(p.ProblemID); err != nil {
+			return &uerror.ValidationError{Errors: map[string]string{
+				"problems": fmt.Sprintf("problem %d: problem %d not found", i+1, p.ProblemID),
+			}}
+		}
+		points := p.Points
+		if points == 0 {
+			points = 100
+		}
+		problems[i] = domain.ContestProblem{
+			ContestID: contest.ID,
+			ProblemID: p.ProblemID,
+			Label:     strings.TrimSpace(p.Label),
+			Points:    points,
+			Order:     i,
+		}
+	}
+
+	contest.Slug = slug
+	contest.Title = strings.TrimSpace(req.Title)
+	contest.Description = req.Description
+	contest.StartTime = req.StartTime
+	contest.EndTime = req.EndTime
+	contest.Scoring = req.Scoring
+	if contest.Scoring == "" {
+		contest.Scoring = domain.ContestScoringICPC
+	}
+	// Only an omitted penalty gets the default; 0 is a contest without penalties
+	contest.PenaltyMinutes = domain.DefaultContestPenaltyMinutes
+	if req.PenaltyMinutes != nil {
+		contest.PenaltyMinutes = *req.PenaltyMinutes
+	}
+	contest.FreezeMinutes = req.FreezeMinutes
+	contest.Visibility = req.Visibility
+	if contest.Visibility == "" {
+		contest.Visibility = domain.ContestVisibilityPrivate
+	}
+	contest.Problems = problems
+	return nil
+}
+
+// DeleteContest deletes a contest and its registrations; its submissions stay,
+// as ordinary submissions
+func (u *ContestUsecase) DeleteContest(contestID int) error {
+	if _, err := u.contestRepo.GetByID(contestID); err != nil {
+		return errors.New("contest not found")
+	}
+	if err := u.contestRepo.Delete(contestID); err != nil {
+		u.logger.Error("Failed to delete contest", zap.Error(err), zap.Int("contest_id", contestID))
+		return errors.New("failed to delete contest")
+	}
+	return nil
+}
+
+// ListAllContests lists every contest, private ones included
+func (u *ContestUsecase) ListAllContests() ([]domain.Contest, error) {
+	contests, err := u.contestRepo.List("")
+	if err != nil {
+		u.logger.Error("Failed to list contests", zap.Error(err))
+		return nil, errors.New("failed to list contests")
+	}
+	return contests, nil
+}
+
+// AdminGetContest gets a contest with its problems
+func (u *ContestUsecase) AdminGetContest(contestID int) (*domain.Contest, error) {
+	contest, err := u.contestRepo.GetByID(contestID)
+	if err != nil {
+		return nil, errors.New("contest not found")
+	}
+	return contest, nil
+}
+
+// SetUnfrozen reveals the results hidden by the scoreboard freeze, or hides
+// them again
+func (u *ContestUsecase) SetUnfrozen(contestID int, unfrozen bool) error {
+	if _, err := u.contestRepo.GetByID(contestID); err != nil {
+		return errors.New("contest not found")
+	}
+	if err := u.contestRepo.SetUnfrozen(contestID, unfrozen); err != nil {
+		u.logger.Error("Failed to update contest freeze", zap.Error(err), zap.Int("contest_id", contestID))
+		return errors.New("failed to update contest")
+	}
+	return nil
+}
+
+// ListRegistrations lists the users registered to a contest
+func (u *ContestUsecase) ListRegistrations(contestID int) ([]domain.ContestRegistration, error) {
+	if _, err := u.contestRepo.GetByID(contestID); err != nil {
+		return nil, errors.New("contest not found")
+	}
+	registrations, err := u.contestRepo.ListRegistrations(contestID)
+	if err != nil {
+		u.logger.Error("Failed to list registrations", zap.Error(err), zap.Int("contest_id", contestID))
+		return nil, errors.New("failed to list registrations")
+	}
+	return registrations, nil
+}
+
+// RegisterUser registers a user to a contest, which is how users get into
+// private contests
+func (u *ContestUsecase) RegisterUser(contestID int, userID int) error {
+	contest, err := u.contestRepo.GetByID(contestID)
+	if err != nil {
+		return errors.New("contest not found")
+	}
+	if _, err := u.userRepo.GetByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+	return u.register(contest, userID)
+}
+
+// UnregisterUser removes a user from a contest
+func (u *ContestUsecase) UnregisterUser(contestID int, userID int) error {
+	if _, err := u.contestRepo.GetByID(contestID); err != nil {
+		return errors.New("contest not found")
+	}
+	if err := u.contestRepo.Unregister(contestID, userID); err != nil {
+		u.logger.Error("Failed to unregister user", zap.Error(err), zap.Int("contest_id", contestID), zap.Int("user_id", userID))
+		return errors.New("failed to unregister")
+	}
+	return nil
+}
+
+// AdminScoreboard is the scoreboard with every result, freeze or not
+func (u *ContestUsecase) AdminScoreboard(contestID int) (*domain.Scoreboard, error) {
+	contest, err := u.contestRepo.GetByID(contestID)
+	if err != nil {
+		return nil, errors.New("contest not found")
+	}
+	return u.scoreboard(contest, false)
+}
+
+// ========== USER OPERATIONS ==========
+
+// ListContests lists the contests userID can see, which are the public ones for
+// anonymous users
+func (u *ContestUsecase) ListContests(userID int) ([]dto.ContestResponse, error) {
+	var contests []domain.Contest
+	var err error
+	if userID == 0 {
+		contests, err = u.contestRepo.List(domain.ContestVisibilityPublic)
+	} else {
+		contests, err = u.contestRepo.ListForUser(userID)
+	}
+	if err != nil {
+		u.logger.Error("Failed to list contests", zap.Error(err))
+		return nil, errors.New("failed to list contests")
+	}
+
+	now := u.now()
+	responses := make([]dto.ContestResponse, len(contests))
+	for i := range contests {
+		responses[i] = dto.ToContestResponse(&contests[i], now, false)
+		if userID != 0 {
+			responses[i].Registered, _ = u.contestRepo.IsRegistered(contests[i].ID, userID)
+		}
+	}
+	return responses, nil
+}
+
+// GetContest gets a contest by slug; its problems are shown once it starts
+func (u *ContestUsecase) GetContest(slug string, userID int) (*dto.ContestResponse, error) {
+	contest, registered, err := u.visibleContest(slug, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.now()
+	resp := dto.ToContestResponse(contest, now, contest.Status(now) != domain.ContestStatusUpcoming)
+	resp.Registered = registered
+	return &resp, nil
+}
+
+// Register registers userID to a public contest that hasn't ended
+func (u *ContestUsecase) Register(slug string, userID int) error {
+	contest, _, err := u.visibleContest(slug, userID)
+	if err != nil {
+		return err
+	}
+	if contest.Visibility != domain.ContestVisibilityPublic {
+		return errors.New("registration is by invitation only")
+	}
+	return u.register(contest, userID)
+}
+
+func (u *ContestUsecase) register(contest *domain.Contest, userID int) error {
+	if contest.Status(u.now()) == domain.ContestStatusEnded {
+		return errors.New("contest has ended")
+	}
+	registered, err := u.contestRepo.IsRegistered(contest.ID, userID)
+	if err != nil {
+		u.logger.Error("Failed to check registration", zap.Error(err), zap.Int("contest_id", contest.ID))
+		return errors.New("failed to register")
+	}
+	if registered {
+		return errors.New("already registered")
+	}
+
+	if err := u.contestRepo.Register(&domain.ContestRegistration{ContestID: contest.ID, UserID: userID}); err != nil {
+		u.logger.Error("Failed to register user", zap.Error(err), zap.Int("contest_id", contest.ID), zap.Int("user_id", userID))
+		return errors.New("failed to register")
+	}
+
+	u.logger.Info("User registered to contest", zap.Int("contest_id", contest.ID), zap.Int("user_id", userID))
+	return nil
+}
+
+// Unregister withdraws userID from a contest that hasn't started
+func (u *ContestUsecase) Unregister(slug string, userID int) error {
+	contest, registered, err := u.visibleContest(slug, userID)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return errors.New("not registered for this contest")
+	}
+	if contest.Status(u.now()) != domain.ContestStatusUpcoming {
+		return errors.New("contest has started")
+	}
+	if err := u.contestRepo.Unregister(contest.ID, userID); err != nil {
+		u.logger.Error("Failed to unregister user", zap.Error(err), zap.Int("contest_id", contest.ID), zap.Int("user_id", userID))
+		return errors.New("failed to unregister")
+	}
+	return nil
+}
+
+// Submit queues a submission to a problem of a running contest userID is
+// registered to; it is judged like any other submission
+func (u *ContestUsecase) Submit(slug string, problemID int, userID int, req *dto.CreateSubmissionRequest) (*domain.Submission, error) {
+	contest, registered, err := u.visibleContest(slug, userID)
+	if err != nil {
+		return nil, err
+	}
+	if contest.Status(u.now()) != domain.ContestStatusRunning {
+		return nil, errors.New("contest is not running")
+	}
+	if !registered {
+		return nil, errors.New("not registered for this contest")
+	}
+	if contest.Problem(problemID) == nil {
+		return nil, errors.New("problem not in contest")
+	}
+
+	return u.submissionUsecase.SubmitToContest(userID, contest.ID, problemID, req)
+}
+
+// ListMySubmissions lists the submissions userID made in a contest
+func (u *ContestUsecase) ListMySubmissions(slug string, userID int, limit, offset int) ([]domain.Submission, error) {
+	contest, _, err := u.visibleContest(slug, userID)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := u.submissionRepo.ListByContestUser(contest.ID, userID, limit, offset)
+	if err != nil {
+		u.logger.Error("Failed to list contest submissions", zap.Error(err), zap.Int("contest_id", contest.ID))
+		return nil, errors.New("failed to list submissions")
+	}
+	return submissions, nil
+}
+
+// Scoreboard is the public scoreboard, which hides the results of submissions
+// made during the freeze
+func (u *ContestUsecase) Scoreboard(slug string, userID int) (*domain.Scoreboard, error) {
+	contest, _, err := u.visibleContest(slug, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.scoreboard(contest, contest.IsFrozen(u.now()))
+}
+
+func (u *ContestUsecase) scoreboard(contest *domain.Contest, frozen bool) (*domain.Scoreboard, error) {
+	registrations, err := u.contestRepo.ListRegistrations(contest.ID)
+	if err != nil {
+		u.logger.Error("Failed to list registrations", zap.Error(err), zap.Int("contest_id", contest.ID))
+		return nil, errors.New("failed to build scoreboard")
+	}
+	submissions, err := u.submissionRepo.ListByContest(contest.ID)
+	if err != nil {
+		u.logger.Error("Failed to list contest submissions", zap.Error(err), zap.Int("contest_id", contest.ID))
+		return nil, errors.New("failed to build scoreboard")
+	}
+	return domain.BuildScoreboard(contest, registrations, submissions, frozen), nil
+}
+
+// visibleContest gets a contest userID may see, and whether they are registered
+// to it; private contests don't exist for those who aren't
+func (u *ContestUsecase) visibleContest(slug string, userID int) (*domain.Contest, bool, error) {
+	contest, err := u.contestRepo.GetBySlug(slug)
+	if err != nil {
+		return nil, false, errors.New("contest not found")
+	}
+
+	registered := false
+	if userID != 0 {
+		registered, err = u.contestRepo.IsRegistered(contest.ID, userID)
+		if err != nil {
+			u.logger.Error("Failed to check registration", zap.Error(err), zap.Int("contest_id", contest.ID))
+			return nil, false, errors.New("failed to get contest")
+		}
+	}
+	if contest.Visibility != domain.ContestVisibilityPublic && !registered {
+		return nil, false, errors.New("contest not found")
+	}
+	return contest, registered, nil
+}
@@ -2,7 +2,6 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -12,6 +11,7 @@ import (
 	"github.com/prabalesh/loco/backend/internal/domain/dto"
 	"github.com/prabalesh/loco/backend/internal/domain/uerror" // This import is kept because it's used later in the file.
 	"github.com/prabalesh/loco/backend/internal/infrastructure/cache"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
 	"github.com/prabalesh/loco/backend/pkg/config"
 	"github.com/prabalesh/loco/backend/pkg/utils"
 	"go.uber.org/zap"
@@ -25,6 +25,8 @@ type ProblemUsecase struct {
 	categoryRepo       domain.CategoryRepository
 	customTypeRepo     domain.CustomTypeRepository
 	boilerplateService domain.BoilerplateService
+	inputValidator     domain.InputValidator
+	testData           *testdata.TestDataService
 	cache              cache.CacheService
 	cfg                *config.Config
 	logger             *zap.Logger
@@ -38,6 +40,8 @@ func NewProblemUsecase(
 	categoryRepo domain.CategoryRepository,
 	customTypeRepo domain.CustomTypeRepository,
 	boilerplateService domain.BoilerplateService,
+	inputValidator domain.InputValidator,
+	testData *testdata.TestDataService,
 	cacheService cache.CacheService,
 	cfg *config.Config,
 	logger *zap.Logger,
@@ -50,6 +54,8 @@ func NewProblemUsecase(
 		categoryRepo:       categoryRepo,
 		customTypeRepo:     customTypeRepo,
 		boilerplateService: boilerplateService,
+		inputValidator:     inputValidator,
+		testData:           testData,
 		cache:              cacheService,
 		cfg:                cfg,
 		logger:             logger,
@@ -108,9 +114,25 @@ func (u *ProblemUsecase) CreateProblem(req *dto.CreateProblemRequest, adminID in
 		memoryLimit = 256 // Default 256 MB
 	}
 
+	judgeMode := req.JudgeMode
+	if judgeMode == "" {
+		judgeMode = domain.JudgeModeFunction
+	}
+	if !domain.IsValidJudgeMode(judgeMode) {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"judge_mode": "judge_mode must be function, design, stdio or interactive"}}
+	}
+
 	validationType := req.ValidationType
 	if validationType == "" {
 		validationType = "EXACT"
+		if judgeMode == domain.JudgeModeStdio {
+			validationType = domain.ValidationTypeTokens
+		}
+	}
+	if validationType == domain.ValidationTypeCustom && (req.CheckerLanguage == "" || req.CheckerCode == "") {
+		return nil, &uerror.ValidationError{Errors: map[string]string{
+			"checker_code": "checker_language and checker_code are required for CUSTOM validation",
+		}}
 	}
 
 	status := req.Status
@@ -131,6 +153,7 @@ func (u *ProblemUsecase) CreateProblem(req *dto.CreateProblemRequest, adminID in
 		TimeLimit:               timeLimit,
 		MemoryLimit:             memoryLimit,
 		ValidationType:          validationType,
+		JudgeMode:               judgeMode,
 		Status:                  status,
 		Visibility:              visibility,
 		IsActive:                req.IsActive,
@@ -142,9 +165,56 @@ func (u *ProblemUsecase) CreateProblem(req *dto.CreateProblemRequest, adminID in
 		FunctionName:            &req.FunctionName,
 		ReturnType:              &req.ReturnType,
 		Parameters:              req.Parameters,
+		Design:                  req.Design,
 		ExpectedTimeComplexity:  &req.ExpectedTimeComplexity,
 		ExpectedSpaceComplexity: &req.ExpectedSpaceComplexity,
 	}
+	if problem.IsWholeProgram() || problem.IsDesign() {
+		problem.FunctionName = nil
+		problem.ReturnType = nil
+		problem.Parameters = nil
+	}
+	if !problem.IsDesign() {
+		problem.Design = nil
+	} else if _, err := problem.Schema(); err != nil {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"design": "a valid design is required for design problems"}}
+	}
+	if validationType == domain.ValidationTypeCustom {
+		problem.CheckerLanguage = &req.CheckerLanguage
+		problem.CheckerCode = &req.CheckerCode
+	}
+	if problem.IsInteractive() {
+		if req.InteractorLanguage == "" || req.InteractorCode == "" {
+			return nil, &uerror.ValidationError{Errors: map[string]string{
+				"interactor_code": "interactor_language and interactor_code are required for interactive problems",
+			}}
+		}
+		problem.InteractorLanguage = &req.InteractorLanguage
+		problem.InteractorCode = &req.InteractorCode
+		problem.QueryLimit = req.QueryLimit
+	}
+	if req.GeneratorLanguage != "" || req.GeneratorCode != "" {
+		problem.GeneratorLanguage = &req.GeneratorLanguage
+		problem.GeneratorCode = &req.GeneratorCode
+		if !problem.HasGenerator() {
+			return nil, &uerror.ValidationError{Errors: map[string]string{
+				"generator_code": "generator_language and generator_code must be set together",
+			}}
+		}
+	}
+	if req.ValidatorLanguage != "" || req.ValidatorCode != "" {
+		problem.ValidatorLanguage = &req.ValidatorLanguage
+		problem.ValidatorCode = &req.ValidatorCode
+		if !problem.HasValidator() {
+			return nil, &uerror.ValidationError{Errors: map[string]string{
+				"validator_code": "validator_language and validator_code must be set together",
+			}}
+		}
+	}
+	if err := req.Subtasks.Validate(); err != nil {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"subtasks": err.Error()}}
+	}
+	problem.Subtasks = req.Subtasks
 
 	// Map Tags
 	if len(req.TagIDs) > 0 {
@@ -172,7 +242,17 @@ func (u *ProblemUsecase) CreateProblem(req *dto.CreateProblemRequest, adminID in
 
 	// Map TestCases
 	if len(req.TestCases) > 0 {
-		problem.TestCases = u.mapTestCaseInputs(req.TestCases)
+		testCases, err := u.mapTestCaseInputs(problem, req.TestCases)
+		if err != nil {
+			return nil, &uerror.ValidationError{Errors: map[string]string{"test_cases": err.Error()}}
+		}
+		if err := u.checkTestCaseInputs(problem, testCases); err != nil {
+			return nil, err
+		}
+		if err := u.offloadTestCases(testCases); err != nil {
+			return nil, err
+		}
+		problem.TestCases = testCases
 	}
 
 	if err := u.problemRepo.Create(problem); err != nil {
@@ -185,7 +265,7 @@ func (u *ProblemUsecase) CreateProblem(req *dto.CreateProblemRequest, adminID in
 	}
 
 	// V2: Generate boilerplates if signature is provided
-	if problem.FunctionName != nil && problem.Parameters != nil {
+	if (problem.FunctionName != nil && problem.Parameters != nil) || problem.Design != nil {
 		if err := u.boilerplateService.GenerateAllBoilerplatesForProblem(problem); err != nil {
 			u.logger.Warn("Failed to generate boilerplates after creation",
 				zap.Error(err),
@@ -249,6 +329,13 @@ func (u *ProblemUsecase) UpdateProblem(problemID int, req *dto.UpdateProblemRequ
 		problem.ValidationType = *req.ValidationType
 	}
 
+	if req.JudgeMode != nil {
+		if !domain.IsValidJudgeMode(*req.JudgeMode) {
+			return nil, &uerror.ValidationError{Errors: map[string]string{"judge_mode": "judge_mode must be function, design, stdio or interactive"}}
+		}
+		problem.JudgeMode = *req.JudgeMode
+	}
+
 	if req.Status != "" {
 		problem.Status = req.Status
 	}
@@ -285,6 +372,10 @@ func (u *ProblemUsecase) UpdateProblem(problemID int, req *dto.UpdateProblemRequ
 		problem.Parameters = req.Parameters
 	}
 
+	if req.Design != nil {
+		problem.Design = req.Design
+	}
+
 	if req.ExpectedTimeComplexity != nil {
 		problem.ExpectedTimeComplexity = req.ExpectedTimeComplexity
 	}
@@ -293,6 +384,87 @@ func (u *ProblemUsecase) UpdateProblem(problemID int, req *dto.UpdateProblemRequ
 		problem.ExpectedSpaceComplexity = req.ExpectedSpaceComplexity
 	}
 
+	if req.CheckerLanguage != nil {
+		problem.CheckerLanguage = req.CheckerLanguage
+	}
+
+	if req.CheckerCode != nil {
+		problem.CheckerCode = req.CheckerCode
+	}
+
+	if req.InteractorLanguage != nil {
+		problem.InteractorLanguage = req.InteractorLanguage
+	}
+
+	if req.InteractorCode != nil {
+		problem.InteractorCode = req.InteractorCode
+	}
+
+	if req.QueryLimit != nil {
+		problem.QueryLimit = *req.QueryLimit
+	}
+
+	if req.GeneratorLanguage != nil {
+		problem.GeneratorLanguage = req.GeneratorLanguage
+	}
+
+	if req.GeneratorCode != nil {
+		problem.GeneratorCode = req.GeneratorCode
+	}
+
+	// Clearing both removes the generator
+	if (problem.GeneratorLanguage != nil && *problem.GeneratorLanguage != "") != (problem.GeneratorCode != nil && *problem.GeneratorCode != "") {
+		return nil, &uerror.ValidationError{Errors: map[string]string{
+			"generator_code": "generator_language and generator_code must be set together",
+		}}
+	}
+
+	if req.ValidatorLanguage != nil {
+		problem.ValidatorLanguage = req.ValidatorLanguage
+	}
+
+	if req.ValidatorCode != nil {
+		problem.ValidatorCode = req.ValidatorCode
+	}
+
+	// Clearing both removes the validator
+	if (problem.ValidatorLanguage != nil && *problem.ValidatorLanguage != "") != (problem.ValidatorCode != nil && *problem.ValidatorCode != "") {
+		return nil, &uerror.ValidationError{Errors: map[string]string{
+			"validator_code": "validator_language and validator_code must be set together",
+		}}
+	}
+
+	// Test cases of subtasks that no longer exist are worth nothing until moved
+	if req.Subtasks != nil {
+		if err := req.Subtasks.Validate(); err != nil {
+			return nil, &uerror.ValidationError{Errors: map[string]string{"subtasks": err.Error()}}
+		}
+		problem.Subtasks = *req.Subtasks
+	}
+
+	if problem.IsInteractive() && !problem.HasInteractor() {
+		return nil, &uerror.ValidationError{Errors: map[string]string{
+			"interactor_code": "interactor_language and interactor_code are required for interactive problems",
+		}}
+	}
+
+	if problem.ValidationType == domain.ValidationTypeCustom && !problem.UsesChecker() {
+		return nil, &uerror.ValidationError{Errors: map[string]string{
+			"checker_code": "checker_language and checker_code are required for CUSTOM validation",
+		}}
+	}
+
+	if problem.IsWholeProgram() || problem.IsDesign() {
+		problem.FunctionName = nil
+		problem.ReturnType = nil
+		problem.Parameters = nil
+	}
+	if !problem.IsDesign() {
+		problem.Design = nil
+	} else if _, err := problem.Schema(); err != nil {
+		return nil, &uerror.ValidationError{Errors: map[string]string{"design": "a valid design is required for design problems"}}
+	}
+
 	// Map Tags for Update
 	if req.TagIDs != nil {
 		problem.Tags = []domain.Tag{}
@@ -321,7 +493,17 @@ func (u *ProblemUsecase) UpdateProblem(problemID int, req *dto.UpdateProblemRequ
 
 	// Map TestCases for Update
 	if req.TestCases != nil {
-		problem.TestCases = u.mapTestCaseInputs(req.TestCases)
+		testCases, err := u.mapTestCaseInputs(problem, req.TestCases)
+		if err != nil {
+			return nil, &uerror.ValidationError{Errors: map[string]string{"test_cases": err.Error()}}
+		}
+		if err := u.checkTestCaseInputs(problem, testCases); err != nil {
+			return nil, err
+		}
+		if err := u.offloadTestCases(testCases); err != nil {
+			return nil, err
+		}
+		problem.TestCases = testCases
 	}
 
 	if err := u.problemRepo.Update(problem); err != nil {
@@ -332,8 +514,8 @@ func (u *ProblemUsecase) UpdateProblem(problemID int, req *dto.UpdateProblemRequ
 		return nil, errors.New("failed to update problem")
 	}
 
-	// Regenerate boilerplates if signature was updated
-	if req.FunctionName != nil || req.ReturnType != nil || req.Parameters != nil {
+	// Regenerate boilerplates if signature or validation type (baked into the harnesses) was updated
+	if req.FunctionName != nil || req.ReturnType != nil || req.Parameters != nil || req.Design != nil || req.ValidationType != nil || req.JudgeMode != nil {
 		if err := u.boilerplateService.RegenerateBoilerplatesForProblem(problem); err != nil {
 			u.logger.Warn("Failed to regenerate boilerplates after update",
 				zap.Error(err),
@@ -354,16 +536,64 @@ func (u *ProblemUsecase) UpdateProblem(problemID int, req *dto.UpdateProblemRequ
 	return problem, nil
 }
 
-func (u *ProblemUsecase) ValidateTestCases(problemID int, adminID int) error {
-	count, err := u.testcaseRepo.CountByProblemID(problemID)
+// ValidateTestCases checks a problem has enough test cases and reports those whose
+// input breaks the problem's constraints
+func (u *ProblemUsecase) ValidateTestCases(problemID int, adminID int) ([]domain.TestCaseViolation, error) {
+	problem, err := u.problemRepo.GetByID(problemID)
+	if err != nil {
+		return nil, errors.New("problem not found")
+	}
+
+	testCases, err := u.testcaseRepo.GetByProblemID(problemID)
+	if err != nil {
+		return nil, errors.New("failed to get test cases")
+	}
+
+	if len(testCases) < 2 {
+		return nil, errors.New("at least 2 test cases are required")
+	}
+
+	violations, err := u.inputValidator.ValidateTestCases(context.Background(), problem, testCases)
+	if errors.Is(err, domain.ErrValidatorFailed) {
+		return nil, err
+	}
 	if err != nil {
-		return errors.New("failed to count test cases")
+		u.logger.Error("Failed to validate test case inputs", zap.Error(err), zap.Int("problem_id", problemID))
+		return nil, errors.New("failed to validate test cases")
 	}
 
-	if count < 2 {
-		return errors.New("at least 2 test cases are required")
+	return violations, nil
+}
+
+// offloadTestCases moves the large values of test cases to the test-data store
+func (u *ProblemUsecase) offloadTestCases(testCases []domain.TestCase) error {
+	for i := range testCases {
+		if err := u.testData.Offload(context.Background(), &testCases[i]); err != nil {
+			u.logger.Error("Failed to store test data", zap.Error(err))
+			return errors.New("failed to store test data")
+		}
 	}
+	return nil
+}
 
+// checkTestCaseInputs rejects test cases given with a problem whose inputs break
+// its constraints
+func (u *ProblemUsecase) checkTestCaseInputs(problem *domain.Problem, testCases []domain.TestCase) error {
+	violations, err := u.inputValidator.ValidateTestCases(context.Background(), problem, testCases)
+	if errors.Is(err, domain.ErrValidatorFailed) {
+		return &uerror.ValidationError{Errors: map[string]string{"validator_code": err.Error()}}
+	}
+	if err != nil {
+		u.logger.Error("Failed to validate test case inputs", zap.Error(err), zap.Int("problem_id", problem.ID))
+		return errors.New("failed to validate test cases")
+	}
+	if len(violations) > 0 {
+		messages := make([]string, len(violations))
+		for i, v := range violations {
+			messages[i] = fmt.Sprintf("test case %d: %s", v.TestCase, strings.Join(v.Errors, "; "))
+		}
+		return &uerror.ValidationError{Errors: map[string]string{"test_cases": strings.Join(messages, "\n")}}
+	}
 	return nil
 }
 
@@ -487,6 +717,7 @@ func (u *ProblemUsecase) GetProblem(identifier string, userID int) (*domain.Prob
 		)
 		return nil, errors.New("problem not found")
 	}
+	problem.Sanitize()
 
 	// Set cache (1 hour)
 	_ = u.cache.Set(context.Background(), cacheKey, problem, 1*time.Hour)
@@ -588,6 +819,9 @@ func (u *ProblemUsecase) ListProblems(req *dto.ListProblemsRequest, userID int)
 		)
 		return nil, 0, errors.New("failed to retrieve problems")
 	}
+	for _, p := range problems {
+		p.Sanitize()
+	}
 
 	// Cache the result (5 minutes for lists as they are more dynamic)
 	_ = u.cache.Set(context.Background(), cacheKey, cachedList{Problems: problems, Total: total}, 5*time.Minute)
@@ -808,21 +1042,33 @@ func generateSlug(title string) string {
 	return slug
 }
 
-func (u *ProblemUsecase) mapTestCaseInputs(inputs []dto.TestCaseInput) []domain.TestCase {
+func (u *ProblemUsecase) mapTestCaseInputs(problem *domain.Problem, inputs []dto.TestCaseInput) ([]domain.TestCase, error) {
 	var testCases []domain.TestCase
-	for _, input := range inputs {
-		inputJSON, _ := json.Marshal(input.Input)
-		outputJSON, _ := json.Marshal(input.ExpectedOutput)
+	for i, input := range inputs {
+		encodedInput, err := problem.EncodeTestValue(input.Input)
+		if err != nil {
+			return nil, fmt.Errorf("test case %d: input: %w", i+1, err)
+		}
+		encodedOutput, err := problem.EncodeTestValue(input.ExpectedOutput)
+		if err != nil {
+			return nil, fmt.Errorf("test case %d: expected_output: %w", i+1, err)
+		}
+		if input.Subtask != "" && !problem.Subtasks.Has(input.Subtask) {
+			return nil, fmt.Errorf("test case %d: subtask %q is not defined", i+1, input.Subtask)
+		}
 
 		tc := domain.TestCase{
-			Input:          string(inputJSON),
-			ExpectedOutput: string(outputJSON),
+			Input:          encodedInput,
+			ExpectedOutput: encodedOutput,
 			IsSample:       input.IsSample,
 			InputSize:      input.InputSize,
 			TimeLimitMs:    input.TimeLimitMs,
 			MemoryLimitMb:  input.MemoryLimitMb,
+			Subtask:        input.Subtask,
+
+			ValidationConfig: input.ValidationConfig,
 		}
 		testCases = append(testCases, tc)
 	}
-	return testCases
+	return testCases, nil
 }
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prabalesh/loco/backend/internal/domain"
+	"github.com/prabalesh/loco/backend/internal/domain/dto"
+	"github.com/prabalesh/loco/backend/internal/domain/uerror"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/cache"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
+	"go.uber.org/zap"
+)
+
+type RejudgeUsecase struct {
+	rejudgeRepo    domain.RejudgeRepository
+	submissionRepo domain.SubmissionRepository
+	problemRepo    domain.ProblemRepository
+	languageRepo   domain.LanguageRepository
+	jobQueue       queue.JobQueue
+	cache          cache.CacheService
+	logger         *zap.Logger
+}
+
+func NewRejudgeUsecase(
+	rejudgeRepo domain.RejudgeRepository,
+	submissionRepo domain.SubmissionRepository,
+	problemRepo domain.ProblemRepository,
+	languageRepo domain.LanguageRepository,
+	jobQueue queue.JobQueue,
+	cacheService cache.CacheService,
+	logger *zap.Logger,
+) *RejudgeUsecase {
+	return &RejudgeUsecase{
+		rejudgeRepo:    rejudgeRepo,
+		submissionRepo: submissionRepo,
+		problemRepo:    problemRepo,
+		languageRepo:   languageRepo,
+		jobQueue:       jobQueue,
+		cache:          cacheService,
+		logger:         logger,
+	}
+}
+
+// Rejudge judges the submissions picked by req again on the bulk lane, so users'
+// submissions keep priority. Each submission's old verdict is kept in the rejudge's
+// results, and the worker recounts the stats its new verdict changes.
+func (u *RejudgeUsecase) Rejudge(req *dto.RejudgeRequest, adminID int) (*domain.Rejudge, error) {
+	filter := domain.RejudgeFilter{
+		SubmissionID: req.SubmissionID,
+		ProblemID:    req.ProblemID,
+		LanguageID:   req.LanguageID,
+		From:         req.From,
+		To:           req.To,
+	}
+	if err := u.validateFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	submissions, err := u.submissionRepo.ListForRejudge(filter, domain.MaxRejudgeSubmissions+1)
+	if err != nil {
+		u.logger.Error("Failed to list submissions to rejudge", zap.Error(err))
+		return nil, errors.New("failed to rejudge")
+	}
+	if len(submissions) == 0 {
+		return nil, errors.New("no submissions to rejudge")
+	}
+	if len(submissions) > domain.MaxRejudgeSubmissions {
+		return nil, &uerror.ValidationError{Errors: map[string]string{
+			"filter": fmt.Sprintf("more than %d submissions match; narrow it down", domain.MaxRejudgeSubmissions),
+		}}
+	}
+
+	results := make([]domain.RejudgeResult, len(submissions))
+	problemIDs := make(map[int]bool)
+	for i := range submissions {
+		results[i] = domain.NewRejudgeResult(&submissions[i])
+		problemIDs[submissions[i].ProblemID] = true
+	}
+
+	rejudge := &domain.Rejudge{RejudgeFilter: filter, CreatedBy: adminID}
+	if err := u.rejudgeRepo.Create(rejudge, results); err != nil {
+		if errors.Is(err, domain.ErrSubmissionsBeingJudged) {
+			return nil, domain.ErrSubmissionsBeingJudged
+		}
+		u.logger.Error("Failed to create rejudge", zap.Error(err), zap.Int("admin_id", adminID))
+		return nil, errors.New("failed to rejudge")
+	}
+
+	// Rejudges usually follow fixed tests, which the workers may still have cached
+	ctx := context.Background()
+	for problemID := range problemIDs {
+		if err := u.cache.DeleteByPrefix(ctx, fmt.Sprintf("problem:%d:testcases:", problemID)); err != nil {
+			u.logger.Warn("Failed to invalidate test case cache", zap.Error(err), zap.Int("problem_id", problemID))
+		}
+	}
+
+	// The submissions are pending already, so the reaper re-enqueues any that fail here
+	for i := range submissions {
+		if err := u.jobQueue.EnqueueSubmission(ctx, submissions[i].ID, queue.LaneBulk); err != nil {
+			u.logger.Error("Failed to enqueue rejudged submission",
+				zap.Error(err),
+				zap.Int("rejudge_id", rejudge.ID),
+				zap.Int("submission_id", submissions[i].ID),
+			)
+		}
+	}
+
+	u.logger.Info("Rejudge queued",
+		zap.Int("rejudge_id", rejudge.ID),
+		zap.Int("submissions", rejudge.Total),
+		zap.Int("admin_id", adminID),
+	)
+	return rejudge, nil
+}
+
+// RejudgeSubmission judges a single submission again
+func (u *RejudgeUsecase) RejudgeSubmission(submissionID, adminID int) (*domain.Rejudge, error) {
+	if _, err := u.submissionRepo.GetByID(submissionID); err != nil {
+		return nil, errors.New("submission not found")
+	}
+	return u.Rejudge(&dto.RejudgeRequest{SubmissionID: &submissionID}, adminID)
+}
+
+// validateFilter checks the filter picks something short of every submission, and
+// that whatever it names exists
+func (u *RejudgeUsecase) validateFilter(filter *domain.RejudgeFilter) error {
+	validationErrors := make(map[string]string)
+	if filter.IsEmpty() {
+		validationErrors["filter"] = "a submission, problem, language or date range is required"
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		validationErrors["to"] = "must be after from"
+	}
+	if filter.ProblemID != nil {
+		if _, err := u.problemRepo.GetByID(*filter.ProblemID); err != nil {
+			validationErrors["problem_id"] = "problem not found"
+		}
+	}
+	if filter.LanguageID != nil {
+		if _, err := u.languageRepo.GetByID(*filter.LanguageID); err != nil {
+			validationErrors["language_id"] = "language not found"
+		}
+	}
+	if len(validationErrors) > 0 {
+		return &uerror.ValidationError{Errors: validationErrors}
+	}
+	return nil
+}
+
+func (u *RejudgeUsecase) ListRejudges(page, limit int) ([]domain.Rejudge, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	rejudges, total, err := u.rejudgeRepo.List(limit, (page-1)*limit)
+	if err != nil {
+		u.logger.Error("Failed to list rejudges", zap.Error(err))
+		return nil, 0, errors.New("failed to list rejudges")
+	}
+	return rejudges, total, nil
+}
+
+// GetRejudge returns a rejudge with its results, only the submissions whose verdict
+// or score changed if changedOnly is set
+func (u *RejudgeUsecase) GetRejudge(id int, changedOnly bool) (*dto.RejudgeResponse, error) {
+	rejudge, err := u.rejudgeRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("rejudge not found")
+	}
+
+	results, err := u.rejudgeRepo.ListResults(id, changedOnly)
+	if err != nil {
+		u.logger.Error("Failed to list rejudge results", zap.Error(err), zap.Int("rejudge_id", id))
+		return nil, errors.New("failed to get rejudge")
+	}
+	return &dto.RejudgeResponse{Rejudge: *rejudge, Results: results}, nil
+}
+
+// GetVerdictHistory returns the verdicts a submission had before and after each
+// time it was rejudged, oldest first
+func (u *RejudgeUsecase) GetVerdictHistory(submissionID int) ([]domain.RejudgeResult, error) {
+	if _, err := u.submissionRepo.GetByID(submissionID); err != nil {
+		return nil, errors.New("submission not found")
+	}
+
+	history, err := u.rejudgeRepo.ListBySubmission(submissionID)
+	if err != nil {
+		u.logger.Error("Failed to list verdict history", zap.Error(err), zap.Int("submission_id", submissionID))
+		return nil, errors.New("failed to get verdict history")
+	}
+	return history, nil
+}
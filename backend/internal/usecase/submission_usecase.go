@@ -7,7 +7,7 @@ import (
 
 	"github.com/prabalesh/loco/backend/internal/domain"
 	"github.com/prabalesh/loco/backend/internal/domain/dto"
-	"github.com/prabalesh/loco/backend/internal/infrastructure/piston"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
 	"github.com/prabalesh/loco/backend/internal/infrastructure/queue"
 	"github.com/prabalesh/loco/backend/internal/services/execution"
 	"github.com/prabalesh/loco/backend/pkg/config"
@@ -20,7 +20,7 @@ type SubmissionUsecase struct {
 	testCaseRepo        domain.TestCaseRepository
 	languageRepo        domain.LanguageRepository
 	problemLanguageRepo domain.ProblemLanguageRepository
-	pistonService       piston.PistonService
+	executor            executor.Executor
 	executionService    *execution.ExecutionService
 	jobQueue            queue.JobQueue
 	achievementUsecase  *AchievementUsecase
@@ -34,7 +34,7 @@ func NewSubmissionUsecase(
 	testCaseRepo domain.TestCaseRepository,
 	languageRepo domain.LanguageRepository,
 	problemLanguageRepo domain.ProblemLanguageRepository,
-	pistonService piston.PistonService,
+	exec executor.Executor,
 	executionService *execution.ExecutionService,
 	jobQueue queue.JobQueue,
 	achievementUsecase *AchievementUsecase,
@@ -47,7 +47,7 @@ func NewSubmissionUsecase(
 		testCaseRepo:        testCaseRepo,
 		languageRepo:        languageRepo,
 		problemLanguageRepo: problemLanguageRepo,
-		pistonService:       pistonService,
+		executor:            exec,
 		executionService:    executionService,
 		jobQueue:            jobQueue,
 		achievementUsecase:  achievementUsecase,
@@ -57,6 +57,16 @@ func NewSubmissionUsecase(
 }
 
 func (u *SubmissionUsecase) Submit(userID int, problemID int, req *dto.CreateSubmissionRequest) (*domain.Submission, error) {
+	return u.submit(userID, problemID, nil, req)
+}
+
+// SubmitToContest queues a submission made in a contest; the caller checks the
+// user may submit to it
+func (u *SubmissionUsecase) SubmitToContest(userID int, contestID int, problemID int, req *dto.CreateSubmissionRequest) (*domain.Submission, error) {
+	return u.submit(userID, problemID, &contestID, req)
+}
+
+func (u *SubmissionUsecase) submit(userID int, problemID int, contestID *int, req *dto.CreateSubmissionRequest) (*domain.Submission, error) {
 	// 1. Validate Problem and Language
 	_, err := u.problemRepo.GetByID(problemID)
 	if err != nil {
@@ -85,6 +95,7 @@ func (u *SubmissionUsecase) Submit(userID int, problemID int, req *dto.CreateSub
 		FunctionCode: req.Code,
 		Status:       domain.SubmissionStatusPending,
 		QueuedAt:     &now,
+		ContestID:    contestID,
 	}
 
 	if err := u.submissionRepo.Create(submission); err != nil {
@@ -93,7 +104,7 @@ func (u *SubmissionUsecase) Submit(userID int, problemID int, req *dto.CreateSub
 
 	// 4. Enqueue submission job to Redis queue
 	ctx := context.Background()
-	if err := u.jobQueue.EnqueueSubmission(ctx, submission.ID); err != nil {
+	if err := u.jobQueue.EnqueueSubmission(ctx, submission.ID, queue.LaneSubmit); err != nil {
 		u.logger.Error("Failed to enqueue submission",
 			zap.Error(err),
 			zap.Int("submission_id", submission.ID),
@@ -242,6 +253,23 @@ func (u *SubmissionUsecase) GetSubmission(id int) (*domain.Submission, error) {
 	return u.submissionRepo.GetByID(id)
 }
 
+// RankSubmission places a ranked submission among the ranked submissions in its
+// language to the same problem by runtime and memory. Other submissions aren't
+// ranked, and a ranking that can't be worked out is left out rather than failing
+// the request, so both come back nil.
+func (u *SubmissionUsecase) RankSubmission(submission *domain.Submission) *domain.PerformanceRanking {
+	if !submission.IsRanked() {
+		return nil
+	}
+
+	counts, err := u.submissionRepo.GetPerformanceCounts(submission)
+	if err != nil {
+		u.logger.Warn("Failed to rank submission", zap.Error(err), zap.Int("submission_id", submission.ID))
+		return nil
+	}
+	return counts.Rank()
+}
+
 func (u *SubmissionUsecase) GetUserProblemSubmissions(userID int, problemID int, limit, offset int) ([]domain.Submission, int64, error) {
 	submissions, err := u.submissionRepo.ListByUserProblem(userID, problemID, limit, offset)
 	if err != nil {
@@ -407,7 +435,7 @@ func (u *SubmissionUsecase) RunCode(userID int, problemID int, req *dto.RunCodeR
 
 	// 4. Enqueue submission job to Redis queue
 	ctx := context.Background()
-	if err := u.jobQueue.EnqueueSubmission(ctx, submission.ID); err != nil {
+	if err := u.jobQueue.EnqueueSubmission(ctx, submission.ID, queue.LaneRun); err != nil {
 		u.logger.Error("Failed to enqueue run request",
 			zap.Error(err),
 			zap.Int("submission_id", submission.ID),
@@ -1,35 +1,46 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 
 	"github.com/prabalesh/loco/backend/internal/domain"
 	"github.com/prabalesh/loco/backend/internal/domain/dto"
 	"github.com/prabalesh/loco/backend/internal/domain/uerror"
 	"github.com/prabalesh/loco/backend/internal/domain/validator"
+	"github.com/prabalesh/loco/backend/internal/services/testdata"
 
 	"github.com/prabalesh/loco/backend/pkg/config"
 	"go.uber.org/zap"
 )
 
 type TestCaseUsecase struct {
-	testCaseRepo domain.TestCaseRepository
-	problemRepo  domain.ProblemRepository
-	cfg          *config.Config
-	logger       *zap.Logger
+	testCaseRepo   domain.TestCaseRepository
+	problemRepo    domain.ProblemRepository
+	inputValidator domain.InputValidator
+	testData       *testdata.TestDataService
+	cfg            *config.Config
+	logger         *zap.Logger
 }
 
 func NewTestCaseUsecase(
 	testCaseRepo domain.TestCaseRepository,
 	problemRepo domain.ProblemRepository,
+	inputValidator domain.InputValidator,
+	testData *testdata.TestDataService,
 	cfg *config.Config,
 	logger *zap.Logger,
 ) *TestCaseUsecase {
 	return &TestCaseUsecase{
-		testCaseRepo: testCaseRepo,
-		problemRepo:  problemRepo,
-		cfg:          cfg,
-		logger:       logger,
+		testCaseRepo:   testCaseRepo,
+		problemRepo:    problemRepo,
+		inputValidator: inputValidator,
+		testData:       testData,
+		cfg:            cfg,
+		logger:         logger,
 	}
 }
 
@@ -44,7 +55,7 @@ func (u *TestCaseUsecase) CreateTestCase(req *dto.CreateTestCaseRequest, adminID
 	}
 
 	// Verify problem exists
-	_, err := u.problemRepo.GetByID(req.ProblemID)
+	problem, err := u.problemRepo.GetByID(req.ProblemID)
 	if err != nil {
 		u.logger.Warn("Problem not found for test case creation", zap.Int("problem_id", req.ProblemID))
 		return nil, errors.New("problem not found")
@@ -58,6 +69,17 @@ func (u *TestCaseUsecase) CreateTestCase(req *dto.CreateTestCaseRequest, adminID
 		IsSample:         req.IsSample,
 		ValidationConfig: req.ValidationConfig,
 		OrderIndex:       req.OrderIndex,
+		Subtask:          req.Subtask,
+	}
+
+	if err := checkSubtask(problem, testCase); err != nil {
+		return nil, err
+	}
+	if err := u.checkInput(problem, testCase); err != nil {
+		return nil, err
+	}
+	if err := u.offload(testCase); err != nil {
+		return nil, err
 	}
 
 	if err := u.testCaseRepo.Create(testCase); err != nil {
@@ -93,12 +115,14 @@ func (u *TestCaseUsecase) UpdateTestCase(testCaseID int, req *dto.UpdateTestCase
 		return nil, errors.New("test case not found")
 	}
 
-	// Update fields if provided
+	// Update fields if provided; new values replace stored ones
 	if req.Input != "" {
 		testCase.Input = req.Input
+		testCase.InputKey = nil
 	}
 	if req.ExpectedOutput != "" {
 		testCase.ExpectedOutput = req.ExpectedOutput
+		testCase.ExpectedOutputKey = nil
 	}
 	if req.IsSample != nil {
 		testCase.IsSample = *req.IsSample
@@ -109,6 +133,27 @@ func (u *TestCaseUsecase) UpdateTestCase(testCaseID int, req *dto.UpdateTestCase
 	if req.OrderIndex != nil {
 		testCase.OrderIndex = *req.OrderIndex
 	}
+	if req.Subtask != nil {
+		testCase.Subtask = *req.Subtask
+	}
+
+	if req.Input != "" || req.Subtask != nil {
+		problem, err := u.problemRepo.GetByID(testCase.ProblemID)
+		if err != nil {
+			return nil, errors.New("problem not found")
+		}
+		if err := checkSubtask(problem, testCase); err != nil {
+			return nil, err
+		}
+		if req.Input != "" {
+			if err := u.checkInput(problem, testCase); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := u.offload(testCase); err != nil {
+		return nil, err
+	}
 
 	if err := u.testCaseRepo.Update(testCase); err != nil {
 		u.logger.Error("Failed to update test case",
@@ -126,6 +171,44 @@ func (u *TestCaseUsecase) UpdateTestCase(testCaseID int, req *dto.UpdateTestCase
 	return testCase, nil
 }
 
+// checkInput rejects a test case whose input breaks the problem's constraints, or
+// that the problem's validator program cannot judge
+func (u *TestCaseUsecase) checkInput(problem *domain.Problem, testCase *domain.TestCase) error {
+	violations, err := u.inputValidator.ValidateTestCases(context.Background(), problem, []domain.TestCase{*testCase})
+	if errors.Is(err, domain.ErrValidatorFailed) {
+		return &uerror.ValidationError{Errors: map[string]string{"validator_code": err.Error()}}
+	}
+	if err != nil {
+		u.logger.Error("Failed to validate test case input", zap.Error(err), zap.Int("problem_id", problem.ID))
+		return errors.New("failed to validate test case input")
+	}
+	if len(violations) > 0 {
+		u.logger.Warn("Test case input violates constraints",
+			zap.Int("problem_id", problem.ID),
+			zap.Strings("errors", violations[0].Errors),
+		)
+		return &uerror.ValidationError{Errors: map[string]string{"input": strings.Join(violations[0].Errors, "; ")}}
+	}
+	return nil
+}
+
+// checkSubtask rejects a test case of a subtask the problem doesn't have
+func checkSubtask(problem *domain.Problem, testCase *domain.TestCase) error {
+	if testCase.Subtask != "" && !problem.Subtasks.Has(testCase.Subtask) {
+		return &uerror.ValidationError{Errors: map[string]string{"subtask": fmt.Sprintf("subtask %q is not defined", testCase.Subtask)}}
+	}
+	return nil
+}
+
+// offload moves the large values of a test case to the test-data store
+func (u *TestCaseUsecase) offload(testCase *domain.TestCase) error {
+	if err := u.testData.Offload(context.Background(), testCase); err != nil {
+		u.logger.Error("Failed to store test data", zap.Error(err), zap.Int("problem_id", testCase.ProblemID))
+		return errors.New("failed to store test data")
+	}
+	return nil
+}
+
 // DeleteTestCase deletes a specific test case
 func (u *TestCaseUsecase) DeleteTestCase(testCaseID int, adminID int) error {
 	// Verify test case exists
@@ -227,6 +310,34 @@ func (u *TestCaseUsecase) GetTestCase(testCaseID int) (*domain.TestCase, error)
 	return testCase, nil
 }
 
+// OpenTestCaseData streams the input or expected output of a test case, wherever it
+// is kept
+func (u *TestCaseUsecase) OpenTestCaseData(ctx context.Context, testCaseID int, field string) (io.ReadCloser, error) {
+	testCase, err := u.testCaseRepo.GetByID(testCaseID)
+	if err != nil {
+		return nil, errors.New("test case not found")
+	}
+
+	value, key := testCase.Input, testCase.InputKey
+	switch field {
+	case "input":
+	case "expected_output":
+		value, key = testCase.ExpectedOutput, testCase.ExpectedOutputKey
+	default:
+		return nil, &uerror.ValidationError{Errors: map[string]string{"field": "field must be input or expected_output"}}
+	}
+	if key == nil {
+		return io.NopCloser(strings.NewReader(value)), nil
+	}
+
+	data, err := u.testData.Open(ctx, *key)
+	if err != nil {
+		u.logger.Error("Failed to open test data", zap.Error(err), zap.Int("test_case_id", testCaseID))
+		return nil, errors.New("failed to open test data")
+	}
+	return data, nil
+}
+
 // GetTestCasesByProblem gets all test cases for a problem
 func (u *TestCaseUsecase) GetTestCasesByProblem(problemID int, includeSamplesOnly bool) ([]*domain.TestCase, error) {
 	// Verify problem exists and is published/public for user access
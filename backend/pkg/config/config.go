@@ -22,12 +22,38 @@ type Config struct {
 	Email               EmailConfig
 	Log                 LogConfig
 	Worker              WorkerConfig
+	Executor            ExecutorConfig
+	TestData            TestDataConfig
 }
 
 type WorkerConfig struct {
 	MaxConcurrentSubmissions int
 	MaxConcurrentTestCases   int
 	BatchSize                int
+	ProcessingTimeout        time.Duration // how long a dequeued job may run before it is considered abandoned
+	StaleSubmissionAge       time.Duration // how old a pending submission must be before the reaper looks at it
+	ReaperInterval           time.Duration
+	MaxReaps                 int            // how many times a submission is re-enqueued by the reaper before it is failed
+	LaneWeights              map[string]int // queue lane name -> dequeue weight, e.g. "run=8,submit=4"
+}
+
+type ExecutorConfig struct {
+	Driver            string // "piston" or "local"
+	PistonURL         string
+	SandboxDir        string // where the local driver creates per-run directories
+	SandboxNamespaces bool   // run local executions in fresh Linux namespaces
+	MaxOutputBytes    int    // cap on stdout and stderr of a run; 0 disables it
+}
+
+type TestDataConfig struct {
+	Driver           string // "local" or "s3"
+	Dir              string // where the local driver keeps blobs
+	InlineLimitBytes int    // larger test inputs and outputs go to the store; 0 keeps them all in the database
+	S3Endpoint       string // e.g. https://s3.us-east-1.amazonaws.com or a MinIO URL
+	S3Bucket         string
+	S3Region         string
+	S3AccessKey      string
+	S3SecretKey      string
 }
 
 type CORSConfig struct {
@@ -53,7 +79,6 @@ type ServerConfig struct {
 	Port       string
 	Env        string // "development" or "production"
 	AppBaseUrl string
-	PistonURL  string
 }
 
 type DatabaseConfig struct {
@@ -121,7 +146,6 @@ func InitConfig() {
 				Port:       getEnv("PORT", "8080"),
 				Env:        env,
 				AppBaseUrl: getEnv("APP_BASE_URL", "http://localhost:5173"),
-				PistonURL:  getEnv("PISTON_API_URL", "https://emkc.org/api/v2/piston"),
 			},
 			Database: DatabaseConfig{
 				Host:     getEnv("DB_HOST", "localhost"),
@@ -181,6 +205,28 @@ func InitConfig() {
 				MaxConcurrentSubmissions: parseInt("WORKER_MAX_CONCURRENT_SUBMISSIONS", 4),
 				MaxConcurrentTestCases:   parseInt("WORKER_MAX_CONCURRENT_TEST__CASES", 5),
 				BatchSize:                parseInt("WORKER_BATCH_SIZE", 4),
+				ProcessingTimeout:        parseDuration("WORKER_PROCESSING_TIMEOUT", "10m"),
+				StaleSubmissionAge:       parseDuration("WORKER_STALE_SUBMISSION_AGE", "15m"),
+				ReaperInterval:           parseDuration("WORKER_REAPER_INTERVAL", "1m"),
+				MaxReaps:                 parseInt("WORKER_MAX_REAPS", 2),
+				LaneWeights:              parseWeights("WORKER_LANE_WEIGHTS"),
+			},
+			Executor: ExecutorConfig{
+				Driver:            getEnv("EXECUTOR_DRIVER", "piston"),
+				PistonURL:         getEnv("PISTON_API_URL", "http://localhost:2000/api/v2"),
+				SandboxDir:        getEnv("EXECUTOR_SANDBOX_DIR", ""),
+				SandboxNamespaces: getEnv("EXECUTOR_SANDBOX_NAMESPACES", "false") == "true",
+				MaxOutputBytes:    parseInt("EXECUTOR_MAX_OUTPUT_BYTES", 1<<20),
+			},
+			TestData: TestDataConfig{
+				Driver:           getEnv("TEST_DATA_DRIVER", "local"),
+				Dir:              getEnv("TEST_DATA_DIR", "./data/testdata"),
+				InlineLimitBytes: parseInt("TEST_DATA_INLINE_LIMIT_BYTES", 64<<10),
+				S3Endpoint:       getEnv("TEST_DATA_S3_ENDPOINT", ""),
+				S3Bucket:         getEnv("TEST_DATA_S3_BUCKET", ""),
+				S3Region:         getEnv("TEST_DATA_S3_REGION", "us-east-1"),
+				S3AccessKey:      getEnv("TEST_DATA_S3_ACCESS_KEY", ""),
+				S3SecretKey:      getEnv("TEST_DATA_S3_SECRET_KEY", ""),
 			},
 		}
 
@@ -238,6 +284,23 @@ func parseInt(key string, fallback int) int {
 	return intValue
 }
 
+// parseWeights parses "name=weight" pairs separated by commas from environment variable
+func parseWeights(key string) map[string]int {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			log.Fatalf("Invalid weight for %s in %s: %v", name, key, err)
+		}
+		weights[strings.TrimSpace(name)] = weight
+	}
+	return weights
+}
+
 // getDefaultSameSite returns default SameSite policy based on environment
 func getDefaultSameSite(isProduction bool) string {
 	if isProduction {
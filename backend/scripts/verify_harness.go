@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 
 	"github.com/prabalesh/loco/backend/internal/domain"
-	"github.com/prabalesh/loco/backend/internal/infrastructure/piston"
+	"github.com/prabalesh/loco/backend/internal/infrastructure/executor"
 	"github.com/prabalesh/loco/backend/internal/services/codegen"
 	"github.com/prabalesh/loco/backend/pkg/config"
 	"go.uber.org/zap"
@@ -20,12 +21,16 @@ func main() {
 		return
 	}
 	cfg := &config.Config{
-		Server: config.ServerConfig{
-			PistonURL: "http://localhost:2000",
+		Executor: config.ExecutorConfig{
+			Driver:    executor.DriverPiston,
+			PistonURL: "http://localhost:2000/api/v2",
 		},
 	}
-	pistonService := piston.NewPistonService(cfg, nil, logger)
-	cg := codegen.NewCodeGenService(nil)
+	codeExecutor, err := executor.New(cfg, nil, logger)
+	if err != nil {
+		log.Fatalf("Failed to init executor: %v", err)
+	}
+	cg := codegen.NewCodeGenService(nil, nil)
 
 	languages := []struct {
 		slug    string
@@ -66,23 +71,37 @@ func main() {
 		}
 
 		harness, _ := cg.GenerateTestHarness(sig, lang.code, lang.slug, []domain.TestCase{{Input: "[1]", ExpectedOutput: "2"}, {Input: "[2]", ExpectedOutput: "4"}}, "EXACT")
-		testCases := []map[string]interface{}{
-			{"input": []interface{}{1}, "expected": 2},
-			{"input": []interface{}{2}, "expected": 4},
-			{"input": []interface{}{3}, "expected": 6},
+		// Test 2 sleeps for 3s and must time out against the 1s limit
+		problem := &domain.Problem{ID: 1, TimeLimit: 1000, MemoryLimit: 256}
+		nonce := codegen.NewHarnessNonce()
+		testInput, limits, err := codegen.BuildHarnessInput([]domain.TestCase{
+			{Input: "[1]", ExpectedOutput: "2"},
+			{Input: "[2]", ExpectedOutput: "4"},
+			{Input: "[3]", ExpectedOutput: "6"},
+		}, problem, nil, nonce)
+		if err != nil {
+			log.Fatalf("Failed to build harness input: %v", err)
 		}
-		testInput, _ := json.Marshal(testCases)
 
 		fmt.Println("Executing Piston...")
-		res, err := pistonService.Execute(1, nil, lang.slug, lang.version, harness, string(testInput))
+		res, err := codeExecutor.Execute(context.Background(), &executor.Request{
+			ProblemID:     1,
+			Language:      lang.slug,
+			Version:       lang.version,
+			Code:          harness,
+			Stdin:         testInput,
+			TimeLimitMs:   limits.TimeMs,
+			MemoryLimitMb: limits.MemoryMb,
+		})
 		if err != nil {
 			log.Fatalf("Execution failed: %v", err)
 		}
 		fmt.Println("Piston execution finished.")
 
 		fmt.Printf("Output: %s\n", res.Output)
+		_, result := codegen.SplitHarnessOutput(res.Output, nonce)
 		var results []domain.TestCaseResult
-		if err := json.Unmarshal([]byte(res.Output), &results); err != nil {
+		if err := json.Unmarshal([]byte(result), &results); err != nil {
 			fmt.Printf("Failed to parse: %v\n", err)
 			continue
 		}
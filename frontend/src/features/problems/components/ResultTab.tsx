@@ -285,6 +285,14 @@ export const ResultTab = ({ submissionResult, pollingId, runResult, isRunning, s
                                                             {result.actual_output || 'No output'}
                                                         </div>
                                                     </div>
+                                                    {result.stdout && (
+                                                        <div className="space-y-2 md:col-span-3">
+                                                            <div className="text-[10px] font-black text-gray-400 uppercase tracking-widest ml-1">Stdout</div>
+                                                            <div className="bg-gray-50 p-3 rounded-xl border border-gray-100 font-mono text-sm text-gray-700 whitespace-pre-wrap break-all max-h-60 overflow-auto">
+                                                                {result.stdout}
+                                                            </div>
+                                                        </div>
+                                                    )}
                                                 </div>
                                             ) : (
                                                 <div className="py-4 px-6 bg-gray-50 rounded-2xl border border-dashed border-gray-200 text-center">
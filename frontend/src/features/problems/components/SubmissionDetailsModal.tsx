@@ -98,6 +98,12 @@ export const SubmissionDetailsModal = ({ submission, isOpen, onClose }: Submissi
                                         color="green"
                                     />
                                 </div>
+                                {submission.ranking && (
+                                    <p className="mt-4 text-sm text-gray-600 font-medium">
+                                        Faster than {submission.ranking.runtime_percentile}% and lighter than{' '}
+                                        {submission.ranking.memory_percentile}% of {submission.language?.name} submissions
+                                    </p>
+                                )}
                             </div>
 
                             {/* Body */}
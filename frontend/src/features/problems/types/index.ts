@@ -1,5 +1,5 @@
 export type Difficulty = 'easy' | 'medium' | 'hard'
-export type SubmissionStatus = 'Pending' | 'Processing' | 'Accepted' | 'Wrong Answer' | 'Time Limit Exceeded' | 'Memory Limit Exceeded' | 'Runtime Error' | 'Compilation Error' | 'Internal Error'
+export type SubmissionStatus = 'Pending' | 'Processing' | 'Accepted' | 'Wrong Answer' | 'Time Limit Exceeded' | 'Memory Limit Exceeded' | 'Output Limit Exceeded' | 'Runtime Error' | 'Compilation Error' | 'Internal Error'
 
 export interface Problem {
     id: number
@@ -169,12 +169,22 @@ export interface Submission {
     created_at: string
     is_run_only?: boolean
     test_case_results?: TestCaseResult[]
+    ranking?: PerformanceRanking
+}
+
+// Where an accepted submission stands among the accepted submissions in its
+// language to the same problem, e.g. faster than 87% of Python submissions
+export interface PerformanceRanking {
+    runtime_percentile: number
+    memory_percentile: number
+    submissions: number
 }
 
 export interface TestCaseResult {
     input: string
     expected_output: string
     actual_output: string
+    stdout?: string
     status: string
     is_sample: boolean
 }